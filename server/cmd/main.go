@@ -6,17 +6,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata" // food court timezones must resolve on slim images
 
 	"github.com/MohdMusaiyab/infybyte/server/config"
+	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/handlers"
+	"github.com/MohdMusaiyab/infybyte/server/internal/mailer"
+	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/migrations"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/scheduler"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 	"github.com/MohdMusaiyab/infybyte/server/internal/websocket"
 	"github.com/MohdMusaiyab/infybyte/server/routes"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func main() {
@@ -27,22 +36,89 @@ func main() {
 		log.Println("⚠️  No .env file found, relying on system environment variables")
 	}
 
+	if len(os.Args) > 1 {
+		if os.Args[1] != "migrate" {
+			log.Fatalf("Unknown command %q; the only command is migrate", os.Args[1])
+		}
+		client := config.ConnectDB()
+		runMigrations(client.Database(databaseName()))
+		if err := client.Disconnect(context.Background()); err != nil {
+			log.Printf("Error disconnecting DB: %v", err)
+		}
+		return
+	}
+
+	if err := utils.InitJWT(); err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 		log.Println("Settng Gin to Release Mode")
 	}
 
 	client := config.ConnectDB()
-	dbName := os.Getenv("MONGO_DB_NAME")
-	if dbName == "" {
-		dbName = "infybyte"
+	db := client.Database(databaseName())
+	repos := repository.NewMongo(db)
+
+	if os.Getenv("MIGRATE_ON_BOOT") != "false" {
+		runMigrations(db)
 	}
-	db := client.Database(dbName)
 
 	wsHub := websocket.NewHub()
 	go wsHub.Run()
-	utils.SetWebSocketHub(wsHub)
-	wsHandler := handlers.NewWebSocketHandler(wsHub)
+
+	var backplane websocket.Backplane
+	switch os.Getenv("WS_BACKPLANE") {
+	case "mongo":
+		mongoBackplane, err := websocket.NewMongoBackplane(db)
+		if err != nil {
+			log.Fatalf("Failed to start MongoDB backplane: %v", err)
+		}
+		backplane = mongoBackplane
+		log.Println("WebSocket events shared through MongoDB backplane")
+	default:
+		backplane = websocket.NewMemoryBackplane()
+	}
+	if err := backplane.Subscribe(wsHub.Publish); err != nil {
+		log.Fatalf("Failed to subscribe to backplane: %v", err)
+	}
+	utils.SetBackplane(backplane)
+
+	mailSender, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	controllers.SetMailer(mailSender)
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	scheduler.StartFoodCourtHours(schedulerCtx, repos)
+
+	trashRetention := controllers.DefaultTrashRetention
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			log.Fatalf("Invalid TRASH_RETENTION_DAYS %q", value)
+		}
+		trashRetention = time.Duration(days) * 24 * time.Hour
+	}
+	controllers.SetTrashRetention(trashRetention)
+	// Purging deletes in transactions, so a standalone mongod keeps its
+	// trash until it is turned into a replica set.
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 10*time.Second)
+	transactions, err := config.SupportsTransactions(checkCtx, client)
+	cancelCheck()
+	if err == nil && transactions {
+		scheduler.StartTrashPurge(schedulerCtx, db, repos, trashRetention, controllers.PurgeTrash)
+	} else {
+		log.Println("⚠️  Trash purge disabled: MongoDB is not a replica set")
+	}
+	wsHandler := handlers.NewWebSocketHandler(wsHub, repos)
+
+	rateLimiter, err := middlewares.NewRateLimiter(middlewares.NewMemoryRateLimitStore())
+	if err != nil {
+		log.Fatalf("Failed to configure rate limits: %v", err)
+	}
 
 	router := gin.New()
 	router.Use(gin.Logger())
@@ -56,14 +132,14 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{frontendURL},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Upgrade", "Connection", "Sec-WebSocket-Key", "Sec-WebSocket-Version", "Sec-WebSocket-Extensions"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Upgrade", "Connection", "Sec-WebSocket-Key", "Sec-WebSocket-Version", "Sec-WebSocket-Extensions", "Last-Event-ID"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
 		AllowCredentials: true,
 		AllowWebSockets:  true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	routes.InitRoutes(router, db, wsHandler)
+	routes.InitRoutes(router, db, repos, wsHandler, rateLimiter)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -89,6 +165,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Gracefully shutting down server...")
+	stopScheduler()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -97,9 +174,36 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	if err := backplane.Close(); err != nil {
+		log.Printf("Error closing backplane: %v", err)
+	}
+
 	if err := client.Disconnect(ctx); err != nil {
 		log.Printf("Error disconnecting DB: %v", err)
 	}
 
 	log.Println("Server exiting")
 }
+
+func databaseName() string {
+	if dbName := os.Getenv("MONGO_DB_NAME"); dbName != "" {
+		return dbName
+	}
+	return "infybyte"
+}
+
+// runMigrations brings the schema up to date before anything uses it.
+// Index builds can take a while on large collections, hence the long
+// timeout.
+func runMigrations(db *mongo.Database) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	applied, err := migrations.Run(ctx, db)
+	if err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	if applied == 0 {
+		log.Println("Database schema is up to date")
+	}
+}
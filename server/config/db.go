@@ -2,10 +2,12 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -34,6 +36,9 @@ func ConnectDB() *mongo.Client {
 	if err != nil {
 		log.Fatal("Could not ping MongoDB:", err)
 	}
+	if ok, err := SupportsTransactions(ctx, client); err == nil && !ok {
+		log.Println("⚠️  MongoDB is not a replica set: deletes, restores and other transactional writes will answer 503")
+	}
 
 	log.Println("✅ Connected to MongoDB Atlas")
 	MongoClient = client
@@ -43,3 +48,18 @@ func ConnectDB() *mongo.Client {
 func GetCollection(databaseName, collectionName string) *mongo.Collection {
 	return MongoClient.Database(databaseName).Collection(collectionName)
 }
+
+// SupportsTransactions reports whether the deployment can run the
+// multi-document transactions the delete cascade relies on: a replica set
+// member or a mongos can, a standalone mongod cannot.
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, fmt.Errorf("could not check the MongoDB topology: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
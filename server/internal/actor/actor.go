@@ -0,0 +1,108 @@
+// Package actor resolves the vendor or manager profile a request acts
+// through. The middlewares load it once per request and handlers read it
+// from the context instead of looking the profile up again.
+package actor
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+const contextKey = "actor"
+
+var (
+	ErrVendorNotFound       = errors.New("vendor not found")
+	ErrManagerNotFound      = errors.New("manager not found")
+	ErrManagerInactive      = errors.New("manager inactive")
+	ErrFoodCourtNotAssigned = errors.New("food court not assigned to manager")
+)
+
+// Actor is the profile behind an authenticated vendor or manager request.
+type Actor struct {
+	UserID   primitive.ObjectID
+	VendorID primitive.ObjectID
+
+	// Vendor is set for vendors.
+	Vendor *models.Vendor
+
+	// Managers are a manager's records, one per vendor, oldest first. Manager
+	// is the one that runs FoodCourtID, the court the request acts on.
+	Managers    []models.Manager
+	Manager     *models.Manager
+	FoodCourtID primitive.ObjectID
+}
+
+// ForFoodCourt scopes a manager to the record that runs foodCourtID. The zero
+// ID falls back to the first court of the oldest record that has one.
+func (a *Actor) ForFoodCourt(foodCourtID primitive.ObjectID) (*Actor, error) {
+	for i := range a.Managers {
+		manager := &a.Managers[i]
+		courts := manager.Courts()
+		if foodCourtID.IsZero() && len(courts) > 0 {
+			foodCourtID = courts[0]
+		}
+		if manager.RunsCourt(foodCourtID) {
+			scoped := *a
+			scoped.Manager = manager
+			scoped.VendorID = manager.VendorID
+			scoped.FoodCourtID = foodCourtID
+			return &scoped, nil
+		}
+	}
+	return nil, ErrFoodCourtNotAssigned
+}
+
+// Active keeps only the manager's active records.
+func (a *Actor) Active() (*Actor, error) {
+	active := []models.Manager{}
+	for _, manager := range a.Managers {
+		if manager.IsActive {
+			active = append(active, manager)
+		}
+	}
+	if len(active) == 0 {
+		return nil, ErrManagerInactive
+	}
+
+	scoped := *a
+	scoped.Managers = active
+	scoped.Manager = nil
+	return &scoped, nil
+}
+
+func Set(c *gin.Context, a *Actor) {
+	c.Set(contextKey, a)
+}
+
+// From returns the actor the middleware stored, or nil on routes that do
+// not load one.
+func From(c *gin.Context) *Actor {
+	value, exists := c.Get(contextKey)
+	if !exists {
+		return nil
+	}
+	a, _ := value.(*Actor)
+	return a
+}
+
+// RespondError answers a request whose actor could not be loaded or scoped.
+func RespondError(c *gin.Context, err error) {
+	switch err {
+	case ErrVendorNotFound:
+		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
+	case ErrManagerNotFound:
+		utils.RespondError(c, http.StatusForbidden, "Manager not found")
+	case ErrManagerInactive:
+		utils.RespondError(c, http.StatusForbidden, "Inactive managers cannot perform this action")
+	case ErrFoodCourtNotAssigned:
+		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
+	default:
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to load your profile")
+	}
+}
@@ -0,0 +1,135 @@
+package actor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+// CacheTTL is how long a loaded profile is reused. Handlers that change a
+// profile call Invalidate, so the TTL only bounds how stale other instances
+// can be.
+const CacheTTL = 15 * time.Second
+
+type cacheKey struct {
+	kind   string
+	userID primitive.ObjectID
+}
+
+type cacheEntry struct {
+	actor    Actor
+	loadedAt time.Time
+}
+
+var cache = struct {
+	sync.Mutex
+	entries map[cacheKey]cacheEntry
+}{entries: make(map[cacheKey]cacheEntry)}
+
+// Invalidate drops the cached profiles of userID.
+func Invalidate(userID primitive.ObjectID) {
+	cache.Lock()
+	defer cache.Unlock()
+	delete(cache.entries, cacheKey{"vendor", userID})
+	delete(cache.entries, cacheKey{"manager", userID})
+}
+
+// InvalidateAll drops every cached profile, for changes such as trashing a
+// food court that touch profiles of users not known up front.
+func InvalidateAll() {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.entries = make(map[cacheKey]cacheEntry)
+}
+
+// LoadVendor returns the vendor userID owns. Trashed shops are not found.
+func LoadVendor(ctx context.Context, repos *repository.Repositories, userID primitive.ObjectID) (*Actor, error) {
+	return load(cacheKey{"vendor", userID}, func() (Actor, error) {
+		vendor, err := repos.Vendors.FindByUser(ctx, userID)
+		if err == repository.ErrNotFound {
+			return Actor{}, ErrVendorNotFound
+		}
+		if err != nil {
+			return Actor{}, err
+		}
+		return Actor{UserID: userID, VendorID: vendor.ID, Vendor: &vendor}, nil
+	})
+}
+
+// LoadManager returns userID's manager records, without those of trashed
+// vendors and with trashed food courts left out. The actor is not yet
+// scoped to a court; see ForFoodCourt.
+func LoadManager(ctx context.Context, repos *repository.Repositories, userID primitive.ObjectID) (*Actor, error) {
+	return load(cacheKey{"manager", userID}, func() (Actor, error) {
+		records, err := repos.Managers.ListByUser(ctx, userID)
+		if err != nil {
+			return Actor{}, err
+		}
+
+		managers := []models.Manager{}
+		for _, manager := range records {
+			if _, err := repos.Vendors.FindByID(ctx, manager.VendorID); err == repository.ErrNotFound {
+				continue
+			} else if err != nil {
+				return Actor{}, err
+			}
+
+			courts := []primitive.ObjectID{}
+			for _, id := range manager.Courts() {
+				if _, err := repos.FoodCourts.FindByID(ctx, id); err == nil {
+					courts = append(courts, id)
+				} else if err != repository.ErrNotFound {
+					return Actor{}, err
+				}
+			}
+			manager.FoodCourtIDs, manager.FoodCourtID = courts, primitive.NilObjectID
+			managers = append(managers, manager)
+		}
+		if len(managers) == 0 {
+			return Actor{}, ErrManagerNotFound
+		}
+		return Actor{UserID: userID, Managers: managers}, nil
+	})
+}
+
+// load returns a copy of the cached actor, so callers can scope and change
+// it freely. Failed loads are not cached.
+func load(key cacheKey, fetch func() (Actor, error)) (*Actor, error) {
+	now := time.Now()
+
+	cache.Lock()
+	entry, ok := cache.entries[key]
+	cache.Unlock()
+	if ok && now.Sub(entry.loadedAt) < CacheTTL {
+		return entry.actor.clone(), nil
+	}
+
+	a, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	for k, e := range cache.entries {
+		if now.Sub(e.loadedAt) >= CacheTTL {
+			delete(cache.entries, k)
+		}
+	}
+	cache.entries[key] = cacheEntry{actor: a, loadedAt: now}
+	cache.Unlock()
+	return a.clone(), nil
+}
+
+func (a Actor) clone() *Actor {
+	if a.Vendor != nil {
+		vendor := *a.Vendor
+		a.Vendor = &vendor
+	}
+	a.Managers = append([]models.Manager(nil), a.Managers...)
+	return &a
+}
@@ -0,0 +1,623 @@
+// Package cascade deletes records together with everything that depends on
+// them. Each operation runs in a single transaction, so a failure leaves the
+// database untouched, and the hub only hears about changes that committed.
+//
+// Orders and audit logs are history and are never removed. Open orders that
+// lose their vendor, food court or customer are cancelled instead.
+package cascade
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+var ErrNotFound = errors.New("record not found")
+
+// Actor is who asked for the delete. Orders cancelled on the way record it
+// in their history.
+type Actor struct {
+	UserID primitive.ObjectID
+	Role   string
+}
+
+// SystemActor is recorded for deletes made by background jobs.
+var SystemActor = Actor{Role: "system"}
+
+type Service struct {
+	db    *mongo.Database
+	actor Actor
+	then  func(sc mongo.SessionContext, r *Report) error
+}
+
+func New(db *mongo.Database, actor Actor) *Service {
+	return &Service{db: db, actor: actor}
+}
+
+// Then runs fn at the end of every operation's transaction, once the
+// cascade and FormerManagers are settled, so writes that follow from the
+// cascade commit or roll back with it.
+func (s *Service) Then(fn func(sc mongo.SessionContext, r *Report) error) *Service {
+	s.then = fn
+	return s
+}
+
+// DeleteUser removes an account with its vendor profile, the food courts it
+// administers, its manager records, invites, sessions and link tokens.
+func (s *Service) DeleteUser(ctx context.Context, userID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var user models.User
+		if err := s.db.Collection("users").FindOne(sc, bson.M{"_id": userID}).Decode(&user); err != nil {
+			return notFound(err)
+		}
+		return s.deleteUser(sc, r, user)
+	})
+}
+
+// DeleteVendor removes a vendor profile with its items, listings, managers
+// and invites, and takes the vendor out of every food court.
+func (s *Service) DeleteVendor(ctx context.Context, vendorID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var vendor models.Vendor
+		if err := s.db.Collection("vendors").FindOne(sc, bson.M{"_id": vendorID}).Decode(&vendor); err != nil {
+			return notFound(err)
+		}
+		return s.deleteVendor(sc, r, vendor)
+	})
+}
+
+// DeleteFoodCourt removes a food court with its listings and token
+// counters, and takes it away from managers and pending invites.
+func (s *Service) DeleteFoodCourt(ctx context.Context, foodCourtID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var foodCourt models.FoodCourt
+		if err := s.db.Collection("foodcourts").FindOne(sc, bson.M{"_id": foodCourtID}).Decode(&foodCourt); err != nil {
+			return notFound(err)
+		}
+		return s.deleteFoodCourt(sc, r, foodCourt)
+	})
+}
+
+// DeleteItem removes an item and its listings in every food court.
+func (s *Service) DeleteItem(ctx context.Context, itemID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		deleted, err := s.deleteItems(sc, r, bson.M{"_id": itemID})
+		if err == nil && deleted == 0 {
+			err = ErrNotFound
+		}
+		return err
+	})
+}
+
+// RemoveVendorFromFoodCourt takes a vendor out of one food court, along with
+// its listings there and its managers' assignment to the court.
+func (s *Service) RemoveVendorFromFoodCourt(ctx context.Context, foodCourtID, vendorID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var foodCourt models.FoodCourt
+		err := s.db.Collection("foodcourts").FindOne(sc, bson.M{"_id": foodCourtID, "vendor_ids": vendorID}).Decode(&foodCourt)
+		if err != nil {
+			return notFound(err)
+		}
+		return s.removeVendorFromFoodCourt(sc, r, foodCourt, vendorID)
+	})
+}
+
+func (s *Service) run(ctx context.Context, step func(sc mongo.SessionContext, r *Report) error) (*Report, error) {
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var report *Report
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		// The callback is retried on transient errors, so every attempt
+		// starts from an empty report.
+		report = newReport()
+		if err := step(sc, report); err != nil {
+			return nil, err
+		}
+		if err := s.settleManagers(sc, report); err != nil {
+			return nil, err
+		}
+		if s.then != nil {
+			return nil, s.then(sc, report)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.emit()
+	return report, nil
+}
+
+func (s *Service) deleteUser(sc mongo.SessionContext, r *Report, user models.User) error {
+	var vendor models.Vendor
+	err := s.db.Collection("vendors").FindOne(sc, bson.M{"user_id": user.ID}).Decode(&vendor)
+	switch err {
+	case nil:
+		if err := s.deleteVendor(sc, r, vendor); err != nil {
+			return err
+		}
+	case mongo.ErrNoDocuments:
+	default:
+		return err
+	}
+
+	foodCourts, err := s.findFoodCourts(sc, bson.M{"admin_id": user.ID})
+	if err != nil {
+		return err
+	}
+	for _, foodCourt := range foodCourts {
+		if err := s.deleteFoodCourt(sc, r, foodCourt); err != nil {
+			return err
+		}
+	}
+
+	if err := s.deleteManagers(sc, r, bson.M{"user_id": user.ID}); err != nil {
+		return err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(user.Email))
+	result, err := s.db.Collection("manager_invites").DeleteMany(sc, bson.M{"$or": []bson.M{
+		{"user_id": user.ID},
+		{"email": email},
+	}})
+	if err != nil {
+		return err
+	}
+	r.deleted("manager_invites", result.DeletedCount)
+
+	// Sessions are revoked rather than deleted so a stale refresh token is
+	// still recognised and refused with a reason.
+	updated, err := s.db.Collection("refresh_sessions").UpdateMany(sc,
+		bson.M{"user_id": user.ID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now(), "revokedReason": models.SessionRevokedUserDeleted}},
+	)
+	if err != nil {
+		return err
+	}
+	r.updated("refresh_sessions", updated.ModifiedCount)
+
+	result, err = s.db.Collection("auth_tokens").DeleteMany(sc, bson.M{"user_id": user.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("auth_tokens", result.DeletedCount)
+
+	// Same key as the login throttle uses for the email.
+	result, err = s.db.Collection("login_throttles").DeleteMany(sc, bson.M{"_id": "email:" + email})
+	if err != nil {
+		return err
+	}
+	r.deleted("login_throttles", result.DeletedCount)
+
+	if err := s.cancelOrders(sc, r, bson.M{"user_id": user.ID}, "Customer account deleted"); err != nil {
+		return err
+	}
+
+	result, err = s.db.Collection("users").DeleteOne(sc, bson.M{"_id": user.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("users", result.DeletedCount)
+	return nil
+}
+
+func (s *Service) deleteVendor(sc mongo.SessionContext, r *Report, vendor models.Vendor) error {
+	if _, err := s.deleteItems(sc, r, bson.M{"vendor_id": vendor.ID}); err != nil {
+		return err
+	}
+	if err := s.deleteManagers(sc, r, bson.M{"vendor_id": vendor.ID}); err != nil {
+		return err
+	}
+
+	result, err := s.db.Collection("manager_invites").DeleteMany(sc, bson.M{"vendor_id": vendor.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("manager_invites", result.DeletedCount)
+
+	foodCourts, err := s.findFoodCourts(sc, bson.M{"vendor_ids": vendor.ID})
+	if err != nil {
+		return err
+	}
+	if len(foodCourts) > 0 {
+		updated, err := s.db.Collection("foodcourts").UpdateMany(sc,
+			bson.M{"vendor_ids": vendor.ID},
+			bson.M{"$pull": bson.M{"vendor_ids": vendor.ID}, "$set": bson.M{"updatedAt": time.Now()}},
+		)
+		if err != nil {
+			return err
+		}
+		r.updated("foodcourts", updated.ModifiedCount)
+		for _, foodCourt := range foodCourts {
+			r.foodCourtVendorRemoved(foodCourt, vendor)
+		}
+	}
+
+	if err := s.cancelOrders(sc, r, bson.M{"vendor_id": vendor.ID}, "Vendor removed"); err != nil {
+		return err
+	}
+
+	result, err = s.db.Collection("vendors").DeleteOne(sc, bson.M{"_id": vendor.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("vendors", result.DeletedCount)
+	return nil
+}
+
+func (s *Service) deleteFoodCourt(sc mongo.SessionContext, r *Report, foodCourt models.FoodCourt) error {
+	if _, err := s.deleteListings(sc, r, bson.M{"foodcourt_id": foodCourt.ID}); err != nil {
+		return err
+	}
+	if err := s.unassignFoodCourt(sc, r, foodCourt.ID, bson.M{}); err != nil {
+		return err
+	}
+	if err := s.pullInviteFoodCourt(sc, r, foodCourt.ID, bson.M{}); err != nil {
+		return err
+	}
+	if err := s.cancelOrders(sc, r, bson.M{"foodcourt_id": foodCourt.ID}, "Food court closed"); err != nil {
+		return err
+	}
+
+	result, err := s.db.Collection("order_token_counters").DeleteMany(sc, bson.M{"foodcourt_id": foodCourt.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("order_token_counters", result.DeletedCount)
+
+	result, err = s.db.Collection("foodcourts").DeleteOne(sc, bson.M{"_id": foodCourt.ID})
+	if err != nil {
+		return err
+	}
+	r.deleted("foodcourts", result.DeletedCount)
+	r.foodCourtDeleted(foodCourt)
+	return nil
+}
+
+func (s *Service) removeVendorFromFoodCourt(sc mongo.SessionContext, r *Report, foodCourt models.FoodCourt, vendorID primitive.ObjectID) error {
+	itemIDs, err := s.db.Collection("items").Distinct(sc, "_id", bson.M{"vendor_id": vendorID})
+	if err != nil {
+		return err
+	}
+	if len(itemIDs) > 0 {
+		_, err := s.deleteListings(sc, r, bson.M{"foodcourt_id": foodCourt.ID, "item_id": bson.M{"$in": itemIDs}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.unassignFoodCourt(sc, r, foodCourt.ID, bson.M{"vendor_id": vendorID}); err != nil {
+		return err
+	}
+	if err := s.pullInviteFoodCourt(sc, r, foodCourt.ID, bson.M{"vendor_id": vendorID}); err != nil {
+		return err
+	}
+	err = s.cancelOrders(sc, r, bson.M{"vendor_id": vendorID, "foodcourt_id": foodCourt.ID}, "Vendor left the food court")
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.db.Collection("foodcourts").UpdateOne(sc,
+		bson.M{"_id": foodCourt.ID},
+		bson.M{"$pull": bson.M{"vendor_ids": vendorID}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	r.updated("foodcourts", updated.ModifiedCount)
+
+	var vendor models.Vendor
+	if err := s.db.Collection("vendors").FindOne(sc, bson.M{"_id": vendorID}).Decode(&vendor); err != nil {
+		vendor.ID = vendorID
+	}
+	r.foodCourtVendorRemoved(foodCourt, vendor)
+	return nil
+}
+
+// deleteItems removes the matching items and their listings, and reports
+// how many items went.
+func (s *Service) deleteItems(sc mongo.SessionContext, r *Report, filter bson.M) (int64, error) {
+	var items []models.Item
+	if err := s.findAll(sc, "items", filter, &items); err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	itemIDs := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	listings, err := s.deleteListings(sc, r, bson.M{"item_id": bson.M{"$in": itemIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Collection("items").DeleteMany(sc, bson.M{"_id": bson.M{"$in": itemIDs}})
+	if err != nil {
+		return 0, err
+	}
+	r.deleted("items", result.DeletedCount)
+
+	for _, item := range items {
+		foodCourtIDs := []primitive.ObjectID{}
+		for _, listing := range listings {
+			if listing.ItemID == item.ID {
+				foodCourtIDs = append(foodCourtIDs, listing.FoodCourtID)
+			}
+		}
+		r.itemDeleted(item, s.shopName(sc, r, item.VendorID), foodCourtIDs)
+	}
+	return result.DeletedCount, nil
+}
+
+// deleteListings removes the matching itemfoodcourts rows and returns them.
+func (s *Service) deleteListings(sc mongo.SessionContext, r *Report, filter bson.M) ([]models.ItemFoodCourt, error) {
+	var listings []models.ItemFoodCourt
+	if err := s.findAll(sc, "itemfoodcourts", filter, &listings); err != nil {
+		return nil, err
+	}
+	if len(listings) == 0 {
+		return nil, nil
+	}
+
+	listingIDs := make([]primitive.ObjectID, 0, len(listings))
+	itemIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		listingIDs = append(listingIDs, listing.ID)
+		itemIDs = append(itemIDs, listing.ItemID)
+	}
+
+	var items []models.Item
+	if err := s.findAll(sc, "items", bson.M{"_id": bson.M{"$in": itemIDs}}, &items); err != nil {
+		return nil, err
+	}
+	itemsByID := make(map[primitive.ObjectID]models.Item, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	result, err := s.db.Collection("itemfoodcourts").DeleteMany(sc, bson.M{"_id": bson.M{"$in": listingIDs}})
+	if err != nil {
+		return nil, err
+	}
+	r.deleted("itemfoodcourts", result.DeletedCount)
+
+	for _, listing := range listings {
+		item, ok := itemsByID[listing.ItemID]
+		shopName := ""
+		if ok {
+			shopName = s.shopName(sc, r, item.VendorID)
+		}
+		r.listingDeleted(listing, item, shopName)
+	}
+	return listings, nil
+}
+
+// deleteManagers removes the matching manager records.
+func (s *Service) deleteManagers(sc mongo.SessionContext, r *Report, filter bson.M) error {
+	if err := s.noteManagerUsers(sc, r, filter); err != nil {
+		return err
+	}
+
+	result, err := s.db.Collection("managers").DeleteMany(sc, filter)
+	if err != nil {
+		return err
+	}
+	r.deleted("managers", result.DeletedCount)
+	return nil
+}
+
+// unassignFoodCourt takes a food court away from the managers in scope that
+// run it, and deletes the records left without a court. Records that still
+// carry the legacy single foodcourt_id are rewritten to the list form.
+func (s *Service) unassignFoodCourt(sc mongo.SessionContext, r *Report, foodCourtID primitive.ObjectID, scope bson.M) error {
+	filter := bson.M{"$and": []bson.M{scope, {"$or": []bson.M{
+		{"foodcourt_ids": foodCourtID},
+		{"foodcourt_id": foodCourtID},
+	}}}}
+	if err := s.noteManagerUsers(sc, r, filter); err != nil {
+		return err
+	}
+
+	// Only the records trimmed here may be deleted for running out of
+	// courts, so both writes go by the ids matched up front.
+	managers := s.db.Collection("managers")
+	ids, err := managers.Distinct(sc, "_id", filter)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	matched := bson.M{"_id": bson.M{"$in": ids}}
+
+	updated, err := managers.UpdateMany(sc, matched, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"foodcourt_ids": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$foodcourt_ids", bson.A{"$foodcourt_id"}}},
+				"cond":  bson.M{"$ne": bson.A{"$$this", foodCourtID}},
+			}},
+			"updatedAt": time.Now(),
+		}}},
+		{{Key: "$unset", Value: "foodcourt_id"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	deleted, err := managers.DeleteMany(sc, bson.M{"_id": bson.M{"$in": ids}, "foodcourt_ids": bson.M{"$size": 0}})
+	if err != nil {
+		return err
+	}
+	r.updated("managers", updated.ModifiedCount-deleted.DeletedCount)
+	r.deleted("managers", deleted.DeletedCount)
+	return nil
+}
+
+// pullInviteFoodCourt drops a food court from the invites in scope. Pending
+// invites left without a court are revoked; answered ones are kept as they
+// are, as a record of what was offered.
+func (s *Service) pullInviteFoodCourt(sc mongo.SessionContext, r *Report, foodCourtID primitive.ObjectID, scope bson.M) error {
+	invites := s.db.Collection("manager_invites")
+	now := time.Now()
+
+	ids, err := invites.Distinct(sc, "_id", bson.M{"$and": []bson.M{scope, {"foodcourt_ids": foodCourtID}}})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	updated, err := invites.UpdateMany(sc,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$pull": bson.M{"foodcourt_ids": foodCourtID}, "$set": bson.M{"updatedAt": now}},
+	)
+	if err != nil {
+		return err
+	}
+	r.updated("manager_invites", updated.ModifiedCount)
+
+	_, err = invites.UpdateMany(sc,
+		bson.M{"_id": bson.M{"$in": ids}, "foodcourt_ids": bson.M{"$size": 0}, "status": models.ManagerInvitePending},
+		bson.M{"$set": bson.M{"status": models.ManagerInviteRevoked, "updatedAt": now}},
+	)
+	return err
+}
+
+// cancelOrders cancels the matching orders the kitchen has not finished.
+// Ready and collected orders are left alone.
+func (s *Service) cancelOrders(sc mongo.SessionContext, r *Report, filter bson.M, reason string) error {
+	open := []string{}
+	for status := range models.OrderTransitions {
+		if models.CanTransitionOrder(status, models.OrderStatusCancelled, false) {
+			open = append(open, status)
+		}
+	}
+	filter["status"] = bson.M{"$in": open}
+
+	var orders []models.Order
+	if err := s.findAll(sc, "orders", filter, &orders); err != nil {
+		return err
+	}
+
+	collection := s.db.Collection("orders")
+	now := time.Now()
+	for _, order := range orders {
+		change := models.OrderStatusChange{
+			From:      order.Status,
+			To:        models.OrderStatusCancelled,
+			ChangedBy: s.actor.UserID,
+			Role:      s.actor.Role,
+			Reason:    reason,
+			At:        now,
+		}
+		result, err := collection.UpdateOne(sc,
+			bson.M{"_id": order.ID, "status": order.Status},
+			bson.M{
+				"$set":  bson.M{"status": models.OrderStatusCancelled, "updatedAt": now},
+				"$push": bson.M{"history": change},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+
+		order.Status = models.OrderStatusCancelled
+		order.History = append(order.History, change)
+		order.UpdatedAt = now
+		r.updated("orders", 1)
+		r.orderCancelled(order)
+	}
+	return nil
+}
+
+// noteManagerUsers remembers whose manager records are about to change, so
+// settleManagers can tell who is left without any.
+func (s *Service) noteManagerUsers(sc mongo.SessionContext, r *Report, filter bson.M) error {
+	userIDs, err := s.db.Collection("managers").Distinct(sc, "user_id", filter)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			r.managerUsers[oid] = true
+		}
+	}
+	return nil
+}
+
+// settleManagers lists the surviving users who lost their last manager
+// record in FormerManagers.
+func (s *Service) settleManagers(sc mongo.SessionContext, r *Report) error {
+	for userID := range r.managerUsers {
+		remaining, err := s.db.Collection("managers").CountDocuments(sc, bson.M{"user_id": userID})
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			continue
+		}
+		exists, err := s.db.Collection("users").CountDocuments(sc, bson.M{"_id": userID})
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			r.FormerManagers = append(r.FormerManagers, userID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) findFoodCourts(sc mongo.SessionContext, filter bson.M) ([]models.FoodCourt, error) {
+	var foodCourts []models.FoodCourt
+	err := s.findAll(sc, "foodcourts", filter, &foodCourts)
+	return foodCourts, err
+}
+
+func (s *Service) findAll(sc mongo.SessionContext, collection string, filter bson.M, results interface{}) error {
+	cursor, err := s.db.Collection(collection).Find(sc, filter)
+	if err != nil {
+		return err
+	}
+	return cursor.All(sc, results)
+}
+
+func (s *Service) shopName(sc mongo.SessionContext, r *Report, vendorID primitive.ObjectID) string {
+	if name, ok := r.shopNames[vendorID]; ok {
+		return name
+	}
+	var vendor struct {
+		ShopName string `bson:"shopName"`
+	}
+	if err := s.db.Collection("vendors").FindOne(sc, bson.M{"_id": vendorID}).Decode(&vendor); err != nil {
+		return ""
+	}
+	r.shopNames[vendorID] = vendor.ShopName
+	return vendor.ShopName
+}
+
+func notFound(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package cascade
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// Report counts, per collection, the documents a cascade removed, trashed
+// or restored, and the ones it changed to drop a reference (cancelled
+// orders, trimmed manager records, food courts that lost a vendor).
+type Report struct {
+	Deleted  map[string]int64 `json:"deleted,omitempty"`
+	Trashed  map[string]int64 `json:"trashed,omitempty"`
+	Restored map[string]int64 `json:"restored,omitempty"`
+	Updated  map[string]int64 `json:"updated,omitempty"`
+
+	// FormerManagers lost their last manager record. The caller should take
+	// the manager role away from them.
+	FormerManagers []primitive.ObjectID `json:"-"`
+
+	managerUsers map[primitive.ObjectID]bool
+	shopNames    map[primitive.ObjectID]string
+	events       []func()
+}
+
+func newReport() *Report {
+	return &Report{
+		Deleted:      map[string]int64{},
+		Trashed:      map[string]int64{},
+		Restored:     map[string]int64{},
+		Updated:      map[string]int64{},
+		managerUsers: map[primitive.ObjectID]bool{},
+		shopNames:    map[primitive.ObjectID]string{},
+	}
+}
+
+func (r *Report) deleted(collection string, count int64) {
+	if count > 0 {
+		r.Deleted[collection] += count
+	}
+}
+
+func (r *Report) updated(collection string, count int64) {
+	if count > 0 {
+		r.Updated[collection] += count
+	}
+}
+
+func (r *Report) trashed(collection string, count int64) {
+	if count > 0 {
+		r.Trashed[collection] += count
+	}
+}
+
+func (r *Report) restored(collection string, count int64) {
+	if count > 0 {
+		r.Restored[collection] += count
+	}
+}
+
+// merge adds the counts of an emitted report.
+func (r *Report) merge(other *Report) {
+	for collection, count := range other.Deleted {
+		r.deleted(collection, count)
+	}
+	for collection, count := range other.Trashed {
+		r.trashed(collection, count)
+	}
+	for collection, count := range other.Restored {
+		r.restored(collection, count)
+	}
+	for collection, count := range other.Updated {
+		r.updated(collection, count)
+	}
+	r.FormerManagers = append(r.FormerManagers, other.FormerManagers...)
+}
+
+// The events below are captured while the documents can still be read and
+// only sent once the transaction has committed.
+
+func (r *Report) emit() {
+	for _, event := range r.events {
+		event()
+	}
+}
+
+func (r *Report) listingDeleted(listing models.ItemFoodCourt, item models.Item, shopName string) {
+	event := utils.ItemFoodCourtEvent{
+		ItemFoodCourt:  listing,
+		ItemName:       item.Name,
+		Category:       item.Category,
+		IsVeg:          item.IsVeg,
+		BasePrice:      item.BasePrice,
+		EffectivePrice: item.BasePrice,
+		VendorID:       item.VendorID,
+		ShopName:       shopName,
+	}
+	if listing.Price != nil {
+		event.EffectivePrice = *listing.Price
+	}
+	r.events = append(r.events, func() { utils.BroadcastItemFoodCourtUpdate(event, "delete") })
+}
+
+func (r *Report) itemDeleted(item models.Item, shopName string, foodCourtIDs []primitive.ObjectID) {
+	r.itemEvent(item, shopName, foodCourtIDs, "delete")
+}
+
+// itemRestored announces a restored item as newly created, as clients
+// dropped it when it was trashed.
+func (r *Report) itemRestored(item models.Item, shopName string, foodCourtIDs []primitive.ObjectID) {
+	r.itemEvent(item, shopName, foodCourtIDs, "create")
+}
+
+func (r *Report) itemEvent(item models.Item, shopName string, foodCourtIDs []primitive.ObjectID, action string) {
+	event := utils.ItemEvent{Item: item, ShopName: shopName, FoodCourtIDs: foodCourtIDs}
+	r.events = append(r.events, func() { utils.BroadcastItemUpdate(event, action) })
+}
+
+func (r *Report) foodCourtDeleted(foodCourt models.FoodCourt) {
+	r.foodCourtEvent(foodCourt, "delete")
+}
+
+func (r *Report) foodCourtRestored(foodCourt models.FoodCourt) {
+	r.foodCourtEvent(foodCourt, "create")
+}
+
+func (r *Report) foodCourtEvent(foodCourt models.FoodCourt, action string) {
+	event := utils.NewFoodCourtEvent(foodCourt)
+	r.events = append(r.events, func() { utils.BroadcastFoodCourtUpdate(event, action) })
+}
+
+func (r *Report) foodCourtVendorRemoved(foodCourt models.FoodCourt, vendor models.Vendor) {
+	r.foodCourtVendorEvent(foodCourt, vendor, "remove")
+}
+
+func (r *Report) foodCourtVendorAdded(foodCourt models.FoodCourt, vendor models.Vendor) {
+	r.foodCourtVendorEvent(foodCourt, vendor, "add")
+}
+
+func (r *Report) foodCourtVendorEvent(foodCourt models.FoodCourt, vendor models.Vendor, action string) {
+	event := utils.FoodCourtVendorEvent{
+		FoodCourtID:   foodCourt.ID,
+		FoodCourtName: foodCourt.Name,
+		VendorID:      vendor.ID,
+		ShopName:      vendor.ShopName,
+	}
+	r.events = append(r.events, func() { utils.BroadcastFoodCourtVendorUpdate(event, action) })
+}
+
+func (r *Report) orderCancelled(order models.Order) {
+	r.events = append(r.events, func() { utils.BroadcastOrderUpdate(order, order.Status) })
+}
@@ -0,0 +1,408 @@
+package cascade
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// Trash kinds are the collections that are soft-deleted.
+const (
+	TrashUsers      = "users"
+	TrashVendors    = "vendors"
+	TrashItems      = "items"
+	TrashFoodCourts = "foodcourts"
+)
+
+// TrashKinds lists the kinds in the order Purge removes them, dependents
+// before what they hang off.
+var TrashKinds = []string{TrashItems, TrashFoodCourts, TrashVendors, TrashUsers}
+
+var (
+	ErrParentTrashed = errors.New("record belongs to a trashed record")
+	ErrRestoreTaken  = errors.New("a live record already takes its place")
+)
+
+// Trashing stamps a record, and the records that cannot exist without it,
+// with the same deletedAt and deletedBy. Restore brings back exactly that
+// batch. Listings, manager records and invites are left in place and are
+// hidden through their trashed parent. Open orders are cancelled, as nobody
+// can fulfil them any more; a restore does not reopen them.
+
+// TrashUser soft-deletes an account with its vendor profile and the food
+// courts it administers, and ends its sessions.
+func (s *Service) TrashUser(ctx context.Context, userID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var user models.User
+		if err := s.db.Collection("users").FindOne(sc, models.NotDeleted(bson.M{"_id": userID})).Decode(&user); err != nil {
+			return notFound(err)
+		}
+		return s.trashUser(sc, r, user, trashTime())
+	})
+}
+
+// TrashVendor soft-deletes a vendor profile and its items.
+func (s *Service) TrashVendor(ctx context.Context, vendorID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var vendor models.Vendor
+		if err := s.db.Collection("vendors").FindOne(sc, models.NotDeleted(bson.M{"_id": vendorID})).Decode(&vendor); err != nil {
+			return notFound(err)
+		}
+		return s.trashVendor(sc, r, vendor, trashTime())
+	})
+}
+
+func (s *Service) TrashFoodCourt(ctx context.Context, foodCourtID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var foodCourt models.FoodCourt
+		if err := s.db.Collection("foodcourts").FindOne(sc, models.NotDeleted(bson.M{"_id": foodCourtID})).Decode(&foodCourt); err != nil {
+			return notFound(err)
+		}
+		return s.trashFoodCourt(sc, r, foodCourt, trashTime())
+	})
+}
+
+func (s *Service) TrashItem(ctx context.Context, itemID primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		var item models.Item
+		if err := s.db.Collection("items").FindOne(sc, models.NotDeleted(bson.M{"_id": itemID})).Decode(&item); err != nil {
+			return notFound(err)
+		}
+		return s.trashItems(sc, r, bson.M{"_id": item.ID}, trashTime())
+	})
+}
+
+// Restore brings a trashed record back together with everything trashed in
+// the same batch. A record whose parent is still trashed cannot be restored
+// on its own.
+func (s *Service) Restore(ctx context.Context, kind string, id primitive.ObjectID) (*Report, error) {
+	return s.run(ctx, func(sc mongo.SessionContext, r *Report) error {
+		switch kind {
+		case TrashUsers:
+			var user models.User
+			if err := s.findTrashed(sc, kind, id, &user); err != nil {
+				return err
+			}
+			return s.restoreUser(sc, r, user)
+		case TrashVendors:
+			var vendor models.Vendor
+			if err := s.findTrashed(sc, kind, id, &vendor); err != nil {
+				return err
+			}
+			if err := s.requireLive(sc, "users", vendor.UserID); err != nil {
+				return err
+			}
+			return s.restoreVendor(sc, r, vendor)
+		case TrashFoodCourts:
+			var foodCourt models.FoodCourt
+			if err := s.findTrashed(sc, kind, id, &foodCourt); err != nil {
+				return err
+			}
+			if err := s.requireLive(sc, "users", foodCourt.AdminID); err != nil {
+				return err
+			}
+			return s.restoreFoodCourts(sc, r, bson.M{"_id": foodCourt.ID}, trashBatch(foodCourt.DeletedAt, foodCourt.DeletedBy))
+		case TrashItems:
+			var item models.Item
+			if err := s.findTrashed(sc, kind, id, &item); err != nil {
+				return err
+			}
+			if err := s.requireLive(sc, "vendors", item.VendorID); err != nil {
+				return err
+			}
+			return s.restoreItems(sc, r, bson.M{"_id": item.ID}, trashBatch(item.DeletedAt, item.DeletedBy))
+		}
+		return ErrNotFound
+	})
+}
+
+// Purge hard-deletes everything trashed before the cutoff, one record and
+// one transaction at a time, with the same rules as the Delete methods.
+func (s *Service) Purge(ctx context.Context, before time.Time) (*Report, error) {
+	total := newReport()
+	for _, kind := range TrashKinds {
+		ids, err := s.db.Collection(kind).Distinct(ctx, "_id", bson.M{"deletedAt": bson.M{"$lt": before}})
+		if err != nil {
+			return total, err
+		}
+
+		for _, id := range ids {
+			oid, ok := id.(primitive.ObjectID)
+			if !ok {
+				continue
+			}
+
+			var report *Report
+			switch kind {
+			case TrashItems:
+				report, err = s.DeleteItem(ctx, oid)
+			case TrashFoodCourts:
+				report, err = s.DeleteFoodCourt(ctx, oid)
+			case TrashVendors:
+				report, err = s.DeleteVendor(ctx, oid)
+			case TrashUsers:
+				report, err = s.DeleteUser(ctx, oid)
+			}
+			// A record can go with a parent purged earlier in the run.
+			if err == ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return total, err
+			}
+			total.merge(report)
+		}
+	}
+	return total, nil
+}
+
+func (s *Service) trashUser(sc mongo.SessionContext, r *Report, user models.User, at time.Time) error {
+	var vendor models.Vendor
+	err := s.db.Collection("vendors").FindOne(sc, models.NotDeleted(bson.M{"user_id": user.ID})).Decode(&vendor)
+	switch err {
+	case nil:
+		if err := s.trashVendor(sc, r, vendor, at); err != nil {
+			return err
+		}
+	case mongo.ErrNoDocuments:
+	default:
+		return err
+	}
+
+	foodCourts, err := s.findFoodCourts(sc, models.NotDeleted(bson.M{"admin_id": user.ID}))
+	if err != nil {
+		return err
+	}
+	for _, foodCourt := range foodCourts {
+		if err := s.trashFoodCourt(sc, r, foodCourt, at); err != nil {
+			return err
+		}
+	}
+
+	updated, err := s.db.Collection("refresh_sessions").UpdateMany(sc,
+		bson.M{"user_id": user.ID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": at, "revokedReason": models.SessionRevokedUserDeleted}},
+	)
+	if err != nil {
+		return err
+	}
+	r.updated("refresh_sessions", updated.ModifiedCount)
+
+	if err := s.cancelOrders(sc, r, bson.M{"user_id": user.ID}, "Customer account deleted"); err != nil {
+		return err
+	}
+	return s.stamp(sc, r, "users", bson.M{"_id": user.ID}, at)
+}
+
+func (s *Service) trashVendor(sc mongo.SessionContext, r *Report, vendor models.Vendor, at time.Time) error {
+	if err := s.trashItems(sc, r, bson.M{"vendor_id": vendor.ID}, at); err != nil {
+		return err
+	}
+	if err := s.cancelOrders(sc, r, bson.M{"vendor_id": vendor.ID}, "Vendor removed"); err != nil {
+		return err
+	}
+
+	foodCourts, err := s.findFoodCourts(sc, models.NotDeleted(bson.M{"vendor_ids": vendor.ID}))
+	if err != nil {
+		return err
+	}
+	for _, foodCourt := range foodCourts {
+		r.foodCourtVendorRemoved(foodCourt, vendor)
+	}
+	return s.stamp(sc, r, "vendors", bson.M{"_id": vendor.ID}, at)
+}
+
+func (s *Service) trashFoodCourt(sc mongo.SessionContext, r *Report, foodCourt models.FoodCourt, at time.Time) error {
+	if err := s.cancelOrders(sc, r, bson.M{"foodcourt_id": foodCourt.ID}, "Food court closed"); err != nil {
+		return err
+	}
+	if err := s.stamp(sc, r, "foodcourts", bson.M{"_id": foodCourt.ID}, at); err != nil {
+		return err
+	}
+	r.foodCourtDeleted(foodCourt)
+	return nil
+}
+
+func (s *Service) trashItems(sc mongo.SessionContext, r *Report, filter bson.M, at time.Time) error {
+	var items []models.Item
+	if err := s.findAll(sc, "items", models.NotDeleted(filter), &items); err != nil {
+		return err
+	}
+	if err := s.stamp(sc, r, "items", filter, at); err != nil {
+		return err
+	}
+	for _, item := range items {
+		foodCourtIDs, err := s.listedIn(sc, item.ID)
+		if err != nil {
+			return err
+		}
+		r.itemDeleted(item, s.shopName(sc, r, item.VendorID), foodCourtIDs)
+	}
+	return nil
+}
+
+func (s *Service) restoreUser(sc mongo.SessionContext, r *Report, user models.User) error {
+	batch := trashBatch(user.DeletedAt, user.DeletedBy)
+	if err := s.unstamp(sc, r, "users", bson.M{"_id": user.ID}, batch); err != nil {
+		return err
+	}
+
+	var vendor models.Vendor
+	err := s.db.Collection("vendors").FindOne(sc, withBatch(bson.M{"user_id": user.ID}, batch)).Decode(&vendor)
+	switch err {
+	case nil:
+		if err := s.restoreVendor(sc, r, vendor); err != nil {
+			return err
+		}
+	case mongo.ErrNoDocuments:
+	default:
+		return err
+	}
+
+	return s.restoreFoodCourts(sc, r, bson.M{"admin_id": user.ID}, batch)
+}
+
+func (s *Service) restoreVendor(sc mongo.SessionContext, r *Report, vendor models.Vendor) error {
+	// Upgrading the user again after a trash creates a fresh profile.
+	taken, err := s.db.Collection("vendors").CountDocuments(sc, models.NotDeleted(bson.M{"user_id": vendor.UserID}))
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrRestoreTaken
+	}
+
+	batch := trashBatch(vendor.DeletedAt, vendor.DeletedBy)
+	if err := s.unstamp(sc, r, "vendors", bson.M{"_id": vendor.ID}, batch); err != nil {
+		return err
+	}
+	if err := s.restoreItems(sc, r, bson.M{"vendor_id": vendor.ID}, batch); err != nil {
+		return err
+	}
+
+	vendor.DeletedAt, vendor.DeletedBy = nil, nil
+	foodCourts, err := s.findFoodCourts(sc, models.NotDeleted(bson.M{"vendor_ids": vendor.ID}))
+	if err != nil {
+		return err
+	}
+	for _, foodCourt := range foodCourts {
+		r.foodCourtVendorAdded(foodCourt, vendor)
+	}
+	return nil
+}
+
+func (s *Service) restoreFoodCourts(sc mongo.SessionContext, r *Report, filter, batch bson.M) error {
+	var foodCourts []models.FoodCourt
+	if err := s.findAll(sc, "foodcourts", withBatch(filter, batch), &foodCourts); err != nil {
+		return err
+	}
+	if err := s.unstamp(sc, r, "foodcourts", filter, batch); err != nil {
+		return err
+	}
+	for _, foodCourt := range foodCourts {
+		r.foodCourtRestored(foodCourt)
+	}
+	return nil
+}
+
+func (s *Service) restoreItems(sc mongo.SessionContext, r *Report, filter, batch bson.M) error {
+	var items []models.Item
+	if err := s.findAll(sc, "items", withBatch(filter, batch), &items); err != nil {
+		return err
+	}
+	if err := s.unstamp(sc, r, "items", filter, batch); err != nil {
+		return err
+	}
+	for _, item := range items {
+		foodCourtIDs, err := s.listedIn(sc, item.ID)
+		if err != nil {
+			return err
+		}
+		item.DeletedAt, item.DeletedBy = nil, nil
+		r.itemRestored(item, s.shopName(sc, r, item.VendorID), foodCourtIDs)
+	}
+	return nil
+}
+
+// stamp soft-deletes the live documents matching filter.
+func (s *Service) stamp(sc mongo.SessionContext, r *Report, collection string, filter bson.M, at time.Time) error {
+	result, err := s.db.Collection(collection).UpdateMany(sc,
+		models.NotDeleted(filter),
+		bson.M{"$set": bson.M{"deletedAt": at, "deletedBy": s.actor.UserID}},
+	)
+	if err != nil {
+		return err
+	}
+	r.trashed(collection, result.ModifiedCount)
+	return nil
+}
+
+// unstamp restores the documents matching filter that went in batch.
+func (s *Service) unstamp(sc mongo.SessionContext, r *Report, collection string, filter, batch bson.M) error {
+	result, err := s.db.Collection(collection).UpdateMany(sc,
+		withBatch(filter, batch),
+		bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	r.restored(collection, result.ModifiedCount)
+	return nil
+}
+
+func (s *Service) findTrashed(sc mongo.SessionContext, collection string, id primitive.ObjectID, result interface{}) error {
+	err := s.db.Collection(collection).FindOne(sc, bson.M{"_id": id, "deletedAt": bson.M{"$ne": nil}}).Decode(result)
+	return notFound(err)
+}
+
+func (s *Service) requireLive(sc mongo.SessionContext, collection string, id primitive.ObjectID) error {
+	live, err := s.db.Collection(collection).CountDocuments(sc, models.NotDeleted(bson.M{"_id": id}))
+	if err != nil {
+		return err
+	}
+	if live == 0 {
+		return ErrParentTrashed
+	}
+	return nil
+}
+
+func (s *Service) listedIn(sc mongo.SessionContext, itemID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ids, err := s.db.Collection("itemfoodcourts").Distinct(sc, "foodcourt_id", bson.M{"item_id": itemID})
+	if err != nil {
+		return nil, err
+	}
+	foodCourtIDs := []primitive.ObjectID{}
+	for _, id := range ids {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			foodCourtIDs = append(foodCourtIDs, oid)
+		}
+	}
+	return foodCourtIDs, nil
+}
+
+// trashTime is cut to the millisecond MongoDB stores, so the stamp read
+// back from a document matches the rest of its batch exactly.
+func trashTime() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+func trashBatch(deletedAt *time.Time, deletedBy *primitive.ObjectID) bson.M {
+	return bson.M{"deletedAt": deletedAt, "deletedBy": deletedBy}
+}
+
+func withBatch(filter, batch bson.M) bson.M {
+	matched := bson.M{}
+	for key, value := range filter {
+		matched[key] = value
+	}
+	for key, value := range batch {
+		matched[key] = value
+	}
+	return matched
+}
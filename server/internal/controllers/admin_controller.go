@@ -3,10 +3,16 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/cascade"
 	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,7 +21,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func GetAllUsers(c *gin.Context, db *mongo.Database) {
+func GetAllUsers(c *gin.Context, repos *repository.Repositories) {
 
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "50")
@@ -31,40 +37,18 @@ func GetAllUsers(c *gin.Context, db *mongo.Database) {
 	}
 
 	skip := (page - 1) * limit
+	query := repository.UserQuery{Email: searchEmail}
 
-	collection := db.Collection("users")
-
-	filter := bson.M{}
-	if searchEmail != "" {
-
-		filter["email"] = bson.M{
-			"$regex":   searchEmail,
-			"$options": "i",
-		}
-	}
-
-	findOptions := options.Find().
-		SetProjection(bson.M{
-			"password": 0,
-		}).
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{"createdAt": -1})
-
-	cursor, err := collection.Find(context.TODO(), filter, findOptions)
+	users, err := repos.Users.ListPage(context.TODO(), query, int64(skip), int64(limit))
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to fetch users")
 		return
 	}
-	defer cursor.Close(context.TODO())
-
-	var users []models.User
-	if err := cursor.All(context.TODO(), &users); err != nil {
-		utils.RespondError(c, 500, "Error decoding users")
-		return
+	for i := range users {
+		users[i].Password = ""
 	}
 
-	total, err := collection.CountDocuments(context.TODO(), filter)
+	total, err := repos.Users.Count(context.TODO(), query)
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to count users")
 		return
@@ -82,7 +66,7 @@ func GetAllUsers(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func MakeVendor(c *gin.Context, db *mongo.Database) {
+func MakeVendor(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	userID := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -90,41 +74,31 @@ func MakeVendor(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	usersCol := db.Collection("users")
-	vendorsCol := db.Collection("vendors")
-
-	var user models.User
-	err = usersCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&user)
+	user, err := repos.Users.FindByID(context.TODO(), objID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	if user.Role == "vendor" {
+	if permissions.Has(user.EffectiveRoles(), permissions.RoleVendor) {
 		utils.RespondError(c, http.StatusConflict, "User is already a vendor")
 		return
 	}
 
-	_, err = usersCol.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"role": "vendor", "updatedAt": time.Now()}},
-	)
+	_, err = updateUserRoles(context.TODO(), db, repos, objID, []string{permissions.RoleVendor}, nil)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
 		return
 	}
 
 	newVendor := models.Vendor{
-		ID:        primitive.NewObjectID(),
 		UserID:    user.ID,
 		ShopName:  user.Name + "'s Shop",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	_, err = vendorsCol.InsertOne(context.TODO(), newVendor)
-	if err != nil {
+	if err := repos.Vendors.Create(context.TODO(), &newVendor); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to create vendor profile")
 		return
 	}
@@ -136,7 +110,7 @@ func MakeVendor(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func MakeUser(c *gin.Context, db *mongo.Database) {
+func MakeUser(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	userID := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -144,44 +118,53 @@ func MakeUser(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	usersCol := db.Collection("users")
-	vendorsCol := db.Collection("vendors")
-
-	var user models.User
-	err = usersCol.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&user)
+	user, err := repos.Users.FindByID(context.TODO(), objID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	if user.Role == "user" {
-		utils.RespondError(c, http.StatusConflict, "User is already a normal user")
+	// Only the vendor role goes. Manager and admin roles are granted and
+	// taken away through their own flows.
+	if !permissions.Has(user.EffectiveRoles(), permissions.RoleVendor) {
+		utils.RespondError(c, http.StatusConflict, "User is not a vendor")
 		return
 	}
 
-	_, err = usersCol.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"role": "user", "updatedAt": time.Now()}},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
-		return
+	revoke := func(ctx context.Context) error {
+		updated, err := updateUserRoles(ctx, db, repos, objID, nil, []string{permissions.RoleVendor})
+		user.Role = updated.Role
+		return err
 	}
 
-	_, err = vendorsCol.DeleteOne(context.TODO(), bson.M{"userId": objID})
-	if err != nil && err != mongo.ErrNoDocuments {
+	// A vendor loses the shop and the roles in one transaction.
+	var removed *cascade.Report
+	vendor, err := repos.Vendors.FindByUser(context.TODO(), objID)
+	if err == nil {
+		removed, err = cascadeFor(c, db).Then(func(sc mongo.SessionContext, _ *cascade.Report) error {
+			return revoke(sc)
+		}).TrashVendor(context.TODO(), vendor.ID)
+	}
+	if err != nil && err != repository.ErrNotFound && err != cascade.ErrNotFound {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to remove vendor profile")
 		return
 	}
+	if removed == nil {
+		if err := revoke(context.TODO()); err != nil {
+			utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
+			return
+		}
+	}
+	actor.Invalidate(objID)
 
-	utils.RespondSuccess(c, http.StatusOK, "User downgraded to normal user successfully", gin.H{
+	utils.RespondSuccess(c, http.StatusOK, "Vendor role removed successfully", gin.H{
 		"user_id":  user.ID,
-		"new_role": "user",
+		"new_role": user.Role,
+		"removed":  removed,
 	})
 }
 
-func DeleteUser(c *gin.Context, db *mongo.Database) {
+func DeleteUser(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	userIDParam := c.Param("id")
 	userID, err := primitive.ObjectIDFromHex(userIDParam)
 	if err != nil {
@@ -189,9 +172,8 @@ func DeleteUser(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	var user models.User
-	err = db.Collection("users").FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&user)
-	if err == mongo.ErrNoDocuments {
+	user, err := repos.Users.FindByID(context.TODO(), userID)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, 404, "User not found")
 		return
 	} else if err != nil {
@@ -199,61 +181,25 @@ func DeleteUser(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	switch user.Role {
-	case "vendor":
-
-		db.Collection("vendors").DeleteOne(context.TODO(), bson.M{"user_id": user.ID})
-
-		itemCursor, _ := db.Collection("items").Find(context.TODO(), bson.M{"vendor_id": user.ID})
-		var items []models.Item
-		_ = itemCursor.All(context.TODO(), &items)
-
-		for _, item := range items {
-			db.Collection("itemfoodcourts").DeleteMany(context.TODO(), bson.M{"item_id": item.ID})
-			db.Collection("items").DeleteOne(context.TODO(), bson.M{"_id": item.ID})
-		}
-
-		db.Collection("foodcourts").UpdateMany(
-			context.TODO(),
-			bson.M{"vendor_ids": user.ID},
-			bson.M{"$pull": bson.M{"vendor_ids": user.ID}},
-		)
-
-		db.Collection("managers").DeleteMany(context.TODO(), bson.M{"vendor_id": user.ID})
-
-	case "admin":
-
-		fcCursor, _ := db.Collection("foodcourts").Find(context.TODO(), bson.M{"admin_id": user.ID})
-		var foodcourts []models.FoodCourt
-		_ = fcCursor.All(context.TODO(), &foodcourts)
-
-		for _, fc := range foodcourts {
-
-			db.Collection("itemfoodcourts").DeleteMany(context.TODO(), bson.M{"foodcourt_id": fc.ID})
-
-			db.Collection("managers").DeleteMany(context.TODO(), bson.M{"foodcourt_id": fc.ID})
-
-			db.Collection("foodcourts").DeleteOne(context.TODO(), bson.M{"_id": fc.ID})
-		}
-
-	case "user":
-
-	}
-
-	_, err = db.Collection("users").DeleteOne(context.TODO(), bson.M{"_id": user.ID})
-	if err != nil {
+	report, err := cascadeFor(c, db).TrashUser(context.TODO(), user.ID)
+	if err == cascade.ErrNotFound {
+		utils.RespondError(c, 404, "User not found")
+		return
+	} else if err != nil {
 		utils.RespondError(c, 500, "Failed to delete user")
 		return
 	}
+	actor.InvalidateAll()
 
-	utils.RespondSuccess(c, 200, "User deleted successfully", gin.H{
-		"id":    user.ID.Hex(),
-		"email": user.Email,
-		"role":  user.Role,
+	utils.RespondSuccess(c, 200, "User moved to trash", gin.H{
+		"id":      user.ID.Hex(),
+		"email":   user.Email,
+		"role":    user.Role,
+		"removed": report,
 	})
 }
 
-func GetAdminProfile(c *gin.Context, db *mongo.Database) {
+func GetAdminProfile(c *gin.Context, repos *repository.Repositories) {
 
 	adminIDHex, exists := c.Get("userID")
 	if !exists {
@@ -267,9 +213,11 @@ func GetAdminProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	var admin models.User
-	err = db.Collection("users").FindOne(context.TODO(), bson.M{"_id": adminID, "role": "admin"}).Decode(&admin)
-	if err == mongo.ErrNoDocuments {
+	admin, err := repos.Users.FindByID(context.TODO(), adminID)
+	if err == nil && admin.Role != permissions.RoleAdmin {
+		err = repository.ErrNotFound
+	}
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, 404, "Admin not found")
 		return
 	} else if err != nil {
@@ -277,9 +225,7 @@ func GetAdminProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	cursor, _ := db.Collection("foodcourts").Find(context.TODO(), bson.M{"admin_id": admin.ID})
-	var foodcourts []models.FoodCourt
-	_ = cursor.All(context.TODO(), &foodcourts)
+	foodcourts, _ := repos.FoodCourts.List(context.TODO(), repository.FoodCourtQuery{AdminID: admin.ID})
 
 	var fcSummaries []gin.H
 	for _, fc := range foodcourts {
@@ -302,7 +248,7 @@ func GetAdminProfile(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func UpdateAdminProfile(c *gin.Context, db *mongo.Database) {
+func UpdateAdminProfile(c *gin.Context, repos *repository.Repositories) {
 
 	adminIDHex, exists := c.Get("userID")
 	if !exists {
@@ -330,28 +276,37 @@ func UpdateAdminProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	updateData := bson.M{"updatedAt": time.Now()}
+	admin, err := repos.Users.FindByID(context.TODO(), adminID)
+	if err == nil && admin.Role != permissions.RoleAdmin {
+		err = repository.ErrNotFound
+	}
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, 404, "Admin not found")
+		return
+	} else if err != nil {
+		utils.RespondError(c, 500, "Database error")
+		return
+	}
+
+	updateData := repository.Fields{"updatedAt": time.Now()}
 	if input.Name != "" {
 		updateData["name"] = input.Name
 	}
 	if input.Email != "" {
 
-		count, _ := db.Collection("users").CountDocuments(context.TODO(), bson.M{
-			"email": input.Email,
-			"_id":   bson.M{"$ne": adminID},
-		})
-		if count > 0 {
+		inUse, _ := repos.Users.EmailInUse(context.TODO(), input.Email, adminID)
+		if inUse {
 			utils.RespondError(c, 409, "Email already in use")
 			return
 		}
 		updateData["email"] = input.Email
 	}
 
-	_, err = db.Collection("users").UpdateOne(
-		context.TODO(),
-		bson.M{"_id": adminID, "role": "admin"},
-		bson.M{"$set": updateData},
-	)
+	err = repos.Users.Update(context.TODO(), adminID, updateData)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.RespondError(c, 409, "Email already in use")
+		return
+	}
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to update profile")
 		return
@@ -360,7 +315,7 @@ func UpdateAdminProfile(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, 200, "Admin profile updated successfully", nil)
 }
 
-func GetAllVendors(c *gin.Context, db *mongo.Database) {
+func GetAllVendors(c *gin.Context, repos *repository.Repositories) {
 
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "50")
@@ -378,56 +333,27 @@ func GetAllVendors(c *gin.Context, db *mongo.Database) {
 	}
 
 	skip := (page - 1) * limit
+	query := repository.UserQuery{Role: permissions.RoleVendor, Email: searchEmail, Name: searchName}
 
-	usersCollection := db.Collection("users")
-	vendorsCollection := db.Collection("vendors")
-
-	userFilter := bson.M{"role": "vendor"}
-
-	if searchEmail != "" || searchName != "" {
-		andConditions := []bson.M{{"role": "vendor"}}
-
-		if searchEmail != "" {
-			andConditions = append(andConditions, bson.M{
-				"email": bson.M{
-					"$regex":   searchEmail,
-					"$options": "i",
-				},
-			})
-		}
-
-		if searchName != "" {
-			andConditions = append(andConditions, bson.M{
-				"name": bson.M{
-					"$regex":   searchName,
-					"$options": "i",
-				},
-			})
-		}
-
-		userFilter = bson.M{"$and": andConditions}
-	}
-
-	findOptions := options.Find().
-		SetProjection(bson.M{
-			"password": 0,
-		}).
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{"createdAt": -1})
-
-	cursor, err := usersCollection.Find(context.TODO(), userFilter, findOptions)
+	vendorUsers, err := repos.Users.ListPage(context.TODO(), query, int64(skip), int64(limit))
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendors")
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	var vendorUsers []models.User
-	if err := cursor.All(context.TODO(), &vendorUsers); err != nil {
+	userIDs := make([]primitive.ObjectID, 0, len(vendorUsers))
+	for _, user := range vendorUsers {
+		userIDs = append(userIDs, user.ID)
+	}
+	vendors, err := repos.Vendors.ListByUsers(context.TODO(), userIDs)
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Error decoding vendors")
 		return
 	}
+	vendorByUser := make(map[primitive.ObjectID]models.Vendor, len(vendors))
+	for _, vendor := range vendors {
+		vendorByUser[vendor.UserID] = vendor
+	}
 
 	type VendorWithProfile struct {
 		ID        string `json:"id"`
@@ -452,9 +378,7 @@ func GetAllVendors(c *gin.Context, db *mongo.Database) {
 			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
 
-		var vendor models.Vendor
-		err := vendorsCollection.FindOne(context.TODO(), bson.M{"userId": user.ID}).Decode(&vendor)
-		if err == nil {
+		if vendor, ok := vendorByUser[user.ID]; ok {
 			vendorData.ShopName = vendor.ShopName
 			vendorData.VendorID = vendor.ID.Hex()
 		}
@@ -462,7 +386,7 @@ func GetAllVendors(c *gin.Context, db *mongo.Database) {
 		vendorsWithProfiles = append(vendorsWithProfiles, vendorData)
 	}
 
-	total, err := usersCollection.CountDocuments(context.TODO(), userFilter)
+	total, err := repos.Users.Count(context.TODO(), query)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to count vendors")
 		return
@@ -481,7 +405,7 @@ func GetAllVendors(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func GetVendorDetails(c *gin.Context, db *mongo.Database) {
+func GetVendorDetails(c *gin.Context, repos *repository.Repositories) {
 	vendorID := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(vendorID)
 	if err != nil {
@@ -492,10 +416,12 @@ func GetVendorDetails(c *gin.Context, db *mongo.Database) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var user models.User
-	err = db.Collection("users").FindOne(ctx, bson.M{"_id": objID, "role": "vendor"}).Decode(&user)
+	user, err := repos.Users.FindByID(ctx, objID)
+	if err == nil && !permissions.Has(user.EffectiveRoles(), permissions.RoleVendor) {
+		err = repository.ErrNotFound
+	}
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "Vendor user not found")
 		} else {
 			utils.RespondError(c, http.StatusInternalServerError, "Database error")
@@ -503,10 +429,9 @@ func GetVendorDetails(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	var vendor models.Vendor
-	err = db.Collection("vendors").FindOne(ctx, bson.M{"user_id": user.ID}).Decode(&vendor)
+	vendor, err := repos.Vendors.FindByUser(ctx, user.ID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "Vendor profile not found for this user")
 		} else {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch profile")
@@ -514,7 +439,7 @@ func GetVendorDetails(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	itemCount, err := db.Collection("items").CountDocuments(ctx, bson.M{"vendor_id": vendor.ID})
+	itemCount, err := repos.Items.CountByVendor(ctx, vendor.ID)
 	if err != nil {
 		itemCount = 0
 	}
@@ -533,7 +458,7 @@ func GetVendorDetails(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func GetAllFoodCourtsAdmin(c *gin.Context, db *mongo.Database) {
+func GetAllFoodCourtsAdmin(c *gin.Context, repos *repository.Repositories) {
 
 	adminIDVal, exists := c.Get("userID")
 	if !exists {
@@ -567,38 +492,15 @@ func GetAllFoodCourtsAdmin(c *gin.Context, db *mongo.Database) {
 	}
 	skip := (page - 1) * limit
 
-	collection := db.Collection("foodcourts")
-
-	filter := bson.M{
-		"admin_id": adminObjID,
-	}
-
-	if searchName != "" {
-		filter["name"] = bson.M{
-			"$regex":   searchName,
-			"$options": "i",
-		}
-	}
-
-	findOptions := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{"createdAt": -1})
+	query := repository.FoodCourtQuery{AdminID: adminObjID, Name: searchName}
 
-	cursor, err := collection.Find(context.TODO(), filter, findOptions)
+	foodCourts, err := repos.FoodCourts.ListPage(context.TODO(), query, int64(skip), int64(limit))
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to fetch food courts")
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	var foodCourts []models.FoodCourt
-	if err := cursor.All(context.TODO(), &foodCourts); err != nil {
-		utils.RespondError(c, 500, "Error decoding food courts")
-		return
-	}
-
-	total, err := collection.CountDocuments(context.TODO(), filter)
+	total, err := repos.FoodCourts.Count(context.TODO(), query)
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to count food courts")
 		return
@@ -616,7 +518,7 @@ func GetAllFoodCourtsAdmin(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func CreateFoodCourt(c *gin.Context, db *mongo.Database) {
+func CreateFoodCourt(c *gin.Context, repos *repository.Repositories) {
 
 	adminIDVal, exists := c.Get("userID")
 	if !exists {
@@ -642,17 +544,12 @@ func CreateFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	collection := db.Collection("foodcourts")
-
-	count, err := collection.CountDocuments(context.TODO(), bson.M{
-		"name":     foodCourt.Name,
-		"admin_id": adminObjID,
-	})
+	taken, err := repos.FoodCourts.NameTaken(context.TODO(), adminObjID, foodCourt.Name)
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to check for duplicate food court")
 		return
 	}
-	if count > 0 {
+	if taken {
 		utils.RespondError(c, 400, "Food court with the same name already exists")
 		return
 	}
@@ -667,16 +564,37 @@ func CreateFoodCourt(c *gin.Context, db *mongo.Database) {
 		foodCourt.Weekends = true
 	}
 
-	_, err = collection.InsertOne(context.TODO(), foodCourt)
-	if err != nil {
+	if foodCourt.Timezone == "" {
+		foodCourt.Timezone = models.DefaultFoodCourtTimezone
+	} else if _, err := time.LoadLocation(foodCourt.Timezone); err != nil {
+		utils.RespondError(c, 400, "Invalid timezone")
+		return
+	}
+
+	if err := validateMealSlots(foodCourt.MealSlots); err != nil {
+		utils.RespondError(c, 400, "Invalid meal slots: "+err.Error())
+		return
+	}
+
+	if foodCourt.Schedule != nil {
+		if err := validateOpeningSchedule(foodCourt.Schedule); err != nil {
+			utils.RespondError(c, 400, "Invalid schedule: "+err.Error())
+			return
+		}
+		foodCourt.ApplySchedule(time.Now())
+	}
+
+	if err := repos.FoodCourts.Create(context.TODO(), &foodCourt); err != nil {
 		utils.RespondError(c, 500, "Failed to create food court")
 		return
 	}
 
+	broadcastFoodCourt(foodCourt, "create")
+
 	utils.RespondSuccess(c, 201, "Food court created successfully", foodCourt)
 }
 
-func AddVendorToFoodCourt(c *gin.Context, db *mongo.Database) {
+func AddVendorToFoodCourt(c *gin.Context, repos *repository.Repositories) {
 	foodCourtIDStr := c.Param("foodCourtId")
 	vendorIDStr := c.Param("vendorId")
 
@@ -691,61 +609,49 @@ func AddVendorToFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	usersCol := db.Collection("users")
-	vendorsCol := db.Collection("vendors")
-	foodCourtsCol := db.Collection("foodcourts")
-
-	var vendor models.Vendor
-	err = vendorsCol.FindOne(context.TODO(), bson.M{"_id": vendorID}).Decode(&vendor)
+	vendor, err := repos.Vendors.FindByID(context.TODO(), vendorID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
 
-	var user models.User
-	err = usersCol.FindOne(context.TODO(), bson.M{"_id": vendor.UserID}).Decode(&user)
+	user, err := repos.Users.FindByID(context.TODO(), vendor.UserID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Associated user not found")
 		return
 	}
-	if user.Role != "vendor" {
+	if !permissions.Has(user.EffectiveRoles(), permissions.RoleVendor) {
 		utils.RespondError(c, http.StatusBadRequest, "User is not a vendor")
 		return
 	}
 
-	count, err := foodCourtsCol.CountDocuments(context.TODO(), bson.M{
-		"_id":        foodCourtID,
-		"vendor_ids": vendorID,
-	})
-	if err != nil {
+	foodCourt, err := repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		return
+	} else if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to check existing vendors")
 		return
 	}
-	if count > 0 {
+	if slices.Contains(foodCourt.VendorIDs, vendorID) {
 		utils.RespondError(c, http.StatusConflict, "Vendor already added to this food court")
 		return
 	}
 
-	_, err = foodCourtsCol.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": foodCourtID},
-		bson.M{
-			"$push": bson.M{"vendor_ids": vendorID},
-			"$set":  bson.M{"updatedAt": time.Now()},
-		},
-	)
-	if err != nil {
+	if err := repos.FoodCourts.AddVendor(context.TODO(), foodCourtID, vendorID); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to add vendor to food court")
 		return
 	}
 
+	broadcastFoodCourtVendor(repos, foodCourtID, vendorID, "add")
+
 	utils.RespondSuccess(c, http.StatusOK, "Vendor added to food court successfully", gin.H{
 		"foodCourtId": foodCourtID,
 		"vendorId":    vendorID,
 	})
 }
 
-func RemoveVendorFromFoodCourt(c *gin.Context, db *mongo.Database) {
+func RemoveVendorFromFoodCourt(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	foodCourtIDStr := c.Param("foodCourtId")
 	vendorIDStr := c.Param("vendorId")
 
@@ -777,39 +683,30 @@ func RemoveVendorFromFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	foodCourtsCol := db.Collection("foodcourts")
-
-	var foodCourt models.FoodCourt
-	err = foodCourtsCol.FindOne(context.TODO(), bson.M{
-		"_id":        foodCourtID,
-		"admin_id":   adminObjID,
-		"vendor_ids": vendorID,
-	}).Decode(&foodCourt)
-	if err != nil {
+	foodCourt, err := repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
+	if err != nil || foodCourt.AdminID != adminObjID || !slices.Contains(foodCourt.VendorIDs, vendorID) {
 		utils.RespondError(c, http.StatusNotFound, "Vendor not found in this food court or you are not the admin")
 		return
 	}
 
-	_, err = foodCourtsCol.UpdateOne(
-		context.TODO(),
-		bson.M{"_id": foodCourtID},
-		bson.M{
-			"$pull": bson.M{"vendor_ids": vendorID},
-			"$set":  bson.M{"updatedAt": time.Now()},
-		},
-	)
-	if err != nil {
+	report, err := cascadeFor(c, db).Then(demoteFormerManagers(db, repos)).RemoveVendorFromFoodCourt(context.TODO(), foodCourtID, vendorID)
+	if err == cascade.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "Vendor not found in this food court or you are not the admin")
+		return
+	} else if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to remove vendor from food court")
 		return
 	}
+	actor.InvalidateAll()
 
 	utils.RespondSuccess(c, http.StatusOK, "Vendor removed from food court successfully", gin.H{
 		"foodCourtId": foodCourtID,
 		"vendorId":    vendorID,
+		"removed":     report,
 	})
 }
 
-func UpdateFoodCourt(c *gin.Context, db *mongo.Database) {
+func UpdateFoodCourt(c *gin.Context, repos *repository.Repositories) {
 	foodCourtIDStr := c.Param("foodCourtId")
 	foodCourtID, err := primitive.ObjectIDFromHex(foodCourtIDStr)
 	if err != nil {
@@ -839,22 +736,25 @@ func UpdateFoodCourt(c *gin.Context, db *mongo.Database) {
 		Timings  *string `json:"timings,omitempty"`
 		Weekdays *bool   `json:"weekdays,omitempty"`
 		Weekends *bool   `json:"weekends,omitempty"`
+		Timezone *string `json:"timezone,omitempty"`
+		IsOpen   *bool   `json:"isOpen,omitempty"`
+
+		MealSlots      []models.MealSlot       `json:"mealSlots,omitempty"`
+		Schedule       *models.OpeningSchedule `json:"schedule,omitempty"`
+		RemoveSchedule bool                    `json:"removeSchedule,omitempty"`
 	}
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	collection := db.Collection("foodcourts")
-
-	var foodCourt models.FoodCourt
-	err = collection.FindOne(context.TODO(), bson.M{"_id": foodCourtID, "admin_id": adminObjID}).Decode(&foodCourt)
-	if err != nil {
+	foodCourt, err := repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
+	if err != nil || foodCourt.AdminID != adminObjID {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found or you are not the admin")
 		return
 	}
 
-	update := bson.M{"updatedAt": time.Now()}
+	update := repository.Fields{"updatedAt": time.Now()}
 	if updateData.Name != nil {
 		update["name"] = *updateData.Name
 	}
@@ -870,23 +770,69 @@ func UpdateFoodCourt(c *gin.Context, db *mongo.Database) {
 	if updateData.Weekends != nil {
 		update["weekends"] = *updateData.Weekends
 	}
+	if updateData.IsOpen != nil {
+		update["isOpen"] = *updateData.IsOpen
+	}
+	if updateData.Timezone != nil {
+		if _, err := time.LoadLocation(*updateData.Timezone); err != nil || *updateData.Timezone == "" {
+			utils.RespondError(c, http.StatusBadRequest, "Invalid timezone")
+			return
+		}
+		update["timezone"] = *updateData.Timezone
+		foodCourt.Timezone = *updateData.Timezone
+	}
 
-	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": foodCourtID}, bson.M{"$set": update})
+	if updateData.MealSlots != nil {
+		if err := validateMealSlots(updateData.MealSlots); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "Invalid meal slots: "+err.Error())
+			return
+		}
+		update["mealSlots"] = updateData.MealSlots
+	}
+
+	var unset []string
+	if updateData.RemoveSchedule {
+		foodCourt.Schedule = nil
+		unset = append(unset, "schedule")
+	} else if updateData.Schedule != nil {
+		if err := validateOpeningSchedule(updateData.Schedule); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "Invalid schedule: "+err.Error())
+			return
+		}
+		foodCourt.Schedule = updateData.Schedule
+	}
+
+	if foodCourt.Schedule != nil {
+		if updateData.IsOpen != nil {
+			utils.RespondError(c, http.StatusConflict, "Opening is driven by the schedule; add an exception or remove the schedule instead")
+			return
+		}
+		foodCourt.ApplySchedule(time.Now())
+		update["schedule"] = foodCourt.Schedule
+		update["timings"] = foodCourt.Timings
+		update["weekdays"] = foodCourt.Weekdays
+		update["weekends"] = foodCourt.Weekends
+		update["isOpen"] = foodCourt.IsOpen
+	}
+
+	err = repos.FoodCourts.Update(context.TODO(), foodCourtID, update, unset...)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to update food court")
 		return
 	}
 
-	err = collection.FindOne(context.TODO(), bson.M{"_id": foodCourtID}).Decode(&foodCourt)
+	foodCourt, err = repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated food court")
 		return
 	}
 
+	broadcastFoodCourt(foodCourt, "update")
+
 	utils.RespondSuccess(c, http.StatusOK, "Food court updated successfully", foodCourt)
 }
 
-func DeleteFoodCourt(c *gin.Context, db *mongo.Database) {
+func DeleteFoodCourt(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	foodCourtIDStr := c.Param("foodCourtId")
 	foodCourtID, err := primitive.ObjectIDFromHex(foodCourtIDStr)
 	if err != nil {
@@ -910,40 +856,45 @@ func DeleteFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	foodCourtsCol := db.Collection("foodcourts")
-	itemFoodCourtCol := db.Collection("item_foodcourts")
-	managersCol := db.Collection("managers")
-
-	var fc models.FoodCourt
-	err = foodCourtsCol.FindOne(context.TODO(), bson.M{"_id": foodCourtID, "admin_id": adminObjID}).Decode(&fc)
-	if err != nil {
+	fc, err := repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
+	if err != nil || fc.AdminID != adminObjID {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found or you are not the admin")
 		return
 	}
 
-	_, err = foodCourtsCol.DeleteOne(context.TODO(), bson.M{"_id": foodCourtID})
-	if err != nil {
+	report, err := cascadeFor(c, db).TrashFoodCourt(context.TODO(), foodCourtID)
+	if err == cascade.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "Food court not found or you are not the admin")
+		return
+	} else if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to delete food court")
 		return
 	}
+	actor.InvalidateAll()
 
-	_, _ = itemFoodCourtCol.DeleteMany(context.TODO(), bson.M{"foodcourt_id": foodCourtID})
-
-	_, _ = managersCol.DeleteMany(context.TODO(), bson.M{"foodcourt_id": foodCourtID})
-
-	utils.RespondSuccess(c, http.StatusOK, "Food court and all related references deleted successfully", nil)
+	utils.RespondSuccess(c, http.StatusOK, "Food court moved to trash", gin.H{"removed": report})
 }
 
-func GetVendorDropdown(c *gin.Context, db *mongo.Database) {
-	vendorsCol := db.Collection("vendors")
-	usersCol := db.Collection("users")
+func GetVendorDropdown(c *gin.Context, repos *repository.Repositories) {
+	allVendors, err := repos.Vendors.List(context.TODO())
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendors")
+		return
+	}
 
-	cursor, err := vendorsCol.Find(context.TODO(), bson.M{})
+	userIDs := make([]primitive.ObjectID, 0, len(allVendors))
+	for _, v := range allVendors {
+		userIDs = append(userIDs, v.UserID)
+	}
+	owners, err := repos.Users.List(context.TODO(), repository.UserQuery{IDs: userIDs, Role: permissions.RoleVendor})
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendors")
 		return
 	}
-	defer cursor.Close(context.TODO())
+	isVendor := make(map[primitive.ObjectID]bool, len(owners))
+	for _, owner := range owners {
+		isVendor[owner.ID] = true
+	}
 
 	type VendorDropdown struct {
 		ID       primitive.ObjectID `json:"id"`
@@ -952,15 +903,8 @@ func GetVendorDropdown(c *gin.Context, db *mongo.Database) {
 	}
 
 	var vendors []VendorDropdown
-	for cursor.Next(context.TODO()) {
-		var v models.Vendor
-		if err := cursor.Decode(&v); err != nil {
-			continue
-		}
-
-		var user models.User
-		err := usersCol.FindOne(context.TODO(), bson.M{"_id": v.UserID}).Decode(&user)
-		if err != nil || user.Role != "vendor" {
+	for _, v := range allVendors {
+		if !isVendor[v.UserID] {
 			continue
 		}
 
@@ -974,7 +918,7 @@ func GetVendorDropdown(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Vendors fetched successfully", vendors)
 }
 
-func GetFoodCourtDetailsAdmin(c *gin.Context, db *mongo.Database) {
+func GetFoodCourtDetailsAdmin(c *gin.Context, repos *repository.Repositories) {
 	foodCourtIDStr := c.Param("foodCourtId")
 	foodCourtID, err := primitive.ObjectIDFromHex(foodCourtIDStr)
 	if err != nil {
@@ -998,37 +942,22 @@ func GetFoodCourtDetailsAdmin(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	foodCourtsCol := db.Collection("foodcourts")
-	vendorsCol := db.Collection("vendors")
-
-	var fc models.FoodCourt
-	err = foodCourtsCol.FindOne(context.TODO(), bson.M{
-		"_id":      foodCourtID,
-		"admin_id": adminObjID,
-	}).Decode(&fc)
-	if err != nil {
+	fc, err := repos.FoodCourts.FindByID(context.TODO(), foodCourtID)
+	if err != nil || fc.AdminID != adminObjID {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found or you are not the admin")
 		return
 	}
 
 	var vendorList []bson.M
 	if len(fc.VendorIDs) > 0 {
-		cursor, err := vendorsCol.Find(
-			context.TODO(),
-			bson.M{"_id": bson.M{"$in": fc.VendorIDs}},
-			options.Find().SetProjection(bson.M{
-				"_id":      1,
-				"shopName": 1,
-			}),
-		)
+		vendors, err := repos.Vendors.ListByIDs(context.TODO(), fc.VendorIDs)
 		if err != nil {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendor details")
 			return
 		}
-		defer cursor.Close(context.TODO())
-		if err := cursor.All(context.TODO(), &vendorList); err != nil {
-			utils.RespondError(c, http.StatusInternalServerError, "Error decoding vendor details")
-			return
+		vendorList = []bson.M{}
+		for _, vendor := range vendors {
+			vendorList = append(vendorList, bson.M{"_id": vendor.ID, "shopName": vendor.ShopName})
 		}
 	}
 
@@ -1038,94 +967,119 @@ func GetFoodCourtDetailsAdmin(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func GetAllManagers(c *gin.Context, db *mongo.Database) {
+func GetAllManagers(c *gin.Context, repos *repository.Repositories) {
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "50")
 
 	searchName := c.Query("name")
 
 	page, _ := strconv.Atoi(pageStr)
-	limit, _ := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(limitStr)
 	if page < 1 {
 		page = 1
 	}
+	if err != nil || limit < 1 {
+		limit = 50
+	}
 	skip := (page - 1) * limit
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	pipeline := []bson.M{
-
-		{"$lookup": bson.M{
-			"from":         "users",
-			"localField":   "user_id",
-			"foreignField": "_id",
-			"as":           "user_info",
-		}},
-		{"$unwind": "$user_info"},
-
-		{"$match": bson.M{
-			"user_info.name": bson.M{"$regex": searchName, "$options": "i"},
-		}},
-
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "fc_info",
-		}},
-		{"$unwind": bson.M{"path": "$fc_info", "preserveNullAndEmptyArrays": true}},
-
-		{"$lookup": bson.M{
-			"from":         "vendors",
-			"localField":   "vendor_id",
-			"foreignField": "_id",
-			"as":           "vendor_info",
-		}},
-		{"$unwind": bson.M{"path": "$vendor_info", "preserveNullAndEmptyArrays": true}},
-
-		{"$project": bson.M{
-			"id":        "$_id",
-			"name":      "$user_info.name",
-			"email":     "$user_info.email",
-			"contactNo": "$contact_no",
-			"isActive":  "$isActive",
-			"createdAt": 1,
-			"foodCourt": bson.M{
-				"id":   "$fc_info._id",
-				"name": "$fc_info.name",
-			},
-
-			"vendorId": "$vendor_info.user_id",
-
-			"vendors": []string{"$vendor_info.shopName"},
-
-			"vendor": bson.M{
-				"id": "$vendor_info._id",
-
-				"name": "$vendor_info.shopName",
-			},
-		}},
-
-		{"$sort": bson.M{"createdAt": -1}},
-		{"$skip": int64(skip)},
-		{"$limit": int64(limit)},
-	}
-
-	cursor, err := db.Collection("managers").Aggregate(ctx, pipeline)
+	managers, err := repos.Managers.List(ctx)
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to fetch managers data")
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var results []bson.M = []bson.M{}
-	if err := cursor.All(ctx, &results); err != nil {
-		utils.RespondError(c, 500, "Decoding error")
+	userIDs := make([]primitive.ObjectID, 0, len(managers))
+	for _, manager := range managers {
+		userIDs = append(userIDs, manager.UserID)
+	}
+	users, err := repos.Users.List(ctx, repository.UserQuery{IDs: userIDs, Name: searchName})
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to fetch managers data")
 		return
 	}
+	userByID := make(map[primitive.ObjectID]models.User, len(users))
+	for _, user := range users {
+		userByID[user.ID] = user
+	}
 
-	total, _ := db.Collection("managers").CountDocuments(ctx, bson.M{})
+	matched := []models.Manager{}
+	for _, manager := range managers {
+		if _, ok := userByID[manager.UserID]; ok {
+			matched = append(matched, manager)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+	matched = matched[min(skip, len(matched)):min(skip+limit, len(matched))]
+
+	courtIDs, vendorIDs := []primitive.ObjectID{}, []primitive.ObjectID{}
+	for _, manager := range matched {
+		courtIDs = append(courtIDs, manager.Courts()...)
+		vendorIDs = append(vendorIDs, manager.VendorID)
+	}
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to fetch managers data")
+		return
+	}
+	courtByID := make(map[primitive.ObjectID]models.FoodCourt, len(courts))
+	for _, court := range courts {
+		courtByID[court.ID] = court
+	}
+	vendors, err := repos.Vendors.ListByIDs(ctx, vendorIDs)
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to fetch managers data")
+		return
+	}
+	vendorByID := make(map[primitive.ObjectID]models.Vendor, len(vendors))
+	for _, vendor := range vendors {
+		vendorByID[vendor.ID] = vendor
+	}
+
+	results := []bson.M{}
+	for _, manager := range matched {
+		user := userByID[manager.UserID]
+
+		foodCourt := bson.M{}
+		foodCourts := []bson.M{}
+		for _, courtID := range manager.Courts() {
+			court, ok := courtByID[courtID]
+			if !ok {
+				continue
+			}
+			if len(foodCourts) == 0 {
+				foodCourt = bson.M{"id": court.ID, "name": court.Name}
+			}
+			foodCourts = append(foodCourts, bson.M{"id": court.ID, "name": court.Name})
+		}
+
+		result := bson.M{
+			"_id":        manager.ID,
+			"id":         manager.ID,
+			"name":       user.Name,
+			"email":      user.Email,
+			"contactNo":  manager.ContactNo,
+			"isActive":   manager.IsActive,
+			"createdAt":  manager.CreatedAt,
+			"foodCourt":  foodCourt,
+			"foodCourts": foodCourts,
+			"vendors":    []string{},
+			"vendor":     bson.M{},
+		}
+		if vendor, ok := vendorByID[manager.VendorID]; ok {
+			result["vendorId"] = vendor.UserID
+			result["vendors"] = []string{vendor.ShopName}
+			result["vendor"] = bson.M{"id": vendor.ID, "name": vendor.ShopName}
+		}
+		results = append(results, result)
+	}
+
+	total, _ := repos.Managers.Count(ctx)
 
 	utils.RespondSuccess(c, 200, "Managers fetched successfully", gin.H{
 		"managers": results,
@@ -1138,65 +1092,55 @@ func GetAllManagers(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func GetAdminDashboardStats(c *gin.Context, db *mongo.Database) {
+func GetAdminDashboardStats(c *gin.Context, repos *repository.Repositories) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	fcCollection := db.Collection("foodcourts")
-	fcCursor, err := fcCollection.Find(ctx, bson.M{"isOpen": true}, options.Find().SetLimit(10))
-
-	var openFoodCourts []bson.M = []bson.M{}
-	if err == nil {
-		fcCursor.All(ctx, &openFoodCourts)
+	openFoodCourts := []bson.M{}
+	if courts, err := repos.FoodCourts.ListPage(ctx, repository.FoodCourtQuery{OpenOnly: true}, 0, 10); err == nil {
+		for _, court := range courts {
+			openFoodCourts = append(openFoodCourts, document(court))
+		}
 	}
 
-	itemLinkCollection := db.Collection("itemfoodcourts")
-
-	itemPipeline := []bson.M{
-		{"$sort": bson.M{"createdAt": -1}},
-
-		{"$limit": 5},
-
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item_details",
-		}},
-		{"$unwind": "$item_details"},
-
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "fc",
-		}},
-		{"$unwind": "$fc"},
-
-		{"$project": bson.M{
-			"id":   "$_id",
-			"name": "$item_details.name",
-
-			"isVeg":    "$item_details.isVeg",
-			"category": "$item_details.category",
-			"price":    "$price",
-
-			"status":        1,
-			"createdAt":     1,
-			"foodCourtName": "$fc.name",
-		}},
-	}
+	recentItems := []bson.M{}
+	if listings, err := repos.ItemFoodCourts.ListRecent(ctx, 5); err == nil {
+		itemIDs, courtIDs := []primitive.ObjectID{}, []primitive.ObjectID{}
+		for _, listing := range listings {
+			itemIDs = append(itemIDs, listing.ItemID)
+			courtIDs = append(courtIDs, listing.FoodCourtID)
+		}
+		items, _ := repos.Items.ListByIDs(ctx, itemIDs)
+		itemByID := make(map[primitive.ObjectID]models.Item, len(items))
+		for _, item := range items {
+			itemByID[item.ID] = item
+		}
+		courts, _ := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+		courtByID := make(map[primitive.ObjectID]models.FoodCourt, len(courts))
+		for _, court := range courts {
+			courtByID[court.ID] = court
+		}
 
-	itemCursor, err := itemLinkCollection.Aggregate(ctx, itemPipeline)
-	var recentItems []bson.M = []bson.M{}
-	if err == nil {
-		itemCursor.All(ctx, &recentItems)
+		for _, listing := range listings {
+			item, ok := itemByID[listing.ItemID]
+			court, found := courtByID[listing.FoodCourtID]
+			if !ok || !found {
+				continue
+			}
+			recentItem := pick(document(listing), "_id", "price", "status", "createdAt")
+			recentItem["id"] = listing.ID
+			recentItem["name"] = item.Name
+			recentItem["isVeg"] = item.IsVeg
+			recentItem["category"] = item.Category
+			recentItem["foodCourtName"] = court.Name
+			recentItems = append(recentItems, recentItem)
+		}
 	}
 
-	totalVendors, _ := db.Collection("vendors").CountDocuments(ctx, bson.M{})
-	totalManagers, _ := db.Collection("managers").CountDocuments(ctx, bson.M{})
+	totalVendors, _ := repos.Vendors.Count(ctx)
+	totalManagers, _ := repos.Managers.Count(ctx)
 
-	totalItems, _ := db.Collection("items").CountDocuments(ctx, bson.M{})
+	totalItems, _ := repos.Items.Count(ctx)
 
 	utils.RespondSuccess(c, 200, "Dashboard stats retrieved", gin.H{
 		"stats": gin.H{
@@ -1209,7 +1153,7 @@ func GetAdminDashboardStats(c *gin.Context, db *mongo.Database) {
 	})
 }
 
-func UpdateVendorStatus(c *gin.Context, db *mongo.Database) {
+func UpdateVendorStatus(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	userID := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -1228,43 +1172,165 @@ func UpdateVendorStatus(c *gin.Context, db *mongo.Database) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	var vendor models.Vendor
-	err = db.Collection("vendors").FindOne(ctx, bson.M{"user_id": objID}).Decode(&vendor)
+	grant, revoke := []string{permissions.RoleVendor}, []string(nil)
+	if input.Role == "user" {
+		grant, revoke = nil, []string{permissions.RoleVendor}
+	}
+	setRoles := func(ctx context.Context) error {
+		_, err := updateUserRoles(ctx, db, repos, objID, grant, revoke)
+		return err
+	}
 
-	if input.Role == "user" && err == nil {
+	vendor, err := repos.Vendors.FindByUser(ctx, objID)
 
-		itemCursor, _ := db.Collection("items").Find(ctx, bson.M{"vendor_id": vendor.ID})
-		var items []models.Item
-		itemCursor.All(ctx, &items)
+	// Demoting a vendor trashes the shop and changes the roles in one
+	// transaction.
+	var removed *cascade.Report
+	if input.Role == "user" && err == nil {
+		removed, err = cascadeFor(c, db).Then(func(sc mongo.SessionContext, _ *cascade.Report) error {
+			return setRoles(sc)
+		}).TrashVendor(ctx, vendor.ID)
+		if err != nil && err != cascade.ErrNotFound {
+			utils.RespondError(c, 500, "Failed to remove vendor data")
+			return
+		}
+	}
 
-		var itemIDs []primitive.ObjectID
-		for _, item := range items {
-			itemIDs = append(itemIDs, item.ID)
+	if removed == nil {
+		if err := setRoles(ctx); err != nil {
+			utils.RespondError(c, 500, "Failed to update user role")
+			return
 		}
+	}
+	actor.Invalidate(objID)
+
+	utils.RespondSuccess(c, 200, "Status updated and related data cleaned", gin.H{"removed": removed})
+}
+
+func UnlockUserLogin(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	adminIDHex, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, 401, "Unauthorized")
+		return
+	}
+
+	adminID, err := primitive.ObjectIDFromHex(adminIDHex.(string))
+	if err != nil {
+		utils.RespondError(c, 400, "Invalid admin ID")
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, 400, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	user, err := repos.Users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, 404, "User not found")
+		return
+	} else if err != nil {
+		utils.RespondError(c, 500, "Database error")
+		return
+	}
 
-		if len(itemIDs) > 0 {
+	result, err := db.Collection("login_throttles").DeleteOne(ctx, bson.M{"_id": loginEmailKey(user.Email)})
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to unlock account")
+		return
+	}
 
-			db.Collection("itemfoodcourts").DeleteMany(ctx, bson.M{"item_id": bson.M{"$in": itemIDs}})
+	recordAudit(ctx, db, models.AuditLog{
+		Action:       models.AuditAccountUnlocked,
+		ActorID:      &adminID,
+		TargetUserID: &user.ID,
+		Email:        user.Email,
+		IP:           c.ClientIP(),
+	})
+
+	utils.RespondSuccess(c, 200, "Account unlocked successfully", gin.H{
+		"id":           user.ID.Hex(),
+		"email":        user.Email,
+		"wasThrottled": result.DeletedCount > 0,
+	})
+}
 
-			db.Collection("items").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": itemIDs}})
+func GetAuditLogs(c *gin.Context, db *mongo.Database) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	filter := bson.M{}
+	if action := c.Query("action"); action != "" {
+		filter["action"] = action
+	}
+	if userIDParam := c.Query("userId"); userIDParam != "" {
+		userID, err := primitive.ObjectIDFromHex(userIDParam)
+		if err != nil {
+			utils.RespondError(c, 400, "Invalid user ID")
+			return
 		}
+		filter["target_user_id"] = userID
+	}
 
-		db.Collection("managers").DeleteMany(ctx, bson.M{"vendor_id": vendor.ID})
+	ctx := context.Background()
+	collection := db.Collection("audit_logs")
 
-		db.Collection("vendors").DeleteOne(ctx, bson.M{"_id": vendor.ID})
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"createdAt": -1})
 
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to fetch audit logs")
+		return
 	}
+	defer cursor.Close(ctx)
 
-	result, err := db.Collection("users").UpdateOne(
-		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"role": input.Role, "updatedAt": time.Now()}},
-	)
+	logs := []models.AuditLog{}
+	if err := cursor.All(ctx, &logs); err != nil {
+		utils.RespondError(c, 500, "Error decoding audit logs")
+		return
+	}
 
-	if err != nil || result.MatchedCount == 0 {
-		utils.RespondError(c, 500, "Failed to update user role")
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.RespondError(c, 500, "Failed to count audit logs")
 		return
 	}
 
-	utils.RespondSuccess(c, 200, "Status updated and related data cleaned", nil)
+	utils.RespondSuccess(c, 200, "Audit logs fetched successfully", gin.H{
+		"logs": logs,
+		"meta": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func validateOpeningSchedule(schedule *models.OpeningSchedule) error {
+	if err := utils.Validate.Struct(schedule); err != nil {
+		return err
+	}
+	return schedule.Validate()
+}
+
+func validateMealSlots(slots []models.MealSlot) error {
+	for _, slot := range slots {
+		if err := utils.Validate.Struct(slot); err != nil {
+			return err
+		}
+	}
+	return models.ValidateMealSlots(slots)
 }
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+func TestAddVendorToFoodCourt(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	court := models.FoodCourt{Name: "South Block", Location: "Campus"}
+	if err := f.repos.FoodCourts.Create(ctx, &court); err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(foodCourtID primitive.ObjectID) int {
+		return serve(t, request{params: gin.Params{
+			{Key: "foodCourtId", Value: foodCourtID.Hex()},
+			{Key: "vendorId", Value: f.vendor.ID.Hex()},
+		}}, func(c *gin.Context) { AddVendorToFoodCourt(c, f.repos) }).Code
+	}
+
+	if code := add(court.ID); code != http.StatusOK {
+		t.Fatalf("first add: status = %d, want %d", code, http.StatusOK)
+	}
+	stored, err := f.repos.FoodCourts.FindByID(ctx, court.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(stored.VendorIDs, f.vendor.ID) {
+		t.Fatalf("vendor_ids = %v, want %s", stored.VendorIDs, f.vendor.ID.Hex())
+	}
+
+	if code := add(court.ID); code != http.StatusConflict {
+		t.Errorf("second add: status = %d, want %d", code, http.StatusConflict)
+	}
+	if code := add(primitive.NewObjectID()); code != http.StatusNotFound {
+		t.Errorf("unknown court: status = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestCreateFoodCourtRejectsDuplicateName(t *testing.T) {
+	f := newFixture(t)
+	adminID := primitive.NewObjectID()
+
+	create := func() int {
+		return serve(t, request{
+			userID: adminID,
+			body:   gin.H{"name": "West Block", "location": "Campus"},
+		}, func(c *gin.Context) { CreateFoodCourt(c, f.repos) }).Code
+	}
+
+	if code := create(); code != http.StatusCreated {
+		t.Fatalf("first create: status = %d, want %d", code, http.StatusCreated)
+	}
+	if code := create(); code != http.StatusBadRequest {
+		t.Errorf("duplicate create: status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
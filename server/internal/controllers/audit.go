@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// recordAudit stores a security-relevant event. A failure is logged rather
+// than returned, as it must never block the action being audited.
+func recordAudit(ctx context.Context, db *mongo.Database, entry models.AuditLog) {
+	entry.CreatedAt = time.Now()
+	if _, err := db.Collection("audit_logs").InsertOne(ctx, entry); err != nil {
+		log.Printf("Failed to write audit log %q: %v", entry.Action, err)
+	}
+}
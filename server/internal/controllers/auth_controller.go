@@ -2,14 +2,16 @@ package controllers
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -26,14 +28,15 @@ func setRefreshCookie(c *gin.Context, token string, maxAge int) {
 	})
 }
 
-func Register(c *gin.Context, db *mongo.Database) {
+func Register(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
 		utils.RespondError(c, 400, "Invalid request body")
 		return
 	}
 
-	user.Role = "user"
+	user.Role = permissions.RoleUser
+	user.Roles = []string{permissions.RoleUser}
 	if err := utils.Validate.Struct(user); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
 		for _, fieldErr := range validationErrors {
@@ -45,13 +48,13 @@ func Register(c *gin.Context, db *mongo.Database) {
 		}
 	}
 
-	collection := db.Collection("users")
-	count, err := collection.CountDocuments(context.TODO(), bson.M{"email": user.Email})
+	// Trashed accounts keep their email so they can still be restored.
+	inUse, err := repos.Users.EmailInUse(context.TODO(), user.Email, primitive.NilObjectID)
 	if err != nil {
 		utils.RespondError(c, 500, "Database error")
 		return
 	}
-	if count > 0 {
+	if inUse {
 		utils.RespondError(c, 409, "Email already registered")
 		return
 	}
@@ -67,21 +70,36 @@ func Register(c *gin.Context, db *mongo.Database) {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
-	res, err := collection.InsertOne(context.TODO(), user)
+	err = repos.Users.Create(context.TODO(), &user)
+	if mongo.IsDuplicateKeyError(err) {
+		// Lost a race with another sign-up for the same email.
+		utils.RespondError(c, 409, "Email already registered")
+		return
+	}
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to create user")
 		return
 	}
 
+	if err := sendVerificationEmail(context.TODO(), db, user); err != nil {
+		log.Printf("Failed to issue verification token for %s: %v", user.Email, err)
+	}
+
+	invites, err := linkManagerInvites(context.TODO(), db, user)
+	if err != nil {
+		log.Printf("Failed to link manager invites for %s: %v", user.Email, err)
+	}
+
 	utils.RespondSuccess(c, 201, "User registered successfully", gin.H{
-		"id":    res.InsertedID,
-		"name":  user.Name,
-		"email": user.Email,
-		"role":  user.Role,
+		"id":             user.ID,
+		"name":           user.Name,
+		"email":          user.Email,
+		"role":           user.Role,
+		"managerInvites": invites,
 	})
 }
 
-func Login(c *gin.Context, db *mongo.Database) {
+func Login(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	var creds struct {
 		Email    string `json:"email" binding:"required,email"`
 		Password string `json:"password" binding:"required,min=6"`
@@ -92,43 +110,68 @@ func Login(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	collection := db.Collection("users")
-	var user models.User
-	if err := collection.FindOne(context.TODO(), bson.M{"email": creds.Email}).Decode(&user); err != nil {
+	ctx := context.TODO()
+	ip := c.ClientIP()
+
+	wait, err := checkLoginThrottle(ctx, db, creds.Email, ip)
+	if err != nil {
+		utils.RespondError(c, 500, "Database error")
+		return
+	}
+	if wait > 0 {
+		respondLoginThrottled(c, wait)
+		return
+	}
+
+	user, err := repos.Users.FindByEmail(ctx, creds.Email)
+	if err != nil && err != repository.ErrNotFound {
+		utils.RespondError(c, 500, "Database error")
+		return
+	}
+
+	if err == repository.ErrNotFound {
+		utils.CheckPassword(dummyPasswordHash(), creds.Password)
+		recordLoginFailure(ctx, db, creds.Email, ip, nil)
 		utils.RespondError(c, 400, "Invalid email or password")
 		return
 	}
 
 	if !utils.CheckPassword(user.Password, creds.Password) {
+		recordLoginFailure(ctx, db, creds.Email, ip, &user.ID)
 		utils.RespondError(c, 400, "Invalid email or password")
 		return
 	}
 
-	accessToken, err := utils.GenerateAccessToken(user.ID.Hex(), user.Role)
+	clearLoginFailures(ctx, db, creds.Email)
+
+	accessToken, err := utils.GenerateAccessToken(user.ID.Hex(), user.Role, user.EffectiveRoles())
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to generate access token")
 		return
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user.ID.Hex(), user.Role)
+	refreshToken, err := startRefreshSession(context.TODO(), db, c, user)
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to generate refresh token")
 		return
 	}
-	setRefreshCookie(c, refreshToken, 7*24*60*60)
+	setRefreshCookie(c, refreshToken, int(utils.RefreshTokenTTL.Seconds()))
 
 	utils.RespondSuccess(c, 200, "Login successful", gin.H{
 		"access_token": accessToken,
 		"user": gin.H{
-			"id":    user.ID.Hex(),
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":            user.ID.Hex(),
+			"name":          user.Name,
+			"email":         user.Email,
+			"role":          user.Role,
+			"roles":         user.EffectiveRoles(),
+			"permissions":   permissions.ForRoles(user.EffectiveRoles()).Slice(),
+			"emailVerified": user.EmailVerified,
 		},
 	})
 }
 
-func Refresh(c *gin.Context, db *mongo.Database) {
+func Refresh(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	var req struct{}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid request body")
@@ -147,37 +190,241 @@ func Refresh(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	accessToken, err := utils.GenerateAccessToken(claims.UserID, claims.Role)
+	ctx := context.TODO()
+
+	// The role always comes from the database so role changes apply on the
+	// next refresh rather than when the old token expires.
+	userID, _ := primitive.ObjectIDFromHex(claims.UserID)
+	user, err := repos.Users.FindByID(ctx, userID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Could not generate token")
+		if sessionID, err := primitive.ObjectIDFromHex(claims.SessionID); err == nil {
+			revokeSession(ctx, db, sessionID, models.SessionRevokedUserDeleted)
+		}
+		setRefreshCookie(c, "", -1)
+		utils.RespondError(c, http.StatusUnauthorized, "User not found")
 		return
 	}
 
-	var user models.User
-	oid, err := primitive.ObjectIDFromHex(claims.UserID)
+	// The new tokens are signed before the session moves on to them, so a
+	// failure here leaves the old refresh token usable.
+	jti := newJTI()
+	accessToken, err := utils.GenerateAccessToken(user.ID.Hex(), user.Role, user.EffectiveRoles())
 	if err != nil {
-		utils.RespondError(c, http.StatusUnauthorized, "Invalid user ID in token")
+		utils.RespondError(c, http.StatusInternalServerError, "Could not generate token")
 		return
 	}
-	err = db.Collection("users").FindOne(context.TODO(), bson.M{"_id": oid}).Decode(&user)
+
+	refreshToken, err := utils.GenerateRefreshToken(user.ID.Hex(), user.Role, claims.SessionID, jti)
 	if err != nil {
-		utils.RespondError(c, http.StatusUnauthorized, "User not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Could not generate token")
+		return
+	}
 
+	session, err := rotateRefreshSession(ctx, db, c, claims, jti)
+	switch err {
+	case nil:
+	case errSessionRaced:
+		utils.RespondError(c, http.StatusConflict, "Token was just refreshed, please retry")
+		return
+	case errSessionReused:
+		setRefreshCookie(c, "", -1)
+		utils.RespondError(c, http.StatusUnauthorized, "Session revoked, please log in again")
+		return
+	case errSessionInvalid:
+		setRefreshCookie(c, "", -1)
+		utils.RespondError(c, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	default:
+		utils.RespondError(c, http.StatusInternalServerError, "Could not refresh session")
 		return
 	}
+
+	setRefreshCookie(c, refreshToken, int(time.Until(session.ExpiresAt).Seconds()))
+
 	utils.RespondSuccess(c, http.StatusOK, "Token refreshed successfully", gin.H{
 		"access_token": accessToken,
 		"user": gin.H{
-			"id":    user.ID.Hex(),
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":          user.ID.Hex(),
+			"name":        user.Name,
+			"email":       user.Email,
+			"role":        user.Role,
+			"roles":       user.EffectiveRoles(),
+			"permissions": permissions.ForRoles(user.EffectiveRoles()).Slice(),
 		},
 	})
 }
 
 func Logout(c *gin.Context, db *mongo.Database) {
+	if sessionID := currentSessionID(c); !sessionID.IsZero() {
+		revokeSession(context.TODO(), db, sessionID, models.SessionRevokedLogout)
+	}
 
 	setRefreshCookie(c, "", -1)
 	utils.RespondSuccess(c, 200, "Logged out successfully", nil)
 }
+
+func LogoutAll(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := revokeUserSessions(context.TODO(), db, userObjID, models.SessionRevokedLogoutAll); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	setRefreshCookie(c, "", -1)
+	utils.RespondSuccess(c, 200, "Logged out of all devices", nil)
+}
+
+func ForgotPassword(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "A valid email is required")
+		return
+	}
+
+	user, err := repos.Users.FindByEmail(context.TODO(), request.Email)
+	if err == nil {
+		if err := sendPasswordResetEmail(context.TODO(), db, user); err != nil {
+			log.Printf("Failed to issue password reset token for %s: %v", user.Email, err)
+		}
+	} else if err != repository.ErrNotFound {
+		log.Printf("Failed to look up %s for password reset: %v", request.Email, err)
+	}
+
+	// Same answer whether or not the email has an account.
+	utils.RespondSuccess(c, http.StatusOK, "If that email is registered, a reset link has been sent", nil)
+}
+
+func ResetPassword(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	var request struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"newPassword" binding:"required,min=6"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Token and a new password of at least 6 characters are required")
+		return
+	}
+
+	ctx := context.TODO()
+
+	authToken, err := consumeAuthToken(ctx, db, request.Token, models.AuthTokenPasswordReset)
+	if err == errAuthTokenInvalid {
+		utils.RespondError(c, http.StatusBadRequest, "Reset link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to verify reset token")
+		return
+	}
+
+	hashed, err := utils.HashPassword(request.NewPassword)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to hash password")
+		return
+	}
+
+	// Receiving the link proves ownership of the address as well.
+	err = repos.Users.UpdateWithEmail(ctx, authToken.UserID, authToken.Email, repository.Fields{
+		"password":      hashed,
+		"emailVerified": true,
+		"updatedAt":     time.Now(),
+	})
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusBadRequest, "Reset link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update password")
+		return
+	}
+
+	if err := revokeUserSessions(ctx, db, authToken.UserID, models.SessionRevokedPasswordChange); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Password reset successfully, please log in", nil)
+}
+
+func VerifyEmail(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	ctx := context.TODO()
+
+	authToken, err := consumeAuthToken(ctx, db, request.Token, models.AuthTokenEmailVerification)
+	if err == errAuthTokenInvalid {
+		utils.RespondError(c, http.StatusBadRequest, "Verification link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to verify token")
+		return
+	}
+
+	// The email filter keeps a link sent to an old address from verifying a
+	// new one.
+	err = repos.Users.UpdateWithEmail(ctx, authToken.UserID, authToken.Email, repository.Fields{
+		"emailVerified": true,
+		"updatedAt":     time.Now(),
+	})
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusBadRequest, "Verification link is invalid or has expired")
+		return
+	}
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+func ResendVerificationEmail(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := repos.Users.FindByID(context.TODO(), userObjID)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if user.EmailVerified {
+		utils.RespondError(c, http.StatusConflict, "Email is already verified")
+		return
+	}
+
+	if err := sendVerificationEmail(context.TODO(), db, user); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Verification email sent", nil)
+}
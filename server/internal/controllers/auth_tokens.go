@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/mailer"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+const mailSendTimeout = 30 * time.Second
+
+var errAuthTokenInvalid = errors.New("token is invalid, expired or already used")
+
+var mailSender mailer.Mailer
+
+// SetMailer sets how account emails are delivered.
+func SetMailer(m mailer.Mailer) {
+	mailSender = m
+}
+
+// sendMail delivers in the background so request latency does not depend
+// on the mail server, nor reveal whether an address has an account.
+func sendMail(msg mailer.Message) {
+	if mailSender == nil {
+		log.Println("Mailer not initialized")
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := mailSender.Send(ctx, msg); err != nil {
+			log.Printf("Failed to send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+func frontendURL(path string) string {
+	return strings.TrimRight(os.Getenv("FRONTEND_URL"), "/") + path
+}
+
+func frontendLink(path, token string) string {
+	return frontendURL(path) + "?token=" + url.QueryEscape(token)
+}
+
+// issueAuthToken creates a token for purpose and discards any earlier unused
+// one, so only the most recent email link works.
+func issueAuthToken(ctx context.Context, db *mongo.Database, user models.User, purpose string) (string, error) {
+	token, tokenHash, err := utils.GenerateURLToken()
+	if err != nil {
+		return "", err
+	}
+
+	collection := db.Collection("auth_tokens")
+	if _, err := collection.DeleteMany(ctx, bson.M{
+		"user_id": user.ID,
+		"purpose": purpose,
+		"usedAt":  bson.M{"$exists": false},
+	}); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = collection.InsertOne(ctx, models.AuthToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		Email:     user.Email,
+		ExpiresAt: now.Add(models.AuthTokenTTL[purpose]),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeAuthToken marks a token used and returns it. It fails the same way
+// for unknown, expired and already used tokens.
+func consumeAuthToken(ctx context.Context, db *mongo.Database, token, purpose string) (*models.AuthToken, error) {
+	now := time.Now()
+
+	var authToken models.AuthToken
+	err := db.Collection("auth_tokens").FindOneAndUpdate(ctx,
+		bson.M{
+			"tokenHash": utils.HashToken(token),
+			"purpose":   purpose,
+			"usedAt":    bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"usedAt": now}},
+	).Decode(&authToken)
+	if err == mongo.ErrNoDocuments {
+		return nil, errAuthTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &authToken, nil
+}
+
+func sendVerificationEmail(ctx context.Context, db *mongo.Database, user models.User) error {
+	token, err := issueAuthToken(ctx, db, user, models.AuthTokenEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Infybite email",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Confirm your email address by opening the link below:\n\n" +
+			frontendLink("/verify-email", token) + "\n\n" +
+			"The link expires in 48 hours.",
+	})
+	return nil
+}
+
+func sendPasswordResetEmail(ctx context.Context, db *mongo.Database, user models.User) error {
+	token, err := issueAuthToken(ctx, db, user, models.AuthTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Infybite password",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Someone asked to reset the password for this account. If it was you, open the link below:\n\n" +
+			frontendLink("/reset-password", token) + "\n\n" +
+			"The link expires in 1 hour and can be used once. If you did not ask for this, ignore this email.",
+	})
+	return nil
+}
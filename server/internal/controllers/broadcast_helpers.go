@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// The helpers below enrich broadcast payloads with names and prices. A
+// failed lookup still sends the event, just with fewer fields filled in.
+
+func broadcastItemFoodCourt(repos *repository.Repositories, itemFoodCourt models.ItemFoodCourt, action string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := utils.ItemFoodCourtEvent{ItemFoodCourt: itemFoodCourt}
+
+	if item, err := repos.Items.FindByID(ctx, itemFoodCourt.ItemID); err == nil {
+		event.ItemName = item.Name
+		event.Category = item.Category
+		event.IsVeg = item.IsVeg
+		event.BasePrice = item.BasePrice
+		event.VendorID = item.VendorID
+		event.ShopName = lookupShopName(ctx, repos.Vendors, item.VendorID)
+	} else {
+		log.Printf("Broadcast enrichment: item %s not found: %v", itemFoodCourt.ItemID.Hex(), err)
+	}
+
+	event.EffectivePrice = event.BasePrice
+	if itemFoodCourt.Price != nil {
+		event.EffectivePrice = *itemFoodCourt.Price
+	}
+
+	utils.BroadcastItemFoodCourtUpdate(event, action)
+}
+
+func broadcastItem(repos *repository.Repositories, item models.Item, action string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := utils.ItemEvent{
+		Item:         item,
+		ShopName:     lookupShopName(ctx, repos.Vendors, item.VendorID),
+		FoodCourtIDs: []primitive.ObjectID{},
+	}
+
+	listings, err := repos.ItemFoodCourts.ListByItem(ctx, item.ID)
+	if err != nil {
+		log.Printf("Broadcast enrichment: food courts for item %s: %v", item.ID.Hex(), err)
+	}
+	for _, listing := range listings {
+		event.FoodCourtIDs = append(event.FoodCourtIDs, listing.FoodCourtID)
+	}
+
+	utils.BroadcastItemUpdate(event, action)
+}
+
+func broadcastFoodCourt(foodCourt models.FoodCourt, action string) {
+	utils.BroadcastFoodCourtUpdate(utils.NewFoodCourtEvent(foodCourt), action)
+}
+
+func broadcastFoodCourtVendor(repos *repository.Repositories, foodCourtID, vendorID primitive.ObjectID, action string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := utils.FoodCourtVendorEvent{
+		FoodCourtID: foodCourtID,
+		VendorID:    vendorID,
+		ShopName:    lookupShopName(ctx, repos.Vendors, vendorID),
+	}
+
+	if foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtID); err == nil {
+		event.FoodCourtName = foodCourt.Name
+	}
+
+	utils.BroadcastFoodCourtVendorUpdate(event, action)
+}
+
+func lookupShopName(ctx context.Context, vendors repository.Vendors, vendorID primitive.ObjectID) string {
+	vendor, err := vendors.FindByID(ctx, vendorID)
+	if err != nil {
+		return ""
+	}
+	return vendor.ShopName
+}
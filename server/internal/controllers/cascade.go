@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/cascade"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+// cascadeFor returns the cascade service acting as the request's user.
+func cascadeFor(c *gin.Context, db *mongo.Database) *cascade.Service {
+	actor := cascade.Actor{Role: c.GetString("role")}
+	actor.UserID, _ = primitive.ObjectIDFromHex(c.GetString("userID"))
+	return cascade.New(db, actor)
+}
+
+// demoteFormerManagers takes the manager role from users a cascade left
+// without any manager record. Pass it to Then so the roles change in the
+// cascade's transaction.
+func demoteFormerManagers(db *mongo.Database, repos *repository.Repositories) func(sc mongo.SessionContext, r *cascade.Report) error {
+	return func(sc mongo.SessionContext, r *cascade.Report) error {
+		for _, userID := range r.FormerManagers {
+			if _, err := updateUserRoles(sc, db, repos, userID, nil, []string{permissions.RoleManager}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
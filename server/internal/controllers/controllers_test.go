@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	utils.InitValidator()
+}
+
+// request is a handler call under test: the route params, query, body and
+// the authenticated user it runs as.
+type request struct {
+	params gin.Params
+	query  string
+	body   interface{}
+	userID primitive.ObjectID
+	role   string
+	actor  *actor.Actor
+}
+
+// serve runs handler on r and returns the recorded response.
+func serve(t *testing.T, r request, handler func(c *gin.Context)) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	if r.body != nil {
+		if err := json.NewEncoder(&body).Encode(r.body); err != nil {
+			t.Fatalf("encoding body: %v", err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("POST", "/?"+r.query, &body)
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = r.params
+	if !r.userID.IsZero() {
+		c.Set("userID", r.userID.Hex())
+	}
+	if r.role != "" {
+		c.Set("role", r.role)
+	}
+	if r.actor != nil {
+		actor.Set(c, r.actor)
+	}
+
+	handler(c)
+	return recorder
+}
+
+// response decodes the data of a successful response into data, failing
+// the test on any other status.
+func response(t *testing.T, recorder *httptest.ResponseRecorder, status int, data interface{}) {
+	t.Helper()
+
+	if recorder.Code != status {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, status, recorder.Body)
+	}
+	if data == nil {
+		return
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if err := json.Unmarshal(envelope.Data, data); err != nil {
+		t.Fatalf("decoding data: %v", err)
+	}
+}
+
+// fixture is a vendor with one item listed in one food court.
+type fixture struct {
+	repos   *repository.Repositories
+	user    models.User
+	vendor  models.Vendor
+	court   models.FoodCourt
+	item    models.Item
+	listing models.ItemFoodCourt
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now()
+	f := &fixture{repos: repository.NewMemory()}
+
+	f.user = models.User{Name: "Asha", Email: "asha@example.com", Role: permissions.RoleVendor, CreatedAt: now, UpdatedAt: now}
+	if err := f.repos.Users.Create(ctx, &f.user); err != nil {
+		t.Fatal(err)
+	}
+	f.vendor = models.Vendor{UserID: f.user.ID, ShopName: "Dosa Corner", CreatedAt: now, UpdatedAt: now}
+	if err := f.repos.Vendors.Create(ctx, &f.vendor); err != nil {
+		t.Fatal(err)
+	}
+	f.court = models.FoodCourt{Name: "North Block", Location: "Campus", IsOpen: true, VendorIDs: []primitive.ObjectID{f.vendor.ID}, CreatedAt: now, UpdatedAt: now}
+	if err := f.repos.FoodCourts.Create(ctx, &f.court); err != nil {
+		t.Fatal(err)
+	}
+	f.item = models.Item{VendorID: f.vendor.ID, Name: "Masala Dosa", BasePrice: 60, Category: "breakfast", IsVeg: true, CreatedAt: now, UpdatedAt: now}
+	if err := f.repos.Items.Create(ctx, &f.item); err != nil {
+		t.Fatal(err)
+	}
+	f.listing = models.ItemFoodCourt{ItemID: f.item.ID, FoodCourtID: f.court.ID, Status: "available", TimeSlot: "breakfast", IsActive: true, CreatedAt: now, UpdatedAt: now}
+	if err := f.repos.ItemFoodCourts.Create(ctx, &f.listing); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// addUser stores a plain user.
+func (f *fixture) addUser(t *testing.T, name, email string) models.User {
+	t.Helper()
+
+	user := models.User{Name: name, Email: email, Role: permissions.RoleUser, EmailVerified: true, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := f.repos.Users.Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// addManager makes user a manager for the fixture vendor in its court.
+func (f *fixture) addManager(t *testing.T, user models.User) models.Manager {
+	t.Helper()
+
+	manager := models.Manager{UserID: user.ID, VendorID: f.vendor.ID, FoodCourtIDs: []primitive.ObjectID{f.court.ID}, ContactNo: "+919876543210", IsActive: true, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := f.repos.Managers.Create(context.Background(), &manager); err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func (f *fixture) vendorActor() *actor.Actor {
+	vendor := f.vendor
+	return &actor.Actor{UserID: f.user.ID, VendorID: vendor.ID, Vendor: &vendor}
+}
+
+func (f *fixture) managerActor(manager models.Manager) *actor.Actor {
+	a := &actor.Actor{UserID: manager.UserID, Managers: []models.Manager{manager}}
+	scoped, _ := a.ForFoodCourt(primitive.NilObjectID)
+	return scoped
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// document returns v as MongoDB stores it, keyed by bson field names. Some
+// responses hand out raw documents, _id keys included, and are built from
+// the models this way.
+func document(v interface{}) bson.M {
+	doc := bson.M{}
+	raw, err := bson.Marshal(v)
+	if err == nil {
+		err = bson.Unmarshal(raw, &doc)
+	}
+	if err != nil {
+		log.Printf("Encoding %T as a document: %v", v, err)
+	}
+	return doc
+}
+
+// pick returns the named fields src has, the way a $project that includes
+// them would.
+func pick(src bson.M, fields ...string) bson.M {
+	return copyFields(bson.M{}, src, fields...)
+}
+
+// copyFields copies the named fields src has into dst and returns dst.
+func copyFields(dst, src bson.M, fields ...string) bson.M {
+	for _, field := range fields {
+		if value, ok := src[field]; ok {
+			dst[field] = value
+		}
+	}
+	return dst
+}
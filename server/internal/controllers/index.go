@@ -0,0 +1,13 @@
+package controllers
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// indexBy maps records by the ID key returns, for joining records that
+// were read separately.
+func indexBy[T any](records []T, key func(T) primitive.ObjectID) map[primitive.ObjectID]T {
+	index := make(map[primitive.ObjectID]T, len(records))
+	for _, record := range records {
+		index[key(record)] = record
+	}
+	return index
+}
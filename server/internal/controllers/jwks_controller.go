@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS publishes the token verification keys for other services. It is
+// written as a bare JWK Set, not the usual response envelope, so standard
+// JWT libraries can consume it.
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": utils.JWKS()})
+}
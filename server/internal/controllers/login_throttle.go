@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// dummyPasswordHash is compared against when the email is unknown, so a
+// missing account costs the same bcrypt time as a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := utils.HashPassword("infybite-timing-equaliser")
+	if err != nil {
+		log.Printf("Failed to create dummy password hash: %v", err)
+	}
+	return hash
+})
+
+func loginEmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// checkLoginThrottle returns how long the caller has to wait before trying
+// again, taking the longer of the email and IP restrictions. Unknown emails
+// are throttled exactly like real ones.
+func checkLoginThrottle(ctx context.Context, db *mongo.Database, email, ip string) (time.Duration, error) {
+	cursor, err := db.Collection("login_throttles").Find(ctx, bson.M{
+		"_id": bson.M{"$in": []string{loginEmailKey(email), loginIPKey(ip)}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var throttles []models.LoginThrottle
+	if err := cursor.All(ctx, &throttles); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, throttle := range throttles {
+		policy := models.EmailLoginPolicy
+		if strings.HasPrefix(throttle.ID, "ip:") {
+			policy = models.IPLoginPolicy
+		}
+		if retry := policy.RetryAfter(throttle, now); retry > wait {
+			wait = retry
+		}
+	}
+	return wait, nil
+}
+
+// recordLoginFailure counts a failed attempt against both keys and locks
+// any key that crosses its threshold.
+func recordLoginFailure(ctx context.Context, db *mongo.Database, email, ip string, userID *primitive.ObjectID) {
+	if err := bumpLoginThrottle(ctx, db, loginEmailKey(email), models.EmailLoginPolicy, models.AuditLog{
+		Action:       models.AuditAccountLocked,
+		TargetUserID: userID,
+		Email:        strings.ToLower(strings.TrimSpace(email)),
+		IP:           ip,
+	}); err != nil {
+		log.Printf("Failed to record login failure for email: %v", err)
+	}
+
+	if err := bumpLoginThrottle(ctx, db, loginIPKey(ip), models.IPLoginPolicy, models.AuditLog{
+		Action: models.AuditIPLocked,
+		IP:     ip,
+	}); err != nil {
+		log.Printf("Failed to record login failure for IP: %v", err)
+	}
+}
+
+func bumpLoginThrottle(ctx context.Context, db *mongo.Database, key string, policy models.LoginThrottlePolicy, lockAudit models.AuditLog) error {
+	collection := db.Collection("login_throttles")
+	now := time.Now()
+
+	// Failures older than the window start over from one.
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"failures": bson.M{"$cond": bson.A{
+			bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$lastFailureAt", time.Time{}}}, now.Add(-policy.Window)}},
+			1,
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}},
+		}},
+		"lastFailureAt": now,
+	}}}}
+
+	var throttle models.LoginThrottle
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&throttle)
+	if err != nil {
+		return err
+	}
+
+	if throttle.Failures < policy.LockAfter {
+		return nil
+	}
+
+	lockedUntil := now.Add(policy.LockFor)
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": key, "$or": bson.A{
+			bson.M{"lockedUntil": bson.M{"$exists": false}},
+			bson.M{"lockedUntil": bson.M{"$lte": now}},
+		}},
+		bson.M{"$set": bson.M{"lockedUntil": lockedUntil}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Only the attempt that actually set the lock writes the audit entry.
+	if result.ModifiedCount == 1 {
+		lockAudit.Details = map[string]any{
+			"failures":    throttle.Failures,
+			"lockedUntil": lockedUntil,
+		}
+		recordAudit(ctx, db, lockAudit)
+	}
+	return nil
+}
+
+// clearLoginFailures forgets an email's failures after a successful login.
+// The IP counter is left to expire on its own, otherwise an attacker with
+// one valid account could keep resetting it.
+func clearLoginFailures(ctx context.Context, db *mongo.Database, email string) {
+	if _, err := db.Collection("login_throttles").DeleteOne(ctx, bson.M{"_id": loginEmailKey(email)}); err != nil {
+		log.Printf("Failed to clear login failures: %v", err)
+	}
+}
+
+func respondLoginThrottled(c *gin.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	utils.RespondError(c, http.StatusTooManyRequests, fmt.Sprintf("Too many failed login attempts, try again in %d seconds", seconds))
+}
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
 	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,11 +17,12 @@ import (
 )
 
 type ManagerDashboardResponse struct {
-	User       interface{}          `json:"user"`
-	Manager    interface{}          `json:"manager"`
-	FoodCourts []FoodCourtDashboard `json:"foodCourts"`
-	Vendor     interface{}          `json:"vendor"`
-	Managers   []interface{}        `json:"managers"`
+	User                interface{}          `json:"user"`
+	Manager             interface{}          `json:"manager"`
+	SelectedFoodCourtID primitive.ObjectID   `json:"selectedFoodCourtId"`
+	FoodCourts          []FoodCourtDashboard `json:"foodCourts"` // Selected court first
+	Vendor              interface{}          `json:"vendor"`
+	Managers            []interface{}        `json:"managers"` // Co-managers of the selected court
 }
 
 type FoodCourtDashboard struct {
@@ -27,277 +30,188 @@ type FoodCourtDashboard struct {
 	ItemCount int         `json:"itemCount"`
 }
 
-func GetManagerDashboard(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
+type managerVendor struct {
+	ID       primitive.ObjectID `json:"id,omitempty"`
+	ShopName string             `json:"shopName"`
+	GST      string             `json:"gst,omitempty"`
+	UserID   primitive.ObjectID `json:"user_id"`
+}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+func newManagerVendor(vendor models.Vendor) managerVendor {
+	return managerVendor{ID: vendor.ID, ShopName: vendor.ShopName, GST: vendor.GST, UserID: vendor.UserID}
+}
 
+func GetManagerDashboard(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	ctx := context.Background()
-	collections := struct {
-		users          *mongo.Collection
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		managers       *mongo.Collection
-		itemFoodCourts *mongo.Collection
-	}{
-		users:          db.Collection("users"),
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		managers:       db.Collection("managers"),
-		itemFoodCourts: db.Collection("itemfoodcourts"),
-	}
 
 	var response ManagerDashboardResponse
 
 	response.Managers = []interface{}{}
 
-	var user struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		Role      string             `bson:"role" json:"role"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-	err = collections.users.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
+	user, err := repos.Users.FindByID(ctx, scope.UserID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
-	response.User = user
+	response.User = struct {
+		ID        primitive.ObjectID `json:"id,omitempty"`
+		Name      string             `json:"name"`
+		Email     string             `json:"email"`
+		Role      string             `json:"role"`
+		CreatedAt time.Time          `json:"createdAt"`
+	}{user.ID, user.Name, user.Email, user.Role, user.CreatedAt}
 
-	var manager struct {
-		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id" json:"vendor_id"`
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id"`
-		ContactNo   string             `bson:"contact_no" json:"contact_no"`
-		IsActive    bool               `bson:"isActive" json:"isActive"`
-		CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager profile not found")
-		return
-	}
-	response.Manager = manager
+	manager := *scope.Manager
+	response.Manager = newManagerResponse(scope)
+	response.SelectedFoodCourtID = scope.FoodCourtID
 
-	var vendor struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		ShopName string             `bson:"shopName" json:"shopName"`
-		GST      string             `bson:"gst,omitempty" json:"gst,omitempty"`
-		UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"_id": manager.VendorID}).Decode(&vendor)
+	vendor, err := repos.Vendors.FindByID(ctx, manager.VendorID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
-	response.Vendor = vendor
+	response.Vendor = newManagerVendor(vendor)
 
-	var foodCourt struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Location  string             `bson:"location" json:"location"`
-		IsOpen    bool               `bson:"isOpen" json:"isOpen"`
-		Timings   string             `bson:"timings,omitempty" json:"timings,omitempty"`
-		Weekends  bool               `bson:"weekends" json:"weekends"`
-		Weekdays  bool               `bson:"weekdays" json:"weekdays"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
+	// Selected court first, then the others in assignment order.
+	courtIDs := []primitive.ObjectID{scope.FoodCourtID}
+	for _, id := range manager.Courts() {
+		if id != scope.FoodCourtID {
+			courtIDs = append(courtIDs, id)
+		}
 	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": manager.FoodCourtID}).Decode(&foodCourt)
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
 		return
 	}
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
-	itemCount, err := collections.itemFoodCourts.CountDocuments(ctx, bson.M{
-		"foodcourt_id": manager.FoodCourtID,
-		"item_id":      bson.M{"$in": getVendorItemIDs(ctx, collections.items, manager.VendorID)},
-	})
-	if err != nil {
-		itemCount = 0
-	}
-
-	foodCourtDashboard := FoodCourtDashboard{
-		FoodCourt: foodCourt,
-		ItemCount: int(itemCount),
+	itemCounts := map[primitive.ObjectID]int{}
+	vendorItems := vendorItemSet(ctx, repos, manager.VendorID)
+	if listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, courtIDs); err == nil {
+		for _, listing := range listings {
+			if vendorItems[listing.ItemID] {
+				itemCounts[listing.FoodCourtID]++
+			}
+		}
 	}
-	response.FoodCourts = []FoodCourtDashboard{foodCourtDashboard}
 
-	managersCursor, err := collections.managers.Find(ctx, bson.M{
-		"foodcourt_id": manager.FoodCourtID,
-		"vendor_id":    manager.VendorID,
-		"user_id":      bson.M{"$ne": userObjID},
-	})
-	if err == nil {
-		defer managersCursor.Close(ctx)
-		for managersCursor.Next(ctx) {
-			var mgr struct {
-				ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-				UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-				ContactNo string             `bson:"contact_no" json:"contact_no"`
-				IsActive  bool               `bson:"isActive" json:"isActive"`
-				CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
+	response.FoodCourts = []FoodCourtDashboard{}
+	for _, courtID := range courtIDs {
+		court, ok := courtByID[courtID]
+		if !ok {
+			if courtID == scope.FoodCourtID {
+				utils.RespondError(c, http.StatusNotFound, "Food court not found")
+				return
 			}
-			if err := managersCursor.Decode(&mgr); err == nil {
-				response.Managers = append(response.Managers, mgr)
+			continue
+		}
+
+		response.FoodCourts = append(response.FoodCourts, FoodCourtDashboard{
+			FoodCourt: struct {
+				ID        primitive.ObjectID `json:"id,omitempty"`
+				Name      string             `json:"name"`
+				Location  string             `json:"location"`
+				IsOpen    bool               `json:"isOpen"`
+				Timings   string             `json:"timings,omitempty"`
+				Weekends  bool               `json:"weekends"`
+				Weekdays  bool               `json:"weekdays"`
+				CreatedAt time.Time          `json:"createdAt"`
+			}{court.ID, court.Name, court.Location, court.IsOpen, court.Timings, court.Weekends, court.Weekdays, court.CreatedAt},
+			ItemCount: itemCounts[courtID],
+		})
+	}
+
+	if coManagers, err := repos.Managers.ListByVendor(ctx, manager.VendorID); err == nil {
+		for _, mgr := range coManagers {
+			if mgr.UserID == scope.UserID || !mgr.RunsCourt(scope.FoodCourtID) {
+				continue
 			}
+			response.Managers = append(response.Managers, struct {
+				ID        primitive.ObjectID `json:"id,omitempty"`
+				UserID    primitive.ObjectID `json:"user_id"`
+				ContactNo string             `json:"contact_no"`
+				IsActive  bool               `json:"isActive"`
+				CreatedAt time.Time          `json:"createdAt"`
+			}{mgr.ID, mgr.UserID, mgr.ContactNo, mgr.IsActive, mgr.CreatedAt})
 		}
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Manager dashboard retrieved successfully", response)
 }
 
-func getVendorItemIDs(ctx context.Context, itemsCollection *mongo.Collection, vendorID primitive.ObjectID) []primitive.ObjectID {
-	var itemIDs []primitive.ObjectID
-	cursor, err := itemsCollection.Find(ctx, bson.M{"vendor_id": vendorID})
+// vendorItemSet returns the IDs of the vendor's live items.
+func vendorItemSet(ctx context.Context, repos *repository.Repositories, vendorID primitive.ObjectID) map[primitive.ObjectID]bool {
+	itemIDs := map[primitive.ObjectID]bool{}
+	items, err := repos.Items.ListByVendor(ctx, vendorID)
 	if err != nil {
 		return itemIDs
 	}
-	defer cursor.Close(ctx)
-
-	for cursor.Next(ctx) {
-		var item struct {
-			ID primitive.ObjectID `bson:"_id"`
-		}
-		if err := cursor.Decode(&item); err == nil {
-			itemIDs = append(itemIDs, item.ID)
-		}
+	for _, item := range items {
+		itemIDs[item.ID] = true
 	}
 	return itemIDs
 }
 
-func GetManagerFoodCourtWithItems(c *gin.Context, db *mongo.Database) {
-	foodCourtID := c.Param("id")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
+// listingWithItem is a listing as the manager endpoints return it, with the
+// named fields of the listing and of its item side by side.
+func listingWithItem(listing models.ItemFoodCourt, listingFields []string, item models.Item, itemFields ...string) bson.M {
+	entry := pick(document(listing), listingFields...)
+	return copyFields(entry, document(item), itemFields...)
+}
 
+func GetManagerFoodCourtWithItems(c *gin.Context, repos *repository.Repositories) {
+	foodCourtID := c.Param("id")
 	foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
 	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid food court ID")
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		foodCourts     *mongo.Collection
-		managers       *mongo.Collection
-		itemFoodCourts *mongo.Collection
-		items          *mongo.Collection
-	}{
-		foodCourts:     db.Collection("foodcourts"),
-		managers:       db.Collection("managers"),
-		itemFoodCourts: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-	}
-
-	var manager struct {
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": foodCourtObjID,
-	}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
-		return
-	}
 
-	var foodCourt struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name     string             `bson:"name" json:"name"`
-		Location string             `bson:"location" json:"location"`
-		IsOpen   bool               `bson:"isOpen" json:"isOpen"`
-		Timings  string             `bson:"timings,omitempty" json:"timings,omitempty"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": foodCourtObjID}).Decode(&foodCourt)
+	scope, err := actor.From(c).ForFoodCourt(foodCourtObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		actor.RespondError(c, err)
 		return
 	}
 
-	var managerDetails struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&managerDetails)
+	court, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager details not found")
+		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
 	}
+	foodCourt := struct {
+		ID       primitive.ObjectID `json:"id,omitempty"`
+		Name     string             `json:"name"`
+		Location string             `json:"location"`
+		IsOpen   bool               `json:"isOpen"`
+		Timings  string             `json:"timings,omitempty"`
+	}{court.ID, court.Name, court.Location, court.IsOpen, court.Timings}
 
-	vendorItems, err := collections.items.Find(ctx, bson.M{"vendor_id": managerDetails.VendorID})
+	vendorItems, err := repos.Items.ListByVendor(ctx, scope.VendorID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendor items")
 		return
 	}
-	defer vendorItems.Close(ctx)
+	itemByID := indexBy(vendorItems, func(item models.Item) primitive.ObjectID { return item.ID })
 
-	var itemIDs []primitive.ObjectID
-	for vendorItems.Next(ctx) {
-		var item struct {
-			ID primitive.ObjectID `bson:"_id"`
-		}
-		if err := vendorItems.Decode(&item); err == nil {
-			itemIDs = append(itemIDs, item.ID)
-		}
-	}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{
-			"foodcourt_id": foodCourtObjID,
-			"item_id":      bson.M{"$in": itemIDs},
-		}}},
-		{{Key: "$lookup", Value: bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item_details",
-		}}},
-		{{Key: "$unwind", Value: "$item_details"}},
-		{{Key: "$project", Value: bson.M{
-			"item_id":     1,
-			"status":      1,
-			"price":       1,
-			"isActive":    1,
-			"timeSlot":    1,
-			"name":        "$item_details.name",
-			"description": "$item_details.description",
-			"category":    "$item_details.category",
-			"isVeg":       "$item_details.isVeg",
-			"basePrice":   "$item_details.basePrice",
-		}}},
-	}
-
-	cursor, err := collections.itemFoodCourts.Aggregate(ctx, pipeline)
+	listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, []primitive.ObjectID{foodCourtObjID})
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch items")
 		return
 	}
-	defer cursor.Close(ctx)
 
 	var items []interface{}
-	if err = cursor.All(ctx, &items); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process items")
-		return
+	for _, listing := range listings {
+		item, ok := itemByID[listing.ItemID]
+		if !ok {
+			continue
+		}
+		items = append(items, listingWithItem(listing,
+			[]string{"_id", "item_id", "status", "price", "isActive", "timeSlot", "timeSlots"},
+			item, "name", "description", "category", "isVeg", "basePrice"))
 	}
 
 	response := gin.H{
@@ -308,15 +222,9 @@ func GetManagerFoodCourtWithItems(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Food court details retrieved successfully", response)
 }
 
-func GetManagerFoodCourtItem(c *gin.Context, db *mongo.Database) {
+func GetManagerFoodCourtItem(c *gin.Context, repos *repository.Repositories) {
 	foodCourtID := c.Param("id")
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
 	foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
 	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid food court ID")
@@ -329,118 +237,52 @@ func GetManagerFoodCourtItem(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		foodCourts     *mongo.Collection
-		managers       *mongo.Collection
-		itemFoodCourts *mongo.Collection
-		items          *mongo.Collection
-	}{
-		foodCourts:     db.Collection("foodcourts"),
-		managers:       db.Collection("managers"),
-		itemFoodCourts: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-	}
-
-	var manager struct {
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": foodCourtObjID,
-	}).Decode(&manager)
+
+	scope, err := actor.From(c).ForFoodCourt(foodCourtObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
+		actor.RespondError(c, err)
 		return
 	}
 
-	var foodCourt struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name     string             `bson:"name" json:"name"`
-		Location string             `bson:"location" json:"location"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": foodCourtObjID}).Decode(&foodCourt)
+	court, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
 	}
+	foodCourt := struct {
+		ID       primitive.ObjectID `json:"id,omitempty"`
+		Name     string             `json:"name"`
+		Location string             `json:"location"`
+	}{court.ID, court.Name, court.Location}
 
-	var item struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID}).Decode(&item)
-	if err != nil || item.VendorID != manager.VendorID {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this item")
-		return
-	}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{
-			"foodcourt_id": foodCourtObjID,
-			"item_id":      itemObjID,
-		}}},
-		{{Key: "$lookup", Value: bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item_details",
-		}}},
-		{{Key: "$unwind", Value: "$item_details"}},
-		{{Key: "$project", Value: bson.M{
-			"_id":         1,
-			"item_id":     1,
-			"status":      1,
-			"price":       1,
-			"isActive":    1,
-			"timeSlot":    1,
-			"createdAt":   1,
-			"updatedAt":   1,
-			"name":        "$item_details.name",
-			"description": "$item_details.description",
-			"category":    "$item_details.category",
-			"isVeg":       "$item_details.isVeg",
-			"isSpecial":   "$item_details.isSpecial",
-			"basePrice":   "$item_details.basePrice",
-		}}},
-	}
-
-	cursor, err := collections.itemFoodCourts.Aggregate(ctx, pipeline)
+	item, err := repos.Items.FindOwned(ctx, scope.VendorID, itemObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch item details")
+		utils.RespondError(c, http.StatusForbidden, "Access denied to this item")
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var foodCourtItem interface{}
-	if cursor.Next(ctx) {
-		cursor.Decode(&foodCourtItem)
-	} else {
+	listing, err := repos.ItemFoodCourts.Find(ctx, itemObjID, foodCourtObjID)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in this food court")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch item details")
+		return
 	}
 
 	response := gin.H{
 		"foodCourt": foodCourt,
-		"item":      foodCourtItem,
+		"item": listingWithItem(listing,
+			[]string{"_id", "item_id", "status", "price", "isActive", "timeSlot", "timeSlots", "createdAt", "updatedAt"},
+			item, "name", "description", "category", "isVeg", "isSpecial", "basePrice"),
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Food court item details retrieved successfully", response)
 }
 
-func UpdateFoodCourtItemStatus(c *gin.Context, db *mongo.Database) {
+func UpdateFoodCourtItemStatus(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
 
 	var request struct {
 		Status string `json:"status" validate:"required,oneof=available notavailable sellingfast finishingsoon"`
@@ -456,103 +298,65 @@ func UpdateFoodCourtItemStatus(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		itemFoodCourts *mongo.Collection
-		items          *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		itemFoodCourts: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-	}
 
-	var manager struct {
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusForbidden, "Manager not found")
+	itemFoodCourt, err := repos.ItemFoodCourts.FindByID(ctx, itemFoodCourtObjID)
+	if err != nil || (c.Query("foodCourtId") != "" && itemFoodCourt.FoodCourtID != actor.From(c).FoodCourtID) {
+		utils.RespondError(c, http.StatusForbidden, "Item not found in your food court")
 		return
 	}
 
-	var itemFoodCourt struct {
-		ID       primitive.ObjectID `bson:"_id"`
-		ItemID   primitive.ObjectID `bson:"item_id"`
-		VendorID primitive.ObjectID `bson:"vendor_id,omitempty"`
-	}
-	err = collections.itemFoodCourts.FindOne(ctx, bson.M{
-		"_id":          itemFoodCourtObjID,
-		"foodcourt_id": manager.FoodCourtID,
-	}).Decode(&itemFoodCourt)
+	// The listing names its court, so it is the selected one unless the
+	// request picked a court itself.
+	scope, err := actor.From(c).ForFoodCourt(itemFoodCourt.FoodCourtID)
 	if err != nil {
-		utils.RespondError(c, http.StatusForbidden, "Item not found in your food court")
+		actor.RespondError(c, err)
 		return
 	}
+	manager := *scope.Manager
 
-	var item struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemFoodCourt.ItemID}).Decode(&item)
-	if err != nil || item.VendorID != manager.VendorID {
+	if _, err := repos.Items.FindOwned(ctx, manager.VendorID, itemFoodCourt.ItemID); err != nil {
 		utils.RespondError(c, http.StatusForbidden, "Access denied to this item")
 		return
 	}
 
-	result, err := collections.itemFoodCourts.UpdateOne(
-		ctx,
-		bson.M{
-			"_id": itemFoodCourtObjID,
-
-			"foodcourt_id": manager.FoodCourtID,
-		},
-		bson.M{
-			"$set": bson.M{
-				"status":    request.Status,
-				"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
-			},
-		},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item status")
+	if itemFoodCourt.FoodCourtID != scope.FoodCourtID {
+		utils.RespondError(c, http.StatusNotFound, "Item not found in your food court")
 		return
 	}
-
-	if result.MatchedCount == 0 {
+	err = repos.ItemFoodCourts.Update(ctx, itemFoodCourtObjID, repository.Fields{
+		"status":    request.Status,
+		"updatedAt": time.Now(),
+	})
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in your food court")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item status")
+		return
 	}
-	var updatedItemFoodCourt models.ItemFoodCourt
-	err = collections.itemFoodCourts.FindOne(ctx, bson.M{"_id": itemFoodCourtObjID}).Decode(&updatedItemFoodCourt)
+
+	updatedItemFoodCourt, err := repos.ItemFoodCourts.FindByID(ctx, itemFoodCourtObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated item")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(updatedItemFoodCourt, "update")
+	broadcastItemFoodCourt(repos, updatedItemFoodCourt, "update")
 	utils.RespondSuccess(c, http.StatusOK, "Item status updated successfully", nil)
 }
 
-func UpdateFoodCourtItemByManager(c *gin.Context, db *mongo.Database) {
+func UpdateFoodCourtItemByManager(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
 
 	var request struct {
-		Status   string   `json:"status" validate:"omitempty,oneof=available notavailable sellingfast finishingsoon"`
-		Price    *float64 `json:"price,omitempty"`
-		IsActive *bool    `json:"isActive,omitempty"`
-		TimeSlot string   `json:"timeSlot" validate:"omitempty,oneof=breakfast lunch snacks dinner"`
+		Status      string             `json:"status" validate:"omitempty,oneof=available notavailable sellingfast finishingsoon"`
+		Price       *float64           `json:"price,omitempty"`
+		IsActive    *bool              `json:"isActive,omitempty"`
+		TimeSlot    string             `json:"timeSlot" validate:"omitempty,oneof=breakfast lunch snacks dinner"`
+		TimeSlots   []string           `json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"`
+		FoodCourtID primitive.ObjectID `json:"foodCourtId,omitempty"` // Defaults to the foodCourtId query parameter, then the manager's first court
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid request data")
@@ -565,44 +369,23 @@ func UpdateFoodCourtItemByManager(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		itemFoodCourts *mongo.Collection
-		items          *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		itemFoodCourts: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-	}
 
-	var manager struct {
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusForbidden, "Manager not found")
-		return
+	if !request.FoodCourtID.IsZero() {
+		if scope, err = scope.ForFoodCourt(request.FoodCourtID); err != nil {
+			actor.RespondError(c, err)
+			return
+		}
 	}
+	manager := *scope.Manager
 
-	var item struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID}).Decode(&item)
-	if err != nil || item.VendorID != manager.VendorID {
+	if _, err := repos.Items.FindOwned(ctx, manager.VendorID, itemObjID); err != nil {
 		utils.RespondError(c, http.StatusForbidden, "Access denied to this item")
 		return
 	}
 
-	updateFields := bson.M{
-		"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+	updateFields := repository.Fields{
+		"updatedAt": time.Now(),
 	}
 	if request.Status != "" {
 		updateFields["status"] = request.Status
@@ -613,122 +396,55 @@ func UpdateFoodCourtItemByManager(c *gin.Context, db *mongo.Database) {
 	if request.IsActive != nil {
 		updateFields["isActive"] = request.IsActive
 	}
-	if request.TimeSlot != "" {
-		updateFields["timeSlot"] = request.TimeSlot
-	}
 
-	result, err := collections.itemFoodCourts.UpdateOne(
-		ctx,
-		bson.M{
-			"item_id":      itemObjID,
-			"foodcourt_id": manager.FoodCourtID,
-		},
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item")
-		return
+	listing, err := repos.ItemFoodCourts.Find(ctx, itemObjID, scope.FoodCourtID)
+	if err == nil && (request.TimeSlot != "" || request.TimeSlots != nil) {
+		timeSlot, timeSlots, err := listing.UpdatedTimeSlots(request.TimeSlot, request.TimeSlots)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		updateFields["timeSlot"] = timeSlot
+		updateFields["timeSlots"] = timeSlots
 	}
-
-	if result.MatchedCount == 0 {
+	if err == nil {
+		err = repos.ItemFoodCourts.Update(ctx, listing.ID, updateFields)
+	}
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in your food court")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item")
+		return
 	}
-	var updatedItemFoodCourt models.ItemFoodCourt
-	err = collections.itemFoodCourts.FindOne(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": manager.FoodCourtID,
-	}).Decode(&updatedItemFoodCourt)
+
+	updatedItemFoodCourt, err := repos.ItemFoodCourts.FindByID(ctx, listing.ID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated item")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(updatedItemFoodCourt, "update")
+	broadcastItemFoodCourt(repos, updatedItemFoodCourt, "update")
 
 	utils.RespondSuccess(c, http.StatusOK, "Item updated successfully", nil)
 }
 
-func GetManagerItemWithFCAssignments(c *gin.Context, db *mongo.Database) {
+func GetManagerItemWithFCAssignments(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid item ID")
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
-
-	var manager struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found")
-		return
-	}
 
-	managerFCsCursor, err := collections.managers.Find(ctx, bson.M{"user_id": userObjID})
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch manager assignments")
-		return
-	}
-	defer managerFCsCursor.Close(ctx)
-
-	var managerFoodCourtIDs []primitive.ObjectID
-	var managerFoodCourts []bson.M
+	manager := *scope.Manager
 
-	for managerFCsCursor.Next(ctx) {
-		var mgr struct {
-			FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-		}
-		if err := managerFCsCursor.Decode(&mgr); err == nil {
-			managerFoodCourtIDs = append(managerFoodCourtIDs, mgr.FoodCourtID)
-		}
-	}
+	managerFoodCourtIDs := manager.Courts()
 
-	if len(managerFoodCourtIDs) > 0 {
-		fcCursor, err := collections.foodCourts.Find(ctx, bson.M{"_id": bson.M{"$in": managerFoodCourtIDs}})
-		if err == nil {
-			defer fcCursor.Close(ctx)
-			fcCursor.All(ctx, &managerFoodCourts)
-		}
-	}
-
-	var item struct {
-		ID          primitive.ObjectID `bson:"_id"`
-		Name        string             `bson:"name"`
-		Description string             `bson:"description"`
-		BasePrice   float64            `bson:"basePrice"`
-		Category    string             `bson:"category"`
-		IsVeg       bool               `bson:"isVeg"`
-		IsSpecial   bool               `bson:"isSpecial"`
-		VendorID    primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID}).Decode(&item)
+	item, err := repos.Items.FindByID(ctx, itemObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found")
 		return
@@ -739,65 +455,40 @@ func GetManagerItemWithFCAssignments(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	fcItemsCursor, err := collections.foodCourtItems.Find(ctx, bson.M{"item_id": itemObjID})
+	currentFCItems, err := repos.ItemFoodCourts.ListByItem(ctx, itemObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch FC assignments")
 		return
 	}
-	defer fcItemsCursor.Close(ctx)
-
-	var currentFCItems []bson.M
-	fcItemsCursor.All(ctx, &currentFCItems)
-
-	currentFCAssignments := make(map[primitive.ObjectID]bson.M)
-	for _, fcItem := range currentFCItems {
-		if fcID, ok := fcItem["foodcourt_id"].(primitive.ObjectID); ok {
-			currentFCAssignments[fcID] = fcItem
-		}
-	}
+	currentFCAssignments := indexBy(currentFCItems, func(listing models.ItemFoodCourt) primitive.ObjectID { return listing.FoodCourtID })
 
 	var currentAssignments []interface{}
 	var availableForAssignment []interface{}
 	var notAccessible []interface{}
 
-	allFCsCursor, err := collections.foodCourts.Find(ctx, bson.M{})
+	allFoodCourts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{})
 	if err == nil {
-		defer allFCsCursor.Close(ctx)
-		var allFoodCourts []bson.M
-		allFCsCursor.All(ctx, &allFoodCourts)
-
 		for _, fc := range allFoodCourts {
-			fcID := fc["_id"].(primitive.ObjectID)
-			fcName := fc["name"].(string)
-			location := fc["location"].(string)
-
 			fcInfo := bson.M{
-				"foodCourtId":   fcID,
-				"foodCourtName": fcName,
-				"location":      location,
+				"foodCourtId":   fc.ID,
+				"foodCourtName": fc.Name,
+				"location":      fc.Location,
 			}
 
-			managerHasAccess := false
-			for _, mgrFCID := range managerFoodCourtIDs {
-				if mgrFCID == fcID {
-					managerHasAccess = true
-					break
-				}
-			}
-
-			if managerHasAccess {
+			if manager.RunsCourt(fc.ID) {
 
-				if fcItem, exists := currentFCAssignments[fcID]; exists {
+				if fcItem, exists := currentFCAssignments[fc.ID]; exists {
 
 					assignment := bson.M{
-						"foodCourtId":   fcID,
-						"foodCourtName": fcName,
-						"location":      location,
-						"status":        fcItem["status"],
-						"price":         fcItem["price"],
-						"timeSlot":      fcItem["timeSlot"],
-						"isActive":      fcItem["isActive"],
-						"updatedAt":     fcItem["updatedAt"],
+						"foodCourtId":   fc.ID,
+						"foodCourtName": fc.Name,
+						"location":      fc.Location,
+						"status":        fcItem.Status,
+						"price":         fcItem.Price,
+						"timeSlot":      fcItem.TimeSlot,
+						"timeSlots":     fcItem.TimeSlots,
+						"isActive":      fcItem.IsActive,
+						"updatedAt":     fcItem.UpdatedAt,
 					}
 					currentAssignments = append(currentAssignments, assignment)
 				} else {
@@ -806,12 +497,12 @@ func GetManagerItemWithFCAssignments(c *gin.Context, db *mongo.Database) {
 				}
 			} else {
 
-				if _, exists := currentFCAssignments[fcID]; exists {
+				if _, exists := currentFCAssignments[fc.ID]; exists {
 
 					notAccessible = append(notAccessible, bson.M{
-						"foodCourtId":   fcID,
-						"foodCourtName": fcName,
-						"location":      location,
+						"foodCourtId":   fc.ID,
+						"foodCourtName": fc.Name,
+						"location":      fc.Location,
 						"reason":        "Not assigned as manager to this food court",
 					})
 				}
@@ -843,19 +534,16 @@ func GetManagerItemWithFCAssignments(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Item FC assignments retrieved successfully", response)
 }
 
-func AddItemToManagerFoodCourt(c *gin.Context, db *mongo.Database) {
+func AddItemToManagerFoodCourt(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
 
 	var request struct {
-		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
+		FoodCourtID primitive.ObjectID `json:"foodCourtId,omitempty"`
 		Status      string             `json:"status" validate:"required,oneof=available notavailable sellingfast finishingsoon"`
 		Price       *float64           `json:"price,omitempty"`
-		TimeSlot    string             `json:"timeSlot" validate:"required,oneof=breakfast lunch snacks dinner"`
+		TimeSlot    string             `json:"timeSlot" validate:"required_without=TimeSlots,omitempty,oneof=breakfast lunch snacks dinner"`
+		TimeSlots   []string           `json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"`
 	}
 
 	if err := c.BindJSON(&request); err != nil {
@@ -863,105 +551,74 @@ func AddItemToManagerFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	itemObjID, err := primitive.ObjectIDFromHex(itemID)
+	timeSlot, timeSlots, err := models.NormalizeTimeSlots(request.TimeSlot, request.TimeSlots)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid item ID")
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		utils.RespondError(c, http.StatusBadRequest, "Invalid item ID")
 		return
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		items:          db.Collection("items"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
-	var manager struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found")
-		return
+	if !request.FoodCourtID.IsZero() {
+		if scope, err = scope.ForFoodCourt(request.FoodCourtID); err != nil {
+			actor.RespondError(c, err)
+			return
+		}
 	}
+	request.FoodCourtID = scope.FoodCourtID
 
-	var item struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": manager.VendorID}).Decode(&item)
-	if err != nil {
+	if _, err := repos.Items.FindOwned(ctx, scope.VendorID, itemObjID); err != nil {
 		utils.RespondError(c, http.StatusForbidden, "Item not found or access denied")
 		return
 	}
 
-	managerAccess, err := collections.managers.CountDocuments(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": request.FoodCourtID,
-	})
-	if err != nil || managerAccess == 0 {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
-		return
-	}
-
-	existing, err := collections.foodCourtItems.CountDocuments(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": request.FoodCourtID,
-	})
-	if err == nil && existing > 0 {
+	if _, err := repos.ItemFoodCourts.Find(ctx, itemObjID, request.FoodCourtID); err == nil {
 		utils.RespondError(c, http.StatusConflict, "Item already exists in this food court")
 		return
 	}
 
-	foodCourtItem := bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": request.FoodCourtID,
-		"status":       request.Status,
-		"price":        request.Price,
-		"timeSlot":     request.TimeSlot,
-		"isActive":     true,
-		"createdAt":    primitive.NewDateTimeFromTime(time.Now()),
-		"updatedAt":    primitive.NewDateTimeFromTime(time.Now()),
+	foodCourtItem := models.ItemFoodCourt{
+		ItemID:      itemObjID,
+		FoodCourtID: request.FoodCourtID,
+		Status:      request.Status,
+		Price:       request.Price,
+		TimeSlot:    timeSlot,
+		TimeSlots:   timeSlots,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
-	result, err := collections.foodCourtItems.InsertOne(ctx, foodCourtItem)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to add item to food court")
+	err = repos.ItemFoodCourts.Create(ctx, &foodCourtItem)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.RespondError(c, http.StatusConflict, "Item already exists in this food court")
 		return
 	}
-	var createdItemFoodCourt models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&createdItemFoodCourt)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch created item")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to add item to food court")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(createdItemFoodCourt, "create")
-	utils.RespondSuccess(c, http.StatusCreated, "Item added to food court successfully", bson.M{"id": result.InsertedID})
+	broadcastItemFoodCourt(repos, foodCourtItem, "create")
+	utils.RespondSuccess(c, http.StatusCreated, "Item added to food court successfully", bson.M{"id": foodCourtItem.ID})
 }
 
-func UpdateItemInManagerFoodCourt(c *gin.Context, db *mongo.Database) {
+func UpdateItemInManagerFoodCourt(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
 
 	var request struct {
-		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
+		FoodCourtID primitive.ObjectID `json:"foodCourtId,omitempty"`
 		Status      *string            `json:"status,omitempty" validate:"omitempty,oneof=available notavailable sellingfast finishingsoon"`
 		Price       *float64           `json:"price,omitempty"`
 		TimeSlot    *string            `json:"timeSlot,omitempty" validate:"omitempty,oneof=breakfast lunch snacks dinner"`
+		TimeSlots   []string           `json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"`
 		IsActive    *bool              `json:"isActive,omitempty"`
 	}
 
@@ -976,52 +633,23 @@ func UpdateItemInManagerFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		items:          db.Collection("items"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
-	var manager struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found")
-		return
+	if !request.FoodCourtID.IsZero() {
+		if scope, err = scope.ForFoodCourt(request.FoodCourtID); err != nil {
+			actor.RespondError(c, err)
+			return
+		}
 	}
+	request.FoodCourtID = scope.FoodCourtID
 
-	var item struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": manager.VendorID}).Decode(&item)
-	if err != nil {
+	if _, err := repos.Items.FindOwned(ctx, scope.VendorID, itemObjID); err != nil {
 		utils.RespondError(c, http.StatusForbidden, "Item not found or access denied")
 		return
 	}
 
-	managerAccess, err := collections.managers.CountDocuments(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": request.FoodCourtID,
-	})
-	if err != nil || managerAccess == 0 {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
-		return
-	}
-
-	updateFields := bson.M{
-		"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+	updateFields := repository.Fields{
+		"updatedAt": time.Now(),
 	}
 	if request.Status != nil {
 		updateFields["status"] = *request.Status
@@ -1029,66 +657,66 @@ func UpdateItemInManagerFoodCourt(c *gin.Context, db *mongo.Database) {
 	if request.Price != nil {
 		updateFields["price"] = *request.Price
 	}
-	if request.TimeSlot != nil {
-		updateFields["timeSlot"] = *request.TimeSlot
-	}
 	if request.IsActive != nil {
 		updateFields["isActive"] = *request.IsActive
 	}
+	updateSlots := request.TimeSlot != nil || request.TimeSlots != nil
 
-	if len(updateFields) == 1 {
+	if len(updateFields) == 1 && !updateSlots {
 
 		utils.RespondError(c, http.StatusBadRequest, "No valid fields to update")
 		return
 	}
 
-	result, err := collections.foodCourtItems.UpdateOne(
-		ctx,
-		bson.M{
-			"item_id":      itemObjID,
-			"foodcourt_id": request.FoodCourtID,
-		},
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item in food court")
-		return
+	listing, err := repos.ItemFoodCourts.Find(ctx, itemObjID, request.FoodCourtID)
+	if err == nil && updateSlots {
+		primary := ""
+		if request.TimeSlot != nil {
+			primary = *request.TimeSlot
+		}
+		timeSlot, timeSlots, err := listing.UpdatedTimeSlots(primary, request.TimeSlots)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		updateFields["timeSlot"] = timeSlot
+		updateFields["timeSlots"] = timeSlots
 	}
-
-	if result.MatchedCount == 0 {
+	if err == nil {
+		err = repos.ItemFoodCourts.Update(ctx, listing.ID, updateFields)
+	}
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in this food court")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item in food court")
+		return
 	}
 
-	var updatedItemFoodCourt models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": request.FoodCourtID,
-	}).Decode(&updatedItemFoodCourt)
+	updatedItemFoodCourt, err := repos.ItemFoodCourts.FindByID(ctx, listing.ID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated item")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(updatedItemFoodCourt, "update")
+	broadcastItemFoodCourt(repos, updatedItemFoodCourt, "update")
 	utils.RespondSuccess(c, http.StatusOK, "Item updated in food court successfully", nil)
 }
 
-func RemoveItemFromManagerFoodCourt(c *gin.Context, db *mongo.Database) {
+func RemoveItemFromManagerFoodCourt(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	itemID := c.Param("itemId")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
 
 	var request struct {
-		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
+		FoodCourtID primitive.ObjectID `json:"foodCourtId,omitempty"`
 	}
 
-	if err := c.BindJSON(&request); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
-		return
+	// The body is optional; without one the selected court is used.
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&request); err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
 	}
 
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
@@ -1097,190 +725,92 @@ func RemoveItemFromManagerFoodCourt(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		items:          db.Collection("items"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
-	var manager struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found")
-		return
+	if !request.FoodCourtID.IsZero() {
+		if scope, err = scope.ForFoodCourt(request.FoodCourtID); err != nil {
+			actor.RespondError(c, err)
+			return
+		}
 	}
+	request.FoodCourtID = scope.FoodCourtID
 
-	var item struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": manager.VendorID}).Decode(&item)
-	if err != nil {
+	if _, err := repos.Items.FindOwned(ctx, scope.VendorID, itemObjID); err != nil {
 		utils.RespondError(c, http.StatusForbidden, "Item not found or access denied")
 		return
 	}
 
-	managerAccess, err := collections.managers.CountDocuments(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": request.FoodCourtID,
-	})
-	if err != nil || managerAccess == 0 {
-		utils.RespondError(c, http.StatusForbidden, "Access denied to this food court")
-		return
-	}
-
-	var itemToDelete models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": request.FoodCourtID,
-	}).Decode(&itemToDelete)
+	itemToDelete, err := repos.ItemFoodCourts.Find(ctx, itemObjID, request.FoodCourtID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in this food court")
 		return
 	}
 
-	result, err := collections.foodCourtItems.DeleteOne(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": request.FoodCourtID,
-	})
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to remove item from food court")
-		return
-	}
-
-	if result.DeletedCount == 0 {
+	err = repos.ItemFoodCourts.Delete(ctx, itemToDelete.ID)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found in this food court")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to remove item from food court")
+		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(itemToDelete, "delete")
+	broadcastItemFoodCourt(repos, itemToDelete, "delete")
 
 	utils.RespondSuccess(c, http.StatusOK, "Item removed from food court successfully", nil)
 }
 
-func GetVendorItemsForManager(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func GetVendorItemsForManager(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	ctx := context.Background()
-	collections := struct {
-		managers       *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-		foodCourts     *mongo.Collection
-	}{
-		managers:       db.Collection("managers"),
-		items:          db.Collection("items"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-		foodCourts:     db.Collection("foodcourts"),
-	}
-
-	var manager struct {
-		VendorID primitive.ObjectID `bson:"vendor_id"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found")
-		return
-	}
 
-	managerFCsCursor, err := collections.managers.Find(ctx, bson.M{"user_id": userObjID})
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch manager assignments")
-		return
-	}
-	defer managerFCsCursor.Close(ctx)
+	manager := *scope.Manager
 
-	var managerFoodCourtIDs []primitive.ObjectID
+	managerFoodCourtIDs := manager.Courts()
 	var managerFoodCourts []bson.M
 
-	for managerFCsCursor.Next(ctx) {
-		var mgr struct {
-			FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-		}
-		if err := managerFCsCursor.Decode(&mgr); err == nil {
-			managerFoodCourtIDs = append(managerFoodCourtIDs, mgr.FoodCourtID)
-		}
-	}
-
 	if len(managerFoodCourtIDs) > 0 {
-		fcCursor, err := collections.foodCourts.Find(ctx, bson.M{"_id": bson.M{"$in": managerFoodCourtIDs}})
+		courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: managerFoodCourtIDs})
 		if err == nil {
-			defer fcCursor.Close(ctx)
-			fcCursor.All(ctx, &managerFoodCourts)
+			for _, court := range courts {
+				managerFoodCourts = append(managerFoodCourts, document(court))
+			}
 		}
 	}
 
-	itemsCursor, err := collections.items.Find(ctx, bson.M{"vendor_id": manager.VendorID})
+	items, err := repos.Items.ListByVendor(ctx, manager.VendorID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendor items")
 		return
 	}
-	defer itemsCursor.Close(ctx)
-
-	var items []bson.M
-	if err := itemsCursor.All(ctx, &items); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process items")
-		return
-	}
 
-	var itemIDs []primitive.ObjectID
+	itemIDs := make([]primitive.ObjectID, 0, len(items))
 	for _, item := range items {
-		if id, ok := item["_id"].(primitive.ObjectID); ok {
-			itemIDs = append(itemIDs, id)
-		}
+		itemIDs = append(itemIDs, item.ID)
 	}
 
-	fcItemsCursor, err := collections.foodCourtItems.Find(ctx, bson.M{
-		"item_id":      bson.M{"$in": itemIDs},
-		"foodcourt_id": bson.M{"$in": managerFoodCourtIDs},
-	})
+	fcItems, err := repos.ItemFoodCourts.ListByItems(ctx, itemIDs)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch FC items")
 		return
 	}
-	defer fcItemsCursor.Close(ctx)
-
-	var fcItems []bson.M
-	fcItemsCursor.All(ctx, &fcItems)
 
 	fcItemMap := make(map[string]bool)
 
 	for _, fcItem := range fcItems {
-		itemID := fcItem["item_id"].(primitive.ObjectID).Hex()
-		fcID := fcItem["foodcourt_id"].(primitive.ObjectID).Hex()
-		fcItemMap[itemID+"_"+fcID] = true
+		if manager.RunsCourt(fcItem.FoodCourtID) {
+			fcItemMap[fcItem.ItemID.Hex()+"_"+fcItem.FoodCourtID.Hex()] = true
+		}
 	}
 
 	var enhancedItems []interface{}
 	for _, item := range items {
-		itemID := item["_id"].(primitive.ObjectID)
+		itemDoc := document(item)
 
 		var fcStatus []interface{}
 		for _, fc := range managerFoodCourts {
 			fcID := fc["_id"].(primitive.ObjectID)
-			isInFC := fcItemMap[itemID.Hex()+"_"+fcID.Hex()]
+			isInFC := fcItemMap[item.ID.Hex()+"_"+fcID.Hex()]
 
 			fcStatus = append(fcStatus, bson.M{
 				"foodCourtId":   fcID,
@@ -1291,14 +821,14 @@ func GetVendorItemsForManager(c *gin.Context, db *mongo.Database) {
 		}
 
 		enhancedItem := bson.M{
-			"id":              item["_id"],
-			"name":            item["name"],
-			"description":     item["description"],
-			"basePrice":       item["basePrice"],
-			"category":        item["category"],
-			"isVeg":           item["isVeg"],
-			"isSpecial":       item["isSpecial"],
-			"createdAt":       item["createdAt"],
+			"id":              item.ID,
+			"name":            item.Name,
+			"description":     itemDoc["description"],
+			"basePrice":       item.BasePrice,
+			"category":        item.Category,
+			"isVeg":           item.IsVeg,
+			"isSpecial":       item.IsSpecial,
+			"createdAt":       item.CreatedAt,
 			"foodCourtStatus": fcStatus,
 			"canManage":       len(managerFoodCourts) > 0,
 		}
@@ -1318,130 +848,90 @@ func GetVendorItemsForManager(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Vendor items with FC status retrieved", response)
 }
 
-func GetManagerProfile(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
+type managerUser struct {
+	ID        primitive.ObjectID `json:"id,omitempty"`
+	Name      string             `json:"name"`
+	Email     string             `json:"email"`
+	Role      string             `json:"role"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+func newManagerUser(user models.User) managerUser {
+	return managerUser{user.ID, user.Name, user.Email, user.Role, user.CreatedAt, user.UpdatedAt}
+}
 
+func GetManagerProfile(c *gin.Context, repos *repository.Repositories) {
+	scope := actor.From(c)
 	ctx := context.Background()
-	collections := struct {
-		users      *mongo.Collection
-		managers   *mongo.Collection
-		vendors    *mongo.Collection
-		foodCourts *mongo.Collection
-	}{
-		users:      db.Collection("users"),
-		managers:   db.Collection("managers"),
-		vendors:    db.Collection("vendors"),
-		foodCourts: db.Collection("foodcourts"),
-	}
-
-	var user struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		Role      string             `bson:"role" json:"role"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
-	err = collections.users.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
+
+	user, err := repos.Users.FindByID(ctx, scope.UserID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	var manager struct {
-		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id" json:"vendor_id"`
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id"`
-		ContactNo   string             `bson:"contact_no" json:"contact_no"`
-		IsActive    bool               `bson:"isActive" json:"isActive"`
-		CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt   primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Manager profile not found")
-		return
-	}
+	manager := *scope.Manager
 
-	var vendor struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		ShopName string             `bson:"shopName" json:"shopName"`
-		GST      string             `bson:"gst,omitempty" json:"gst,omitempty"`
-		UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"_id": manager.VendorID}).Decode(&vendor)
+	vendor, err := repos.Vendors.FindByID(ctx, manager.VendorID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
 
-	var foodCourt struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name     string             `bson:"name" json:"name"`
-		Location string             `bson:"location" json:"location"`
-		IsOpen   bool               `bson:"isOpen" json:"isOpen"`
-		Timings  string             `bson:"timings,omitempty" json:"timings,omitempty"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": manager.FoodCourtID}).Decode(&foodCourt)
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: manager.Courts()})
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
 		return
 	}
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
-	otherFCsCursor, err := collections.managers.Find(ctx, bson.M{
-		"user_id":      userObjID,
-		"foodcourt_id": bson.M{"$ne": manager.FoodCourtID},
-	})
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch other food courts")
+	primary, ok := courtByID[scope.FoodCourtID]
+	if !ok {
+		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
 	}
-	defer otherFCsCursor.Close(ctx)
+	foodCourt := struct {
+		ID       primitive.ObjectID `json:"id,omitempty"`
+		Name     string             `json:"name"`
+		Location string             `json:"location"`
+		IsOpen   bool               `json:"isOpen"`
+		Timings  string             `json:"timings,omitempty"`
+	}{primary.ID, primary.Name, primary.Location, primary.IsOpen, primary.Timings}
 
-	var otherFoodCourts []interface{}
-	for otherFCsCursor.Next(ctx) {
-		var mgr struct {
-			FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
+	type courtSummary struct {
+		ID       primitive.ObjectID `json:"id,omitempty"`
+		Name     string             `json:"name"`
+		Location string             `json:"location"`
+	}
+	otherFoodCourts := []interface{}{}
+	for _, courtID := range manager.Courts() {
+		if courtID == scope.FoodCourtID {
+			continue
 		}
-		if err := otherFCsCursor.Decode(&mgr); err == nil {
-			var fc struct {
-				ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-				Name     string             `bson:"name" json:"name"`
-				Location string             `bson:"location" json:"location"`
-			}
-			if err := collections.foodCourts.FindOne(ctx, bson.M{"_id": mgr.FoodCourtID}).Decode(&fc); err == nil {
-				otherFoodCourts = append(otherFoodCourts, fc)
-			}
+		if fc, ok := courtByID[courtID]; ok {
+			otherFoodCourts = append(otherFoodCourts, courtSummary{fc.ID, fc.Name, fc.Location})
 		}
 	}
 
-	itemsCollection := db.Collection("items")
-	itemFoodCourtsCollection := db.Collection("itemfoodcourts")
-
-	totalItems, _ := itemsCollection.CountDocuments(ctx, bson.M{"vendor_id": manager.VendorID})
+	totalItems, _ := repos.Items.CountByVendor(ctx, manager.VendorID)
 
-	itemsInPrimaryFC, _ := itemFoodCourtsCollection.CountDocuments(ctx, bson.M{
-		"foodcourt_id": manager.FoodCourtID,
-		"item_id":      bson.M{"$in": getVendorItemIDs(ctx, itemsCollection, manager.VendorID)},
-	})
+	itemsInPrimaryFC := 0
+	vendorItems := vendorItemSet(ctx, repos, manager.VendorID)
+	if listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, []primitive.ObjectID{scope.FoodCourtID}); err == nil {
+		for _, listing := range listings {
+			if vendorItems[listing.ItemID] {
+				itemsInPrimaryFC++
+			}
+		}
+	}
 
-	totalManagedFCs, _ := collections.managers.CountDocuments(ctx, bson.M{"user_id": userObjID})
+	totalManagedFCs := len(manager.Courts())
 
 	response := gin.H{
-		"user":             user,
-		"manager":          manager,
-		"vendor":           vendor,
+		"user":             newManagerUser(user),
+		"manager":          newManagerResponse(scope),
+		"vendor":           newManagerVendor(vendor),
 		"primaryFoodCourt": foodCourt,
 		"otherFoodCourts":  otherFoodCourts,
 		"stats": gin.H{
@@ -1454,18 +944,8 @@ func GetManagerProfile(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Manager profile retrieved successfully", response)
 }
 
-func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+func UpdateManagerProfile(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userObjID := actor.From(c).UserID
 
 	var request struct {
 		Name  *string `json:"name,omitempty" validate:"omitempty,min=2,max=50"`
@@ -1486,13 +966,6 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		users    *mongo.Collection
-		managers *mongo.Collection
-	}{
-		users:    db.Collection("users"),
-		managers: db.Collection("managers"),
-	}
 
 	session, err := db.Client().StartSession()
 	if err != nil {
@@ -1503,8 +976,8 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
 
-		userUpdateFields := bson.M{
-			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+		userUpdateFields := repository.Fields{
+			"updatedAt": time.Now(),
 		}
 
 		if request.Name != nil {
@@ -1513,14 +986,11 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 
 		if request.Email != nil {
 
-			existingUser, err := collections.users.CountDocuments(sessCtx, bson.M{
-				"email": *request.Email,
-				"_id":   bson.M{"$ne": userObjID},
-			})
+			inUse, err := repos.Users.EmailInUse(sessCtx, *request.Email, userObjID)
 			if err != nil {
 				return nil, err
 			}
-			if existingUser > 0 {
+			if inUse {
 				return nil, fmt.Errorf("email already exists")
 			}
 			userUpdateFields["email"] = *request.Email
@@ -1528,21 +998,17 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 
 		if len(userUpdateFields) > 1 {
 
-			result, err := collections.users.UpdateOne(
-				sessCtx,
-				bson.M{"_id": userObjID},
-				bson.M{"$set": userUpdateFields},
-			)
+			err := repos.Users.Update(sessCtx, userObjID, userUpdateFields)
+			if err == repository.ErrNotFound {
+				return nil, fmt.Errorf("user not found")
+			}
 			if err != nil {
 				return nil, err
 			}
-			if result.MatchedCount == 0 {
-				return nil, fmt.Errorf("user not found")
-			}
 		}
 
-		managerUpdateFields := bson.M{
-			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+		managerUpdateFields := repository.Fields{
+			"updatedAt": time.Now(),
 		}
 
 		if request.ContactNo != nil {
@@ -1555,17 +1021,15 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 
 		if len(managerUpdateFields) > 1 {
 
-			result, err := collections.managers.UpdateOne(
-				sessCtx,
-				bson.M{"user_id": userObjID},
-				bson.M{"$set": managerUpdateFields},
-			)
+			// Contact details and availability are the person's, so they
+			// apply to every vendor they manage for.
+			err := repos.Managers.UpdateByUser(sessCtx, userObjID, managerUpdateFields)
+			if err == repository.ErrNotFound {
+				return nil, fmt.Errorf("manager profile not found")
+			}
 			if err != nil {
 				return nil, err
 			}
-			if result.MatchedCount == 0 {
-				return nil, fmt.Errorf("manager profile not found")
-			}
 		}
 
 		return nil, nil
@@ -1573,7 +1037,7 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 
 	_, err = session.WithTransaction(ctx, callback)
 	if err != nil {
-		if err.Error() == "email already exists" {
+		if err.Error() == "email already exists" || mongo.IsDuplicateKeyError(err) {
 			utils.RespondError(c, http.StatusConflict, "Email already exists")
 			return
 		}
@@ -1589,98 +1053,70 @@ func UpdateManagerProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	var updatedUser struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		Role      string             `bson:"role" json:"role"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
-	err = collections.users.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&updatedUser)
+	updatedUser, err := repos.Users.FindByID(ctx, userObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated user data")
 		return
 	}
 
-	var updatedManager struct {
-		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-		VendorID    primitive.ObjectID `bson:"vendor_id" json:"vendor_id"`
-		FoodCourtID primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id"`
-		ContactNo   string             `bson:"contact_no" json:"contact_no"`
-		IsActive    bool               `bson:"isActive" json:"isActive"`
-		CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt   primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
+	actor.Invalidate(userObjID)
+	scope, err := actor.LoadManager(ctx, repos, userObjID)
+	if err == nil {
+		scope, err = scope.ForFoodCourt(primitive.NilObjectID)
 	}
-	err = collections.managers.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&updatedManager)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated manager data")
 		return
 	}
 
 	response := gin.H{
-		"user":    updatedUser,
-		"manager": updatedManager,
+		"user":    newManagerUser(updatedUser),
+		"manager": newManagerResponse(scope),
 		"message": "Profile updated successfully",
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Manager profile updated successfully", response)
 }
 
-func GetManagerFoodCourts(c *gin.Context, db *mongo.Database) {
+func GetManagerFoodCourts(c *gin.Context, repos *repository.Repositories) {
+	userObjID := actor.From(c).UserID
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, 401, "User not authenticated")
-		return
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	managers, err := repos.Managers.ListByUser(ctx, userObjID)
 	if err != nil {
-		utils.RespondError(c, 400, "Invalid manager ID")
+		utils.RespondError(c, 500, "Failed to retrieve assigned food courts")
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	pipeline := []bson.M{
-
-		{"$match": bson.M{"user_id": userObjID}},
-
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "fc_details",
-		}},
-
-		{"$unwind": "$fc_details"},
-
-		{"$project": bson.M{
-			"id":         "$fc_details._id",
-			"name":       "$fc_details.name",
-			"location":   "$fc_details.location",
-			"isOpen":     "$fc_details.isOpen",
-			"timings":    "$fc_details.timings",
-			"weekends":   "$fc_details.weekends",
-			"weekdays":   "$fc_details.weekdays",
-			"assignedAt": "$createdAt",
-		}},
+	courtIDs := []primitive.ObjectID{}
+	for _, manager := range managers {
+		courtIDs = append(courtIDs, manager.Courts()...)
 	}
-
-	cursor, err := db.Collection("managers").Aggregate(ctx, pipeline)
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
 	if err != nil {
 		utils.RespondError(c, 500, "Failed to retrieve assigned food courts")
 		return
 	}
-	defer cursor.Close(ctx)
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
 	var results []bson.M = []bson.M{}
-	if err := cursor.All(ctx, &results); err != nil {
-		utils.RespondError(c, 500, "Data decoding error")
-		return
+	for _, manager := range managers {
+		for _, courtID := range manager.Courts() {
+			court, ok := courtByID[courtID]
+			if !ok {
+				continue
+			}
+			result := bson.M{
+				"_id":        manager.ID,
+				"id":         court.ID,
+				"vendorId":   manager.VendorID,
+				"assignedAt": manager.CreatedAt,
+			}
+			copyFields(result, document(court), "name", "location", "isOpen", "timings", "weekends", "weekdays")
+			results = append(results, result)
+		}
 	}
 
 	if len(results) == 0 {
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUpdateFoodCourtItemStatus(t *testing.T) {
+	f := newFixture(t)
+	manager := f.addManager(t, f.addUser(t, "Ravi", "ravi@example.com"))
+
+	recorder := serve(t, request{
+		params: gin.Params{{Key: "itemId", Value: f.listing.ID.Hex()}},
+		body:   gin.H{"status": "sellingfast"},
+		actor:  f.managerActor(manager),
+	}, func(c *gin.Context) { UpdateFoodCourtItemStatus(c, f.repos) })
+	response(t, recorder, http.StatusOK, nil)
+
+	listing, err := f.repos.ItemFoodCourts.FindByID(context.Background(), f.listing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listing.Status != "sellingfast" {
+		t.Errorf("status = %q, want sellingfast", listing.Status)
+	}
+}
+
+func TestGetManagerFoodCourtWithItems(t *testing.T) {
+	f := newFixture(t)
+	manager := f.addManager(t, f.addUser(t, "Ravi", "ravi@example.com"))
+
+	var data struct {
+		FoodCourt struct {
+			ID primitive.ObjectID `json:"id"`
+		} `json:"foodCourt"`
+		Items []struct {
+			ID     primitive.ObjectID `json:"_id"`
+			Name   string             `json:"name"`
+			Status string             `json:"status"`
+		} `json:"items"`
+	}
+	recorder := serve(t, request{
+		params: gin.Params{{Key: "id", Value: f.court.ID.Hex()}},
+		actor:  f.managerActor(manager),
+	}, func(c *gin.Context) { GetManagerFoodCourtWithItems(c, f.repos) })
+	response(t, recorder, http.StatusOK, &data)
+
+	if data.FoodCourt.ID != f.court.ID {
+		t.Errorf("foodCourt.id = %s, want %s", data.FoodCourt.ID.Hex(), f.court.ID.Hex())
+	}
+	if len(data.Items) != 1 || data.Items[0].ID != f.listing.ID || data.Items[0].Name != f.item.Name {
+		t.Errorf("items = %+v, want the %s listing", data.Items, f.item.Name)
+	}
+
+	recorder = serve(t, request{
+		params: gin.Params{{Key: "id", Value: primitive.NewObjectID().Hex()}},
+		actor:  f.managerActor(manager),
+	}, func(c *gin.Context) { GetManagerFoodCourtWithItems(c, f.repos) })
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("unassigned court: status = %d, want %d", recorder.Code, http.StatusForbidden)
+	}
+}
+
+func TestUpdateFoodCourtItemByManagerTimeSlots(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      gin.H
+		timeSlot  string
+		timeSlots []string
+	}{
+		{"slot joins the existing ones", gin.H{"timeSlot": "lunch"}, "lunch", []string{"breakfast", "lunch"}},
+		{"list replaces the existing ones", gin.H{"timeSlots": []string{"dinner"}}, "dinner", []string{"dinner"}},
+		{"slot and list", gin.H{"timeSlot": "snacks", "timeSlots": []string{"lunch"}}, "snacks", []string{"lunch", "snacks"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			manager := f.addManager(t, f.addUser(t, "Ravi", "ravi@example.com"))
+
+			recorder := serve(t, request{
+				params: gin.Params{{Key: "itemId", Value: f.item.ID.Hex()}},
+				body:   tt.body,
+				actor:  f.managerActor(manager),
+			}, func(c *gin.Context) { UpdateFoodCourtItemByManager(c, f.repos) })
+			response(t, recorder, http.StatusOK, nil)
+
+			listing, err := f.repos.ItemFoodCourts.FindByID(context.Background(), f.listing.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if listing.TimeSlot != tt.timeSlot || !slices.Equal(listing.TimeSlots, tt.timeSlots) {
+				t.Errorf("slots = %q %v, want %q %v", listing.TimeSlot, listing.TimeSlots, tt.timeSlot, tt.timeSlots)
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/mailer"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+var errManagerInviteNotFound = errors.New("invite not found, expired or already answered")
+
+type managerInviteResponse struct {
+	models.ManagerInvite
+	Status     string `json:"status"`
+	VendorName string `json:"vendorName,omitempty"`
+}
+
+func listManagerInvites(ctx context.Context, db *mongo.Database, repos *repository.Repositories, filter bson.M) ([]managerInviteResponse, error) {
+	cursor, err := db.Collection("manager_invites").Find(ctx, filter, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invites []models.ManagerInvite
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+
+	vendorNames := make(map[primitive.ObjectID]string)
+	now := time.Now()
+	response := make([]managerInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		name, ok := vendorNames[invite.VendorID]
+		if !ok {
+			name = lookupShopName(ctx, repos.Vendors, invite.VendorID)
+			vendorNames[invite.VendorID] = name
+		}
+
+		response = append(response, managerInviteResponse{
+			ManagerInvite: invite,
+			Status:        invite.CurrentStatus(now),
+			VendorName:    name,
+		})
+	}
+	return response, nil
+}
+
+// pendingInviteFilter matches an invite that can still be answered.
+func pendingInviteFilter(inviteID primitive.ObjectID) bson.M {
+	return bson.M{
+		"_id":       inviteID,
+		"status":    models.ManagerInvitePending,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+}
+
+// linkManagerInvites attaches pending invites for the user's email to their
+// account, so invites sent before they signed up are waiting for them.
+func linkManagerInvites(ctx context.Context, db *mongo.Database, user models.User) (int64, error) {
+	result, err := db.Collection("manager_invites").UpdateMany(ctx,
+		bson.M{
+			"email":     strings.ToLower(user.Email),
+			"status":    models.ManagerInvitePending,
+			"expiresAt": bson.M{"$gt": time.Now()},
+		},
+		bson.M{"$set": bson.M{"user_id": user.ID, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// assignManager makes the user a manager for the vendor in the given courts.
+// A user already managing for the vendor keeps their record and gains the
+// courts.
+func assignManager(ctx context.Context, repos *repository.Repositories, userID, vendorID primitive.ObjectID, foodCourtIDs []primitive.ObjectID, contactNo string) (primitive.ObjectID, error) {
+	defer actor.Invalidate(userID)
+
+	now := time.Now()
+
+	records, err := repos.Managers.ListByUser(ctx, userID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	for _, existing := range records {
+		if existing.VendorID != vendorID {
+			continue
+		}
+		existing.FoodCourtIDs = append(existing.Courts(), foodCourtIDs...)
+		_, err = repos.Managers.UpdateForVendor(ctx, vendorID, existing.ID, repository.Fields{
+			"foodcourt_ids": existing.Courts(),
+			"contact_no":    contactNo,
+			"updatedAt":     now,
+		}, "foodcourt_id")
+		return existing.ID, err
+	}
+
+	manager := models.Manager{
+		UserID:       userID,
+		VendorID:     vendorID,
+		FoodCourtIDs: foodCourtIDs,
+		ContactNo:    contactNo,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := repos.Managers.Create(ctx, &manager); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return manager.ID, nil
+}
+
+func sendManagerInviteEmail(invite models.ManagerInvite, shopName string) {
+	sendMail(mailer.Message{
+		To:      invite.Email,
+		Subject: shopName + " invited you to manage on Infybite",
+		Body: "Hi,\n\n" +
+			shopName + " would like you to manage their stalls on Infybite.\n\n" +
+			"Sign in with this email address to accept or decline the invite:\n\n" +
+			frontendURL("/manager-invites") + "\n\n" +
+			"If you do not have an account yet, sign up with this email address first. " +
+			"The invite expires in 7 days.",
+	})
+}
+
+func CreateManagerInvite(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	vendor := actor.From(c).Vendor
+
+	var request struct {
+		Email        string               `json:"email" validate:"required,email"`
+		ContactNo    string               `json:"contactNo" validate:"required,e164"`
+		FoodCourtIDs []primitive.ObjectID `json:"foodCourtIds,omitempty"`
+		FoodCourtID  primitive.ObjectID   `json:"foodCourtId,omitempty"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	request.Email = strings.ToLower(strings.TrimSpace(request.Email))
+	if err := utils.Validate.Struct(request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "A valid email and contact number are required")
+		return
+	}
+
+	foodCourtIDs := request.FoodCourtIDs
+	if !request.FoodCourtID.IsZero() {
+		foodCourtIDs = append(foodCourtIDs, request.FoodCourtID)
+	}
+	foodCourtIDs = models.Manager{FoodCourtIDs: foodCourtIDs}.Courts()
+	if len(foodCourtIDs) == 0 {
+		utils.RespondError(c, http.StatusBadRequest, "At least one food court is required")
+		return
+	}
+
+	ctx := context.Background()
+	managerInvites := db.Collection("manager_invites")
+
+	if status, message := checkVendorFoodCourts(ctx, repos, vendor.ID, foodCourtIDs); status != 0 {
+		utils.RespondError(c, status, message)
+		return
+	}
+
+	now := time.Now()
+	invite := models.ManagerInvite{
+		VendorID:     vendor.ID,
+		InvitedBy:    vendor.UserID,
+		Email:        request.Email,
+		FoodCourtIDs: foodCourtIDs,
+		ContactNo:    request.ContactNo,
+		Status:       models.ManagerInvitePending,
+		ExpiresAt:    now.Add(models.ManagerInviteTTL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	invitees, err := repos.Users.List(ctx, repository.UserQuery{
+		Email: "^" + regexp.QuoteMeta(request.Email) + "$",
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to look up user")
+		return
+	}
+	if len(invitees) > 0 {
+		invitee := invitees[0]
+		if invitee.ID == vendor.UserID {
+			utils.RespondError(c, http.StatusBadRequest, "You cannot invite yourself")
+			return
+		}
+		invite.UserID = &invitee.ID
+	}
+
+	// A new invite replaces any still pending for the same address, so the
+	// latest courts and contact number are the ones accepted.
+	_, err = managerInvites.UpdateMany(ctx,
+		bson.M{"vendor_id": vendor.ID, "email": invite.Email, "status": models.ManagerInvitePending},
+		bson.M{"$set": bson.M{"status": models.ManagerInviteRevoked, "updatedAt": now}},
+	)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to create invite")
+		return
+	}
+
+	result, err := managerInvites.InsertOne(ctx, invite)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to create invite")
+		return
+	}
+	invite.ID = result.InsertedID.(primitive.ObjectID)
+
+	sendManagerInviteEmail(invite, vendor.ShopName)
+
+	utils.RespondSuccess(c, http.StatusCreated, "Invite sent successfully", managerInviteResponse{
+		ManagerInvite: invite,
+		Status:        invite.Status,
+		VendorName:    vendor.ShopName,
+	})
+}
+
+func GetVendorManagerInvites(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	vendor := actor.From(c).Vendor
+
+	ctx := context.Background()
+
+	filter := bson.M{"vendor_id": vendor.ID}
+	switch status := c.Query("status"); status {
+	case "":
+	case models.ManagerInviteExpired:
+		filter["status"] = models.ManagerInvitePending
+		filter["expiresAt"] = bson.M{"$lte": time.Now()}
+	case models.ManagerInvitePending:
+		filter["status"] = status
+		filter["expiresAt"] = bson.M{"$gt": time.Now()}
+	default:
+		filter["status"] = status
+	}
+
+	invites, err := listManagerInvites(ctx, db, repos, filter)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch invites")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invites fetched successfully", gin.H{
+		"invites": invites,
+	})
+}
+
+func RevokeManagerInvite(c *gin.Context, db *mongo.Database) {
+	vendor := actor.From(c).Vendor
+
+	inviteObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid invite ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	filter := pendingInviteFilter(inviteObjID)
+	filter["vendor_id"] = vendor.ID
+	result, err := db.Collection("manager_invites").UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"status": models.ManagerInviteRevoked, "updatedAt": time.Now()},
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke invite")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.RespondError(c, http.StatusNotFound, "Invite not found or no longer pending")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invite revoked successfully", nil)
+}
+
+func GetUserManagerInvites(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	user, err := repos.Users.FindByID(ctx, userObjID)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	invites, err := listManagerInvites(ctx, db, repos, bson.M{
+		"email":     strings.ToLower(user.Email),
+		"status":    models.ManagerInvitePending,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch invites")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invites fetched successfully", gin.H{
+		"invites": invites,
+	})
+}
+
+func AcceptManagerInvite(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	respondToManagerInvite(c, db, repos, models.ManagerInviteAccepted)
+}
+
+func DeclineManagerInvite(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	respondToManagerInvite(c, db, repos, models.ManagerInviteDeclined)
+}
+
+// respondToManagerInvite answers an invite addressed to the caller's email.
+// Accepting needs a verified address, so signing up with someone else's
+// email is not enough to take over their invite.
+func respondToManagerInvite(c *gin.Context, db *mongo.Database, repos *repository.Repositories, answer string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	inviteObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid invite ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	user, err := repos.Users.FindByID(ctx, userObjID)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if answer == models.ManagerInviteAccepted && !user.EmailVerified {
+		utils.RespondError(c, http.StatusForbidden, "Verify your email address before accepting invites")
+		return
+	}
+
+	filter := pendingInviteFilter(inviteObjID)
+	filter["email"] = strings.ToLower(user.Email)
+
+	var invite models.ManagerInvite
+	if err := db.Collection("manager_invites").FindOne(ctx, filter).Decode(&invite); err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.RespondError(c, http.StatusNotFound, errManagerInviteNotFound.Error())
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch invite")
+		return
+	}
+
+	// The vendor may have left a court since inviting.
+	if answer == models.ManagerInviteAccepted {
+		if status, message := checkVendorFoodCourts(ctx, repos, invite.VendorID, invite.FoodCourtIDs); status != 0 {
+			utils.RespondError(c, http.StatusConflict, "Invite is no longer valid: "+message)
+			return
+		}
+	}
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to start database session")
+		return
+	}
+	defer session.EndSession(ctx)
+
+	// The invite is claimed in the same transaction as the assignment, so a
+	// second click, or a revoke racing the answer, cannot assign the manager
+	// twice, and a failed assignment leaves the invite pending.
+	var managerID primitive.ObjectID
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		now := time.Now()
+		result, err := db.Collection("manager_invites").UpdateOne(sc, filter, bson.M{"$set": bson.M{
+			"status":      answer,
+			"user_id":     user.ID,
+			"respondedAt": now,
+			"updatedAt":   now,
+		}})
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount == 0 {
+			return nil, errManagerInviteNotFound
+		}
+		if answer == models.ManagerInviteDeclined {
+			return nil, nil
+		}
+
+		managerID, err = assignManager(sc, repos, user.ID, invite.VendorID, invite.FoodCourtIDs, invite.ContactNo)
+		if err != nil {
+			return nil, err
+		}
+		_, err = updateUserRoles(sc, db, repos, user.ID, []string{permissions.RoleManager}, nil)
+		return nil, err
+	})
+	if errors.Is(err, errManagerInviteNotFound) {
+		utils.RespondError(c, http.StatusNotFound, errManagerInviteNotFound.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to answer invite %s: %v", invite.ID.Hex(), err)
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update invite")
+		return
+	}
+
+	if answer == models.ManagerInviteDeclined {
+		utils.RespondSuccess(c, http.StatusOK, "Invite declined", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invite accepted, sign in again to start managing", bson.M{"managerId": managerID})
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+func TestAssignManagerMergesCourts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	user := f.addUser(t, "Ravi", "ravi@example.com")
+
+	second := models.FoodCourt{Name: "South Block", Location: "Campus", VendorIDs: []primitive.ObjectID{f.vendor.ID}}
+	if err := f.repos.FoodCourts.Create(ctx, &second); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := assignManager(ctx, f.repos, user.ID, f.vendor.ID, []primitive.ObjectID{f.court.ID}, "+919876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := assignManager(ctx, f.repos, user.ID, f.vendor.ID, []primitive.ObjectID{second.ID}, "+919812345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Fatalf("second invite created manager %s, want %s reused", again.Hex(), first.Hex())
+	}
+
+	records, err := f.repos.Managers.ListByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	courts := records[0].Courts()
+	if len(courts) != 2 || courts[0] != f.court.ID || courts[1] != second.ID {
+		t.Errorf("courts = %v, want both courts in assignment order", courts)
+	}
+	if records[0].ContactNo != "+919812345678" {
+		t.Errorf("contact_no = %q, want the latest", records[0].ContactNo)
+	}
+}
+
+func TestCheckVendorFoodCourts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	elsewhere := models.FoodCourt{Name: "South Block", Location: "Campus"}
+	if err := f.repos.FoodCourts.Create(ctx, &elsewhere); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		vendorID primitive.ObjectID
+		courts   []primitive.ObjectID
+		want     int
+	}{
+		{"own court", f.vendor.ID, []primitive.ObjectID{f.court.ID}, 0},
+		{"unknown vendor", primitive.NewObjectID(), []primitive.ObjectID{f.court.ID}, http.StatusNotFound},
+		{"unknown court", f.vendor.ID, []primitive.ObjectID{primitive.NewObjectID()}, http.StatusNotFound},
+		{"court without the vendor", f.vendor.ID, []primitive.ObjectID{f.court.ID, elsewhere.ID}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, message := checkVendorFoodCourts(ctx, f.repos, tt.vendorID, tt.courts); status != tt.want {
+				t.Errorf("status = %d (%s), want %d", status, message, tt.want)
+			}
+		})
+	}
+}
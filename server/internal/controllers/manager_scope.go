@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// managerResponse is a manager record as the manager endpoints return it.
+// foodcourt_id is the court the request acted on.
+type managerResponse struct {
+	models.Manager
+	FoodCourtIDs []primitive.ObjectID `json:"foodcourt_ids"`
+	FoodCourtID  primitive.ObjectID   `json:"foodcourt_id"`
+}
+
+func newManagerResponse(scope *actor.Actor) managerResponse {
+	return managerResponse{
+		Manager:      *scope.Manager,
+		FoodCourtIDs: scope.Manager.Courts(),
+		FoodCourtID:  scope.FoodCourtID,
+	}
+}
+
+// managerOrderScope matches the orders a manager may see: those placed with
+// their vendor in any court they run, or only in the selected court when
+// the request picked one.
+func managerOrderScope(c *gin.Context) bson.M {
+	scope := actor.From(c)
+	if c.Query("foodCourtId") != "" {
+		return bson.M{"vendor_id": scope.VendorID, "foodcourt_id": scope.FoodCourtID}
+	}
+
+	var courts []bson.M
+	for _, manager := range scope.Managers {
+		courts = append(courts, bson.M{
+			"vendor_id":    manager.VendorID,
+			"foodcourt_id": bson.M{"$in": manager.Courts()},
+		})
+	}
+	return bson.M{"$or": courts}
+}
@@ -0,0 +1,633 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+func PlaceOrder(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	role := c.GetString("role")
+
+	var request struct {
+		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
+		Items       []struct {
+			ItemFoodCourtID primitive.ObjectID `json:"itemFoodCourtId" validate:"required"`
+			Quantity        int                `json:"quantity" validate:"required,gt=0,lte=50"`
+		} `json:"items" validate:"required,min=1,max=50,dive"`
+		Note string `json:"note,omitempty" validate:"omitempty,max=300"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := utils.Validate.Struct(request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Each order needs a food court and 1-50 lines with a quantity between 1 and 50")
+		return
+	}
+
+	ctx := context.Background()
+
+	foodCourt, err := repos.FoodCourts.FindByID(ctx, request.FoodCourtID)
+	if err != nil {
+		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		return
+	}
+	if !foodCourt.IsOpen {
+		utils.RespondError(c, http.StatusConflict, "Food court is currently closed")
+		return
+	}
+
+	quantities := make(map[primitive.ObjectID]int)
+	lineIDs := []primitive.ObjectID{}
+	for _, line := range request.Items {
+		if _, seen := quantities[line.ItemFoodCourtID]; !seen {
+			lineIDs = append(lineIDs, line.ItemFoodCourtID)
+		}
+		quantities[line.ItemFoodCourtID] += line.Quantity
+	}
+
+	listings, err := repos.ItemFoodCourts.ListByIDs(ctx, lineIDs)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch ordered items")
+		return
+	}
+	itemIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		itemIDs = append(itemIDs, listing.ItemID)
+	}
+	items, err := repos.Items.ListByIDs(ctx, itemIDs)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process ordered items")
+		return
+	}
+	itemByID := indexBy(items, func(item models.Item) primitive.ObjectID { return item.ID })
+
+	type menuLine struct {
+		models.ItemFoodCourt
+		Name      string
+		BasePrice float64
+		VendorID  primitive.ObjectID
+	}
+	var menuLines []menuLine
+	for _, listing := range listings {
+		item, ok := itemByID[listing.ItemID]
+		if listing.FoodCourtID != request.FoodCourtID || !ok {
+			continue
+		}
+		menuLines = append(menuLines, menuLine{listing, item.Name, item.BasePrice, item.VendorID})
+	}
+
+	if len(menuLines) != len(lineIDs) {
+		utils.RespondError(c, http.StatusBadRequest, "One or more items are not on this food court's menu")
+		return
+	}
+
+	vendorInFoodCourt := make(map[primitive.ObjectID]bool)
+	for _, vid := range foodCourt.VendorIDs {
+		vendorInFoodCourt[vid] = true
+	}
+
+	now := time.Now()
+	groupID := primitive.NewObjectID()
+	ordersByVendor := make(map[primitive.ObjectID]*models.Order)
+	var vendorOrder []primitive.ObjectID
+
+	for _, line := range menuLines {
+		if !line.IsActive || line.Status == "notavailable" || !vendorInFoodCourt[line.VendorID] {
+			utils.RespondError(c, http.StatusConflict, line.Name+" is not available right now")
+			return
+		}
+
+		unitPrice := line.BasePrice
+		if line.Price != nil {
+			unitPrice = *line.Price
+		}
+		quantity := quantities[line.ID]
+
+		order, ok := ordersByVendor[line.VendorID]
+		if !ok {
+			order = &models.Order{
+				ID:          primitive.NewObjectID(),
+				GroupID:     groupID,
+				UserID:      userObjID,
+				VendorID:    line.VendorID,
+				FoodCourtID: request.FoodCourtID,
+				Status:      models.OrderStatusPlaced,
+				Note:        request.Note,
+				History: []models.OrderStatusChange{{
+					To:        models.OrderStatusPlaced,
+					ChangedBy: userObjID,
+					Role:      role,
+					At:        now,
+				}},
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			ordersByVendor[line.VendorID] = order
+			vendorOrder = append(vendorOrder, line.VendorID)
+		}
+
+		order.Items = append(order.Items, models.OrderItem{
+			ItemFoodCourtID: line.ID,
+			ItemID:          line.ItemID,
+			Name:            line.Name,
+			Quantity:        quantity,
+			UnitPrice:       unitPrice,
+			LineTotal:       unitPrice * float64(quantity),
+		})
+		order.TotalAmount += unitPrice * float64(quantity)
+	}
+
+	var documents []interface{}
+	var orders []models.Order
+	grandTotal := 0.0
+	for _, vendorID := range vendorOrder {
+		order := ordersByVendor[vendorID]
+		documents = append(documents, order)
+		orders = append(orders, *order)
+		grandTotal += order.TotalAmount
+	}
+
+	if _, err := db.Collection("orders").InsertMany(ctx, documents); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to place order")
+		return
+	}
+
+	for _, order := range orders {
+		utils.BroadcastOrderUpdate(order, "create")
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Order placed successfully", gin.H{
+		"groupId":     groupID,
+		"orders":      orders,
+		"totalAmount": grandTotal,
+	})
+}
+
+func GetUserOrders(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	filter := bson.M{"user_id": userObjID}
+	respondOrderList(c, db, filter)
+}
+
+func respondOrderList(c *gin.Context, db *mongo.Database, filter bson.M) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	ctx := context.Background()
+	collection := db.Collection("orders")
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"createdAt": -1})
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch orders")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var orders []models.Order = []models.Order{}
+	if err := cursor.All(ctx, &orders); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process orders")
+		return
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to count orders")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Orders retrieved successfully", gin.H{
+		"orders": orders,
+		"meta": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+func GetUserOrder(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	orderObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	var order models.Order
+	err = db.Collection("orders").FindOne(context.Background(), bson.M{
+		"_id":     orderObjID,
+		"user_id": userObjID,
+	}).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.RespondError(c, http.StatusNotFound, "Order not found")
+		} else {
+			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch order")
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+func CancelUserOrder(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	orderObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason,omitempty" validate:"omitempty,max=300"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil && err.Error() != "EOF" {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	change := models.OrderStatusChange{
+		ChangedBy: userObjID,
+		Role:      c.GetString("role"),
+		Reason:    request.Reason,
+	}
+	order, err := transitionOrder(context.Background(), db, repos, bson.M{
+		"_id":     orderObjID,
+		"user_id": userObjID,
+	}, models.OrderStatusCancelled, change, true)
+	respondOrderTransition(c, order, err)
+}
+
+func GetVendorOrders(c *gin.Context, db *mongo.Database) {
+	vendor := actor.From(c).Vendor
+
+	filter := bson.M{"vendor_id": vendor.ID}
+	if foodCourtID := c.Query("foodCourtId"); foodCourtID != "" {
+		foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "Invalid food court ID")
+			return
+		}
+		filter["foodcourt_id"] = foodCourtObjID
+	}
+
+	respondOrderList(c, db, filter)
+}
+
+func GetVendorOrder(c *gin.Context, db *mongo.Database) {
+	vendor := actor.From(c).Vendor
+
+	orderObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	var order models.Order
+	err = db.Collection("orders").FindOne(ctx, bson.M{
+		"_id":       orderObjID,
+		"vendor_id": vendor.ID,
+	}).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.RespondError(c, http.StatusNotFound, "Order not found or access denied")
+		} else {
+			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch order")
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+func UpdateVendorOrderStatus(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	vendor := actor.From(c).Vendor
+
+	orderObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	var request struct {
+		Status string `json:"status" validate:"required,oneof=accepted preparing ready picked_up rejected cancelled"`
+		Reason string `json:"reason,omitempty" validate:"omitempty,max=300"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request data")
+		return
+	}
+	if err := utils.Validate.Struct(request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order status")
+		return
+	}
+
+	ctx := context.Background()
+
+	change := models.OrderStatusChange{
+		ChangedBy: vendor.UserID,
+		Role:      c.GetString("role"),
+		Reason:    request.Reason,
+	}
+	order, err := transitionOrder(ctx, db, repos, bson.M{
+		"_id":       orderObjID,
+		"vendor_id": vendor.ID,
+	}, request.Status, change, false)
+	respondOrderTransition(c, order, err)
+}
+
+func GetManagerOrders(c *gin.Context, db *mongo.Database) {
+	respondOrderList(c, db, managerOrderScope(c))
+}
+
+func UpdateManagerOrderStatus(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	orderObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	var request struct {
+		Status string `json:"status" validate:"required,oneof=accepted preparing ready picked_up rejected cancelled"`
+		Reason string `json:"reason,omitempty" validate:"omitempty,max=300"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request data")
+		return
+	}
+	if err := utils.Validate.Struct(request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid order status")
+		return
+	}
+
+	ctx := context.Background()
+
+	scope := managerOrderScope(c)
+	scope["_id"] = orderObjID
+
+	change := models.OrderStatusChange{
+		ChangedBy: actor.From(c).UserID,
+		Role:      c.GetString("role"),
+		Reason:    request.Reason,
+	}
+	order, err := transitionOrder(ctx, db, repos, scope, request.Status, change, false)
+	respondOrderTransition(c, order, err)
+}
+
+var (
+	errOrderNotFound          = errors.New("order not found")
+	errIllegalOrderTransition = errors.New("illegal order status transition")
+	errOrderChanged           = errors.New("order was updated by someone else")
+)
+
+// transitionOrder moves the order matched by scope to the given status. The
+// write is conditioned on the status that was read, so two staff members
+// racing on the same order cannot both succeed.
+func transitionOrder(ctx context.Context, db *mongo.Database, repos *repository.Repositories, scope bson.M, to string, change models.OrderStatusChange, byCustomer bool) (*models.Order, error) {
+	collection := db.Collection("orders")
+
+	var order models.Order
+	if err := collection.FindOne(ctx, scope).Decode(&order); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errOrderNotFound
+		}
+		return nil, err
+	}
+
+	if !models.CanTransitionOrder(order.Status, to, byCustomer) {
+		return &order, errIllegalOrderTransition
+	}
+
+	now := time.Now()
+	change.From = order.Status
+	change.To = to
+	change.At = now
+
+	set := bson.M{"status": to, "updatedAt": now}
+	if to == models.OrderStatusAccepted && order.Token == 0 {
+		// A token burned by a lost race below just leaves a gap in the
+		// sequence, which the counter staff can live with.
+		token, tokenDate, err := issueOrderToken(ctx, db, repos, order.FoodCourtID, now)
+		if err != nil {
+			return nil, err
+		}
+		set["token"] = token
+		set["tokenDate"] = tokenDate
+	}
+
+	err := collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": order.ID, "status": order.Status},
+		bson.M{
+			"$set":  set,
+			"$push": bson.M{"history": change},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errOrderChanged
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// issueOrderToken hands out the next pickup token for a food court. Counters
+// are keyed by the court's local date, so numbering restarts at its midnight.
+func issueOrderToken(ctx context.Context, db *mongo.Database, repos *repository.Repositories, foodCourtID primitive.ObjectID, at time.Time) (int, string, error) {
+	foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtID)
+	if err != nil && err != repository.ErrNotFound {
+		return 0, "", err
+	}
+
+	date := at.In(models.FoodCourtLocation(foodCourt.Timezone)).Format("2006-01-02")
+
+	var counter models.OrderTokenCounter
+	err = db.Collection("order_token_counters").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": foodCourtID.Hex() + ":" + date},
+		bson.M{
+			"$inc":         bson.M{"seq": 1},
+			"$setOnInsert": bson.M{"foodcourt_id": foodCourtID, "date": date},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return counter.Seq, date, nil
+}
+
+func GetFoodCourtQueue(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	foodCourtObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid food court ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		} else {
+			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food court")
+		}
+		return
+	}
+
+	today := time.Now().In(models.FoodCourtLocation(foodCourt.Timezone)).Format("2006-01-02")
+
+	cursor, err := db.Collection("orders").Find(ctx, bson.M{
+		"foodcourt_id": foodCourtObjID,
+		"tokenDate":    today,
+		"status":       bson.M{"$in": []string{models.OrderStatusPreparing, models.OrderStatusReady}},
+	}, options.Find().SetSort(bson.M{"token": 1}))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch queue")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var orders []models.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process queue")
+		return
+	}
+
+	vendorIDs := []primitive.ObjectID{}
+	for _, order := range orders {
+		vendorIDs = append(vendorIDs, order.VendorID)
+	}
+	vendors, err := repos.Vendors.ListByIDs(ctx, vendorIDs)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process queue")
+		return
+	}
+	vendorByID := indexBy(vendors, func(vendor models.Vendor) primitive.ObjectID { return vendor.ID })
+
+	type queueEntry struct {
+		Token      int                `json:"token"`
+		Status     string             `json:"status"`
+		VendorID   primitive.ObjectID `json:"vendorId"`
+		VendorName string             `json:"vendorName"`
+		UpdatedAt  time.Time          `json:"updatedAt"`
+	}
+
+	preparing := []queueEntry{}
+	ready := []queueEntry{}
+	for _, order := range orders {
+		entry := queueEntry{order.Token, order.Status, order.VendorID, vendorByID[order.VendorID].ShopName, order.UpdatedAt}
+		if entry.Status == models.OrderStatusReady {
+			ready = append(ready, entry)
+		} else {
+			preparing = append(preparing, entry)
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Queue retrieved successfully", gin.H{
+		"foodCourtId":   foodCourt.ID,
+		"foodCourtName": foodCourt.Name,
+		"date":          today,
+		"preparing":     preparing,
+		"ready":         ready,
+	})
+}
+
+func respondOrderTransition(c *gin.Context, order *models.Order, err error) {
+	switch err {
+	case nil:
+		utils.BroadcastOrderUpdate(*order, order.Status)
+		utils.RespondSuccess(c, http.StatusOK, "Order status updated successfully", order)
+	case errOrderNotFound:
+		utils.RespondError(c, http.StatusNotFound, "Order not found or access denied")
+	case errIllegalOrderTransition:
+		utils.RespondError(c, http.StatusConflict, "Order cannot move from "+order.Status+" to the requested status")
+	case errOrderChanged:
+		utils.RespondError(c, http.StatusConflict, "Order was updated by someone else, please refresh")
+	default:
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update order status")
+	}
+}
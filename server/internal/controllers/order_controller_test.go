@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// The cases below are all refused before the order is written, so they run
+// without a database.
+func TestPlaceOrderRejectsInvalidOrders(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	customer := f.addUser(t, "Kiran", "kiran@example.com")
+
+	other := models.FoodCourt{Name: "South Block", Location: "Campus", IsOpen: true, VendorIDs: []primitive.ObjectID{f.vendor.ID}}
+	if err := f.repos.FoodCourts.Create(ctx, &other); err != nil {
+		t.Fatal(err)
+	}
+	closed := models.FoodCourt{Name: "East Block", Location: "Campus", VendorIDs: []primitive.ObjectID{f.vendor.ID}}
+	if err := f.repos.FoodCourts.Create(ctx, &closed); err != nil {
+		t.Fatal(err)
+	}
+	soldOut := models.ItemFoodCourt{ItemID: f.item.ID, FoodCourtID: other.ID, Status: "notavailable", TimeSlot: "breakfast", IsActive: true}
+	if err := f.repos.ItemFoodCourts.Create(ctx, &soldOut); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		foodCourtID primitive.ObjectID
+		listingID   primitive.ObjectID
+		want        int
+	}{
+		{"unknown court", primitive.NewObjectID(), f.listing.ID, http.StatusNotFound},
+		{"closed court", closed.ID, f.listing.ID, http.StatusConflict},
+		{"listing from another court", other.ID, f.listing.ID, http.StatusBadRequest},
+		{"unavailable listing", other.ID, soldOut.ID, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(t, request{
+				userID: customer.ID,
+				body: gin.H{
+					"foodCourtId": tt.foodCourtID,
+					"items":       []gin.H{{"itemFoodCourtId": tt.listingID, "quantity": 2}},
+				},
+			}, func(c *gin.Context) { PlaceOrder(c, nil, f.repos) })
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body)
+			}
+		})
+	}
+}
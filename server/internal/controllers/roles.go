@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+// updateUserRoles grants and revokes roles, keeps the primary role field in
+// step and, when anything changed, ends the user's sessions so the next
+// tokens carry the new roles.
+func updateUserRoles(ctx context.Context, db *mongo.Database, repos *repository.Repositories, userID primitive.ObjectID, grant, revoke []string) (models.User, error) {
+	// Callers change the profile behind the roles, so the cached one goes
+	// even when the roles stay.
+	actor.Invalidate(userID)
+
+	users := repos.Users
+	user, err := users.FindByID(ctx, userID)
+	if err != nil {
+		return user, err
+	}
+
+	current := user.EffectiveRoles()
+	next := []string{}
+	for _, role := range append(current, grant...) {
+		if !slices.Contains(revoke, role) {
+			next = append(next, role)
+		}
+	}
+	next = permissions.Normalize(next)
+	primary := permissions.Primary(next)
+
+	if primary == user.Role && slices.Equal(next, user.Roles) {
+		return user, nil
+	}
+
+	err = users.Update(ctx, userID, repository.Fields{"role": primary, "roles": next, "updatedAt": time.Now()})
+	if err != nil {
+		return user, err
+	}
+	user.Role, user.Roles = primary, next
+
+	if !slices.Equal(next, current) {
+		if err := revokeUserSessions(ctx, db, userID, models.SessionRevokedRoleChange); err != nil {
+			return user, err
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// refreshGracePeriod lets two tabs refresh with the same token at the same
+// moment: the loser is told to retry instead of tripping reuse detection.
+const refreshGracePeriod = 10 * time.Second
+
+var (
+	errSessionInvalid = errors.New("refresh session is invalid or expired")
+	errSessionReused  = errors.New("refresh token reuse detected")
+	errSessionRaced   = errors.New("refresh token was just rotated")
+)
+
+func newJTI() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// startRefreshSession opens a session family for a fresh login and returns
+// its first refresh token.
+func startRefreshSession(ctx context.Context, db *mongo.Database, c *gin.Context, user models.User) (string, error) {
+	now := time.Now()
+	session := models.RefreshSession{
+		ID:         primitive.NewObjectID(),
+		UserID:     user.ID,
+		CurrentJTI: newJTI(),
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(utils.RefreshTokenTTL),
+	}
+
+	if _, err := db.Collection("refresh_sessions").InsertOne(ctx, session); err != nil {
+		return "", err
+	}
+
+	return utils.GenerateRefreshToken(user.ID.Hex(), user.Role, session.ID.Hex(), session.CurrentJTI)
+}
+
+// rotateRefreshSession swaps the session's current jti for next. Callers
+// sign the tokens carrying next first, so a failure there leaves the old
+// token working. A jti that is neither current nor inside the grace window
+// revokes the family.
+func rotateRefreshSession(ctx context.Context, db *mongo.Database, c *gin.Context, claims *utils.JWTClaims, next string) (*models.RefreshSession, error) {
+	sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+	if err != nil || claims.ID == "" {
+		return nil, errSessionInvalid
+	}
+
+	collection := db.Collection("refresh_sessions")
+	now := time.Now()
+
+	var session models.RefreshSession
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"_id":        sessionID,
+			"currentJti": claims.ID,
+			"revokedAt":  bson.M{"$exists": false},
+			"expiresAt":  bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{
+			"currentJti":  next,
+			"previousJti": claims.ID,
+			"rotatedAt":   now,
+			"lastUsedAt":  now,
+			"userAgent":   c.Request.UserAgent(),
+			"ip":          c.ClientIP(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&session)
+	if err == nil {
+		return &session, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	if err := collection.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&session); err != nil {
+		return nil, errSessionInvalid
+	}
+	err = staleRefreshError(session, claims.ID, now)
+	if err != errSessionReused {
+		return nil, err
+	}
+
+	if err := revokeSession(ctx, db, session.ID, models.SessionRevokedReuse); err != nil {
+		return nil, err
+	}
+	return nil, errSessionReused
+}
+
+// staleRefreshError explains why jti could not rotate session: the session
+// is over, jti was rotated away within the grace period, or it is a reuse.
+func staleRefreshError(session models.RefreshSession, jti string, now time.Time) error {
+	if session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return errSessionInvalid
+	}
+	if session.PreviousJTI == jti && session.RotatedAt != nil && now.Sub(*session.RotatedAt) < refreshGracePeriod {
+		return errSessionRaced
+	}
+	return errSessionReused
+}
+
+// currentSessionID returns the session behind the request's refresh cookie,
+// or NilObjectID when there is no valid cookie.
+func currentSessionID(c *gin.Context) primitive.ObjectID {
+	cookie, err := c.Cookie("refresh_token")
+	if err != nil {
+		return primitive.NilObjectID
+	}
+	claims, err := utils.ValidateRefreshToken(cookie)
+	if err != nil {
+		return primitive.NilObjectID
+	}
+	sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+	if err != nil {
+		return primitive.NilObjectID
+	}
+	return sessionID
+}
+
+// activeSessionsFilter matches a user's sessions that can still be refreshed.
+func activeSessionsFilter(userID primitive.ObjectID) bson.M {
+	return bson.M{
+		"user_id":   userID,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+}
+
+func revokeSession(ctx context.Context, db *mongo.Database, sessionID primitive.ObjectID, reason string) error {
+	_, err := db.Collection("refresh_sessions").UpdateOne(ctx,
+		bson.M{"_id": sessionID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now(), "revokedReason": reason}},
+	)
+	return err
+}
+
+// revokeUserSessions ends every live session of a user, e.g. after a
+// password or role change, so stale refresh tokens stop working.
+func revokeUserSessions(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, reason string) error {
+	_, err := db.Collection("refresh_sessions").UpdateMany(ctx,
+		bson.M{"user_id": userID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now(), "revokedReason": reason}},
+	)
+	return err
+}
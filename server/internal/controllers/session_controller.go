@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+type sessionResponse struct {
+	ID         primitive.ObjectID `json:"id"`
+	UserAgent  string             `json:"userAgent"`
+	IP         string             `json:"ip"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	LastUsedAt time.Time          `json:"lastUsedAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	Current    bool               `json:"current"`
+}
+
+func listActiveSessions(ctx context.Context, db *mongo.Database, userID, currentID primitive.ObjectID) ([]sessionResponse, error) {
+	cursor, err := db.Collection("refresh_sessions").Find(
+		ctx,
+		activeSessionsFilter(userID),
+		options.Find().SetSort(bson.M{"lastUsedAt": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.RefreshSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, sessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		})
+	}
+	return response, nil
+}
+
+func GetUserSessions(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	sessions, err := listActiveSessions(ctx, db, userObjID, currentSessionID(c))
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Sessions fetched successfully", gin.H{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+func RevokeUserSession(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	sessionObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	filter := activeSessionsFilter(userObjID)
+	filter["_id"] = sessionObjID
+
+	result, err := db.Collection("refresh_sessions").UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"revokedAt":     time.Now(),
+		"revokedReason": models.SessionRevokedByUser,
+	}})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.RespondError(c, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if sessionObjID == currentSessionID(c) {
+		setRefreshCookie(c, "", -1)
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeOtherUserSessions signs the caller out everywhere except the device
+// making the request.
+func RevokeOtherUserSessions(c *gin.Context, db *mongo.Database) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	filter := activeSessionsFilter(userObjID)
+	if currentID := currentSessionID(c); !currentID.IsZero() {
+		filter["_id"] = bson.M{"$ne": currentID}
+	}
+
+	result, err := db.Collection("refresh_sessions").UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"revokedAt":     time.Now(),
+		"revokedReason": models.SessionRevokedByUser,
+	}})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Other sessions revoked successfully", gin.H{
+		"revoked": result.ModifiedCount,
+	})
+}
+
+func GetUserSessionsAdmin(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	if _, err := repos.Users.FindByID(ctx, userObjID); err != nil {
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	sessions, err := listActiveSessions(ctx, db, userObjID, primitive.NilObjectID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Sessions fetched successfully", gin.H{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+func RevokeUserSessionAdmin(c *gin.Context, db *mongo.Database) {
+	userObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	sessionObjID, err := primitive.ObjectIDFromHex(c.Param("sessionId"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	filter := activeSessionsFilter(userObjID)
+	filter["_id"] = sessionObjID
+
+	result, err := db.Collection("refresh_sessions").UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"revokedAt":     time.Now(),
+		"revokedReason": models.SessionRevokedByAdmin,
+	}})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.RespondError(c, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+func RevokeAllUserSessionsAdmin(c *gin.Context, db *mongo.Database) {
+	userObjID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	ctx := context.Background()
+
+	result, err := db.Collection("refresh_sessions").UpdateMany(ctx, activeSessionsFilter(userObjID), bson.M{"$set": bson.M{
+		"revokedAt":     time.Now(),
+		"revokedReason": models.SessionRevokedByAdmin,
+	}})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "All sessions revoked successfully", gin.H{
+		"revoked": result.ModifiedCount,
+	})
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// The first rotation attempt is a conditional update on currentJti; these
+// cases cover what a token that lost that update is told.
+func TestStaleRefreshError(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		when := now.Add(-ago)
+		return &when
+	}
+	live := func(rotatedAgo time.Duration) models.RefreshSession {
+		return models.RefreshSession{
+			CurrentJTI:  "jti-2",
+			PreviousJTI: "jti-1",
+			RotatedAt:   at(rotatedAgo),
+			ExpiresAt:   now.Add(time.Hour),
+		}
+	}
+	revoked := live(time.Second)
+	revoked.RevokedAt = at(0)
+	expired := live(time.Second)
+	expired.ExpiresAt = now.Add(-time.Minute)
+	neverRotated := models.RefreshSession{CurrentJTI: "jti-1", ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name    string
+		session models.RefreshSession
+		jti     string
+		want    error
+	}{
+		{"previous token inside the grace period", live(time.Second), "jti-1", errSessionRaced},
+		{"previous token just before the grace period ends", live(refreshGracePeriod - time.Millisecond), "jti-1", errSessionRaced},
+		{"previous token after the grace period", live(refreshGracePeriod), "jti-1", errSessionReused},
+		{"older token inside the grace period", live(time.Second), "jti-0", errSessionReused},
+		{"unknown token on a session never rotated", neverRotated, "jti-0", errSessionReused},
+		{"revoked session", revoked, "jti-1", errSessionInvalid},
+		{"expired session", expired, "jti-1", errSessionInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staleRefreshError(tt.session, tt.jti, now); got != tt.want {
+				t.Errorf("staleRefreshError = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
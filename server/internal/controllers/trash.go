@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/cascade"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// DefaultTrashRetention is how long trashed records are kept when
+// TRASH_RETENTION_DAYS is not set.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+var trashRetention = DefaultTrashRetention
+
+// SetTrashRetention sets how long trashed records are kept before the purge
+// job deletes them for good.
+func SetTrashRetention(retention time.Duration) {
+	trashRetention = retention
+}
+
+// GetTrash lists one kind of trashed record, most recently trashed first,
+// with the time each one will be purged.
+func GetTrash(c *gin.Context, db *mongo.Database) {
+	kind := c.DefaultQuery("type", cascade.TrashVendors)
+	if !slices.Contains(cascade.TrashKinds, kind) {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid trash type")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	ctx := context.Background()
+	collection := db.Collection(kind)
+	filter := bson.M{"deletedAt": bson.M{"$ne": nil}}
+
+	findOptions := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.M{"deletedAt": -1}).
+		SetProjection(bson.M{"password": 0})
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch trash")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	records := []bson.M{}
+	if err := cursor.All(ctx, &records); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Error decoding trash")
+		return
+	}
+	for _, record := range records {
+		if deletedAt, ok := record["deletedAt"].(primitive.DateTime); ok {
+			record["purgeAt"] = deletedAt.Time().Add(trashRetention)
+		}
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to count trash")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Trash fetched successfully", gin.H{
+		"type":    kind,
+		"records": records,
+		"meta": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+			"pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RestoreTrash brings a trashed record back with everything trashed along
+// with it. A restored vendor gets its owner's vendor role back, as turning
+// a vendor back into a user trashes the shop.
+func RestoreTrash(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	kind := c.Param("type")
+	if !slices.Contains(cascade.TrashKinds, kind) {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid trash type")
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	restore := cascadeFor(c, db)
+	if kind == cascade.TrashVendors {
+		// The owner gets the vendor role back with the shop.
+		restore.Then(func(sc mongo.SessionContext, _ *cascade.Report) error {
+			vendor, err := repos.Vendors.FindByID(sc, id)
+			if err != nil {
+				return err
+			}
+			_, err = updateUserRoles(sc, db, repos, vendor.UserID, []string{permissions.RoleVendor}, nil)
+			return err
+		})
+	}
+
+	report, err := restore.Restore(context.Background(), kind, id)
+	switch err {
+	case nil:
+	case cascade.ErrNotFound:
+		utils.RespondError(c, http.StatusNotFound, "Record not found in trash")
+		return
+	case cascade.ErrParentTrashed:
+		utils.RespondError(c, http.StatusConflict, "Restore the record it belongs to first")
+		return
+	case cascade.ErrRestoreTaken:
+		utils.RespondError(c, http.StatusConflict, "A live record already takes its place")
+		return
+	default:
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to restore record")
+		return
+	}
+	actor.InvalidateAll()
+
+	utils.RespondSuccess(c, http.StatusOK, "Record restored successfully", gin.H{"restored": report})
+}
+
+// PurgeTrash deletes for good everything trashed before the cutoff and
+// takes the manager role from users it leaves without a manager record.
+func PurgeTrash(ctx context.Context, db *mongo.Database, repos *repository.Repositories, before time.Time) (*cascade.Report, error) {
+	return cascade.New(db, cascade.SystemActor).Then(demoteFormerManagers(db, repos)).Purge(ctx, before)
+}
@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,10 +12,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 )
 
-func GetUserProfile(c *gin.Context, db *mongo.Database) {
+func GetUserProfile(c *gin.Context, repos *repository.Repositories) {
 	userID, exists := c.Get("userID")
 	if !exists {
 		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
@@ -27,27 +31,25 @@ func GetUserProfile(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	usersCollection := db.Collection("users")
 
-	var user struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		Role      string             `bson:"role" json:"role"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
-
-	err = usersCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
+	user, err := repos.Users.FindByID(ctx, userObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	utils.RespondSuccess(c, http.StatusOK, "User profile retrieved successfully", user)
+	utils.RespondSuccess(c, http.StatusOK, "User profile retrieved successfully", struct {
+		ID            primitive.ObjectID `json:"id,omitempty"`
+		Name          string             `json:"name"`
+		Email         string             `json:"email"`
+		Role          string             `json:"role"`
+		EmailVerified bool               `json:"emailVerified"`
+		CreatedAt     time.Time          `json:"createdAt"`
+		UpdatedAt     time.Time          `json:"updatedAt"`
+	}{user.ID, user.Name, user.Email, user.Role, user.EmailVerified, user.CreatedAt, user.UpdatedAt})
 }
 
-func UpdateUserProfile(c *gin.Context, db *mongo.Database) {
+func UpdateUserProfile(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	userID, exists := c.Get("userID")
 	if !exists {
 		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
@@ -71,36 +73,28 @@ func UpdateUserProfile(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	usersCollection := db.Collection("users")
 
 	if updateData.Email != nil {
-		var existingUser struct {
-			ID primitive.ObjectID `bson:"_id"`
-		}
-
-		err := usersCollection.FindOne(ctx, bson.M{
-			"email": *updateData.Email,
-			"_id":   bson.M{"$ne": userObjID},
-		}).Decode(&existingUser)
-
-		if err == nil {
+		inUse, err := repos.Users.EmailInUse(ctx, *updateData.Email, userObjID)
+		if err != nil {
 
-			utils.RespondError(c, http.StatusConflict, "Email already exists")
+			utils.RespondError(c, http.StatusInternalServerError, "Failed to check email availability")
 			return
-		} else if err != mongo.ErrNoDocuments {
+		} else if inUse {
 
-			utils.RespondError(c, http.StatusInternalServerError, "Failed to check email availability")
+			utils.RespondError(c, http.StatusConflict, "Email already exists")
 			return
 		}
 
 	}
 
-	updateFields := bson.M{}
+	updateFields := repository.Fields{}
 	if updateData.Name != nil {
 		updateFields["name"] = *updateData.Name
 	}
 	if updateData.Email != nil {
 		updateFields["email"] = *updateData.Email
+		updateFields["emailVerified"] = false
 	}
 
 	if len(updateFields) == 0 {
@@ -108,51 +102,136 @@ func UpdateUserProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	updateFields["updatedAt"] = primitive.NewDateTimeFromTime(time.Now())
+	updateFields["updatedAt"] = time.Now()
 
-	_, err = usersCollection.UpdateOne(
-		ctx,
-		bson.M{"_id": userObjID},
-		bson.M{"$set": updateFields},
-	)
+	err = repos.Users.Update(ctx, userObjID, updateFields)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.RespondError(c, http.StatusConflict, "Email already exists")
+		return
+	}
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to update user profile")
 		return
 	}
 
+	if updateData.Email != nil {
+		if user, err := repos.Users.FindByID(ctx, userObjID); err == nil {
+			if err := sendVerificationEmail(ctx, db, user); err != nil {
+				log.Printf("Failed to issue verification token for %s: %v", user.Email, err)
+			}
+		}
+	}
+
 	utils.RespondSuccess(c, http.StatusOK, "User profile updated successfully", nil)
 }
 
-func GetAllFoodCourts(c *gin.Context, db *mongo.Database) {
+func ChangePassword(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var request struct {
+		CurrentPassword string `json:"currentPassword" validate:"required"`
+		NewPassword     string `json:"newPassword" validate:"required,min=6"`
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := utils.Validate.Struct(request); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "New password must be at least 6 characters")
+		return
+	}
+
 	ctx := context.Background()
-	foodCourtsCollection := db.Collection("foodcourts")
 
-	cursor, err := foodCourtsCollection.Find(ctx, bson.M{})
+	user, err := repos.Users.FindByID(ctx, userObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
+		utils.RespondError(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if !utils.CheckPassword(user.Password, request.CurrentPassword) {
+		utils.RespondError(c, http.StatusBadRequest, "Current password is incorrect")
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(request.NewPassword)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to hash password")
+		return
+	}
+
+	err = repos.Users.Update(ctx, userObjID, repository.Fields{
+		"password":  hashedPassword,
+		"updatedAt": time.Now(),
+	})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update password")
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var foodCourts []struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name     string             `bson:"name" json:"name"`
-		Location string             `bson:"location" json:"location"`
-		Timings  string             `bson:"timings,omitempty" json:"timings,omitempty"`
-		IsOpen   bool               `bson:"isOpen" json:"isOpen"`
-		Weekends bool               `bson:"weekends" json:"weekends"`
-		Weekdays bool               `bson:"weekdays" json:"weekdays"`
+	// Every other device has to log in again; this one gets a fresh session.
+	if err := revokeUserSessions(ctx, db, userObjID, models.SessionRevokedPasswordChange); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
 	}
 
-	if err := cursor.All(ctx, &foodCourts); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process food courts data")
+	refreshToken, err := startRefreshSession(ctx, db, c, user)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to generate refresh token")
 		return
 	}
+	setRefreshCookie(c, refreshToken, int(utils.RefreshTokenTTL.Seconds()))
+
+	utils.RespondSuccess(c, http.StatusOK, "Password changed successfully", nil)
+}
+
+type foodCourtSummary struct {
+	ID       primitive.ObjectID `json:"id,omitempty"`
+	Name     string             `json:"name"`
+	Location string             `json:"location"`
+	Timings  string             `json:"timings,omitempty"`
+	IsOpen   bool               `json:"isOpen"`
+	Weekends bool               `json:"weekends"`
+	Weekdays bool               `json:"weekdays"`
+}
+
+func newFoodCourtSummary(fc models.FoodCourt) foodCourtSummary {
+	return foodCourtSummary{fc.ID, fc.Name, fc.Location, fc.Timings, fc.IsOpen, fc.Weekends, fc.Weekdays}
+}
+
+func GetAllFoodCourts(c *gin.Context, repos *repository.Repositories) {
+	ctx := context.Background()
+
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
+		return
+	}
+
+	var foodCourts []foodCourtSummary
+	for _, court := range courts {
+		foodCourts = append(foodCourts, newFoodCourtSummary(court))
+	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Food courts retrieved successfully", foodCourts)
 }
 
-func GetFoodCourtByID(c *gin.Context, db *mongo.Database) {
+func GetFoodCourtByID(c *gin.Context, repos *repository.Repositories) {
 	foodCourtID := c.Param("id")
 	foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
 	if err != nil {
@@ -161,94 +240,65 @@ func GetFoodCourtByID(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		foodCourts     *mongo.Collection
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		foodCourts:     db.Collection("foodcourts"),
-		vendors:        db.Collection("vendors"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
-	var foodCourt struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name     string             `bson:"name" json:"name"`
-		Location string             `bson:"location" json:"location"`
-		Timings  string             `bson:"timings,omitempty" json:"timings,omitempty"`
-		IsOpen   bool               `bson:"isOpen" json:"isOpen"`
-		Weekends bool               `bson:"weekends" json:"weekends"`
-		Weekdays bool               `bson:"weekdays" json:"weekdays"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": foodCourtObjID}).Decode(&foodCourt)
+	court, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
 	}
+	foodCourt := struct {
+		foodCourtSummary
+		Timezone  string            `json:"timezone,omitempty"`
+		MealSlots []models.MealSlot `json:"mealSlots,omitempty"`
+	}{newFoodCourtSummary(court), court.Timezone, court.MealSlots}
+
+	type vendorSummary struct {
+		ID       primitive.ObjectID `json:"id,omitempty"`
+		ShopName string             `json:"shopName"`
+		GST      string             `json:"gst,omitempty"`
+	}
+	var vendors []vendorSummary
+	if courtVendors, err := repos.Vendors.ListByIDs(ctx, court.VendorIDs); err == nil {
+		for _, vendor := range courtVendors {
+			vendors = append(vendors, vendorSummary{vendor.ID, vendor.ShopName, vendor.GST})
+		}
+	}
 
-	var vendors []struct {
-		ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		ShopName string             `bson:"shopName" json:"shopName"`
-		GST      string             `bson:"gst,omitempty" json:"gst,omitempty"`
-	}
-	vendorsCursor, err := collections.vendors.Find(ctx, bson.M{"_id": bson.M{"$in": getVendorIDsFromFoodCourt(ctx, collections.foodCourts, foodCourtObjID)}})
-	if err == nil {
-		defer vendorsCursor.Close(ctx)
-		vendorsCursor.All(ctx, &vendors)
-	}
-
-	var itemsWithVendors []struct {
-		ItemID      primitive.ObjectID `bson:"itemId" json:"itemId"`
-		Name        string             `bson:"name" json:"name"`
-		Description string             `bson:"description,omitempty" json:"description,omitempty"`
-		BasePrice   float64            `bson:"basePrice" json:"basePrice"`
-		Category    string             `bson:"category" json:"category"`
-		IsVeg       bool               `bson:"isVeg" json:"isVeg"`
-		IsSpecial   bool               `bson:"isSpecial" json:"isSpecial"`
-		VendorID    primitive.ObjectID `bson:"vendorId" json:"vendorId"`
-		ShopName    string             `bson:"shopName" json:"shopName"`
-		Status      string             `bson:"status" json:"status"`
-		Price       *float64           `bson:"price,omitempty" json:"price,omitempty"`
-		TimeSlot    string             `bson:"timeSlot" json:"timeSlot"`
-	}
-
-	pipeline := []bson.M{
-		{"$match": bson.M{"foodcourt_id": foodCourtObjID, "isActive": true}},
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item",
-		}},
-		{"$unwind": "$item"},
-		{"$lookup": bson.M{
-			"from":         "vendors",
-			"localField":   "item.vendor_id",
-			"foreignField": "_id",
-			"as":           "vendor",
-		}},
-		{"$unwind": "$vendor"},
-		{"$project": bson.M{
-			"itemId":      "$item._id",
-			"name":        "$item.name",
-			"description": "$item.description",
-			"basePrice":   "$item.basePrice",
-			"category":    "$item.category",
-			"isVeg":       "$item.isVeg",
-			"isSpecial":   "$item.isSpecial",
-			"vendorId":    "$vendor._id",
-			"shopName":    "$vendor.shopName",
-			"status":      "$status",
-			"price":       "$price",
-			"timeSlot":    "$timeSlot",
-		}},
-	}
-
-	cursor, err := collections.foodCourtItems.Aggregate(ctx, pipeline)
-	if err == nil {
-		defer cursor.Close(ctx)
-		cursor.All(ctx, &itemsWithVendors)
+	type itemWithVendor struct {
+		ItemID      primitive.ObjectID `json:"itemId"`
+		Name        string             `json:"name"`
+		Description string             `json:"description,omitempty"`
+		BasePrice   float64            `json:"basePrice"`
+		Category    string             `json:"category"`
+		IsVeg       bool               `json:"isVeg"`
+		IsSpecial   bool               `json:"isSpecial"`
+		VendorID    primitive.ObjectID `json:"vendorId"`
+		ShopName    string             `json:"shopName"`
+		Status      string             `json:"status"`
+		Price       *float64           `json:"price,omitempty"`
+		TimeSlot    string             `json:"timeSlot"`
+		TimeSlots   []string           `json:"timeSlots,omitempty"`
+	}
+	var itemsWithVendors []itemWithVendor
+
+	if listings, err := loadMenuListings(ctx, repos, foodCourtObjID, nil); err == nil {
+		for _, listing := range listings {
+			itemsWithVendors = append(itemsWithVendors, itemWithVendor{
+				ItemID:      listing.item.ID,
+				Name:        listing.item.Name,
+				Description: listing.item.Description,
+				BasePrice:   listing.item.BasePrice,
+				Category:    listing.item.Category,
+				IsVeg:       listing.item.IsVeg,
+				IsSpecial:   listing.item.IsSpecial,
+				VendorID:    listing.vendor.ID,
+				ShopName:    listing.vendor.ShopName,
+				Status:      listing.Status,
+				Price:       listing.Price,
+				TimeSlot:    listing.TimeSlot,
+				TimeSlots:   listing.TimeSlots,
+			})
+		}
 	}
 
 	response := struct {
@@ -264,15 +314,7 @@ func GetFoodCourtByID(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Food court details retrieved successfully", response)
 }
 
-func getVendorIDsFromFoodCourt(ctx context.Context, foodCourtsCollection *mongo.Collection, foodCourtID primitive.ObjectID) []primitive.ObjectID {
-	var foodCourt struct {
-		VendorIDs []primitive.ObjectID `bson:"vendor_ids"`
-	}
-	foodCourtsCollection.FindOne(ctx, bson.M{"_id": foodCourtID}).Decode(&foodCourt)
-	return foodCourt.VendorIDs
-}
-
-func GetFoodCourtItems(c *gin.Context, db *mongo.Database) {
+func GetFoodCourtItems(c *gin.Context, repos *repository.Repositories) {
 	foodCourtID := c.Param("id")
 	foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
 	if err != nil {
@@ -281,74 +323,136 @@ func GetFoodCourtItems(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		foodCourtItems *mongo.Collection
-	}{
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
-
-	var vendorItems []struct {
-		VendorID primitive.ObjectID `bson:"vendorId" json:"vendorId"`
-		ShopName string             `bson:"shopName" json:"shopName"`
-		Items    []interface{}      `bson:"items" json:"items"`
-	}
-
-	pipeline := []bson.M{
-		{"$match": bson.M{"foodcourt_id": foodCourtObjID, "isActive": true}},
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item",
-		}},
-		{"$unwind": "$item"},
-		{"$lookup": bson.M{
-			"from":         "vendors",
-			"localField":   "item.vendor_id",
-			"foreignField": "_id",
-			"as":           "vendor",
-		}},
-		{"$unwind": "$vendor"},
-		{"$group": bson.M{
-			"_id":      "$vendor._id",
-			"shopName": bson.M{"$first": "$vendor.shopName"},
-			"items": bson.M{"$push": bson.M{
-				"itemId":      "$item._id",
-				"name":        "$item.name",
-				"description": "$item.description",
-				"basePrice":   "$item.basePrice",
-				"category":    "$item.category",
-				"isVeg":       "$item.isVeg",
-				"isSpecial":   "$item.isSpecial",
-				"status":      "$status",
-				"price":       "$price",
-				"timeSlot":    "$timeSlot",
-			}},
-		}},
-		{"$project": bson.M{
-			"vendorId": "$_id",
-			"shopName": 1,
-			"items":    1,
-			"_id":      0,
-		}},
-	}
-
-	cursor, err := collections.foodCourtItems.Aggregate(ctx, pipeline)
+
+	// ?menu=current narrows the menu to the time slots being served now.
+	var timeSlots []string
+	if c.Query("menu") == "current" {
+		foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
+		if err != nil {
+			utils.RespondError(c, http.StatusNotFound, "Food court not found")
+			return
+		}
+		now := time.Now().In(models.FoodCourtLocation(foodCourt.Timezone))
+		timeSlots = models.ActiveTimeSlots(foodCourt.MealSlots, now)
+	}
+
+	vendorItems, err := LoadFoodCourtMenu(ctx, repos, foodCourtObjID, timeSlots)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food court items")
 		return
 	}
-	defer cursor.Close(ctx)
 
-	if err := cursor.All(ctx, &vendorItems); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process food court items")
-		return
+	utils.RespondSuccess(c, http.StatusOK, "Food court items retrieved successfully", vendorItems)
+}
+
+type FoodCourtMenuVendor struct {
+	VendorID primitive.ObjectID `bson:"vendorId" json:"vendorId"`
+	ShopName string             `bson:"shopName" json:"shopName"`
+	Items    []interface{}      `bson:"items" json:"items"`
+}
+
+// menuListing is an active listing joined with its live item and vendor.
+type menuListing struct {
+	models.ItemFoodCourt
+	item   models.Item
+	vendor models.Vendor
+}
+
+// loadMenuListings returns the court's active listings whose item and
+// vendor are live. A non-nil timeSlots keeps only listings served in one of
+// those slots.
+func loadMenuListings(ctx context.Context, repos *repository.Repositories, foodCourtObjID primitive.ObjectID, timeSlots []string) ([]menuListing, error) {
+	listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, []primitive.ObjectID{foodCourtObjID})
+	if err != nil {
+		return nil, err
 	}
 
-	utils.RespondSuccess(c, http.StatusOK, "Food court items retrieved successfully", vendorItems)
+	active := []models.ItemFoodCourt{}
+	itemIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		if !listing.IsActive {
+			continue
+		}
+		if timeSlots != nil && !slices.Contains(timeSlots, listing.TimeSlot) &&
+			!slices.ContainsFunc(listing.TimeSlots, func(slot string) bool { return slices.Contains(timeSlots, slot) }) {
+			continue
+		}
+		active = append(active, listing)
+		itemIDs = append(itemIDs, listing.ItemID)
+	}
+
+	items, err := repos.Items.ListByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemByID := indexBy(items, func(item models.Item) primitive.ObjectID { return item.ID })
+
+	vendorIDs := []primitive.ObjectID{}
+	for _, item := range items {
+		vendorIDs = append(vendorIDs, item.VendorID)
+	}
+	vendors, err := repos.Vendors.ListByIDs(ctx, vendorIDs)
+	if err != nil {
+		return nil, err
+	}
+	vendorByID := indexBy(vendors, func(vendor models.Vendor) primitive.ObjectID { return vendor.ID })
+
+	menu := []menuListing{}
+	for _, listing := range active {
+		item, ok := itemByID[listing.ItemID]
+		if !ok {
+			continue
+		}
+		vendor, ok := vendorByID[item.VendorID]
+		if !ok {
+			continue
+		}
+		menu = append(menu, menuListing{listing, item, vendor})
+	}
+	return menu, nil
 }
 
-func GetVendorItemsWithFoodCourts(c *gin.Context, db *mongo.Database) {
+// LoadFoodCourtMenu returns a food court's active items grouped by vendor.
+// It backs both the REST menu endpoint and WebSocket snapshots. A non-nil
+// timeSlots keeps only items served in one of those slots.
+func LoadFoodCourtMenu(ctx context.Context, repos *repository.Repositories, foodCourtObjID primitive.ObjectID, timeSlots []string) ([]FoodCourtMenuVendor, error) {
+	vendorItems := []FoodCourtMenuVendor{}
+
+	// Listings outlive a trashed food court until it is purged.
+	if _, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID); err == repository.ErrNotFound {
+		return vendorItems, nil
+	} else if err != nil {
+		return vendorItems, err
+	}
+
+	listings, err := loadMenuListings(ctx, repos, foodCourtObjID, timeSlots)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[primitive.ObjectID]int{}
+	for _, listing := range listings {
+		group, ok := groups[listing.vendor.ID]
+		if !ok {
+			group = len(vendorItems)
+			groups[listing.vendor.ID] = group
+			vendorItems = append(vendorItems, FoodCourtMenuVendor{
+				VendorID: listing.vendor.ID,
+				ShopName: listing.vendor.ShopName,
+				Items:    []interface{}{},
+			})
+		}
+
+		entry := bson.M{"itemId": listing.item.ID}
+		copyFields(entry, document(listing.item), "name", "description", "basePrice", "category", "isVeg", "isSpecial")
+		copyFields(entry, document(listing.ItemFoodCourt), "status", "price", "timeSlot", "timeSlots")
+		vendorItems[group].Items = append(vendorItems[group].Items, entry)
+	}
+
+	return vendorItems, nil
+}
+
+func GetVendorItemsWithFoodCourts(c *gin.Context, repos *repository.Repositories) {
 	vendorID := c.Param("id")
 	vendorObjID, err := primitive.ObjectIDFromHex(vendorID)
 	if err != nil {
@@ -357,119 +461,91 @@ func GetVendorItemsWithFoodCourts(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		foodCourtItems *mongo.Collection
-		foodCourts     *mongo.Collection
-		items          *mongo.Collection
-	}{
-		items: db.Collection("items"),
-	}
-
-	var itemsWithFoodCourts []struct {
-		ItemID      primitive.ObjectID `bson:"itemId" json:"itemId"`
-		Name        string             `bson:"name" json:"name"`
-		Description string             `bson:"description" json:"description"`
-		BasePrice   float64            `bson:"basePrice" json:"basePrice"`
-		Category    string             `bson:"category" json:"category"`
-		IsVeg       bool               `bson:"isVeg" json:"isVeg"`
-		IsSpecial   bool               `bson:"isSpecial" json:"isSpecial"`
-		FoodCourts  []struct {
-			FoodCourtID   primitive.ObjectID `bson:"foodCourtId" json:"foodCourtId"`
-			FoodCourtName string             `bson:"foodCourtName" json:"foodCourtName"`
-			Location      string             `bson:"location" json:"location"`
-			Status        string             `bson:"status" json:"status"`
-			Price         *float64           `bson:"price,omitempty" json:"price,omitempty"`
-			TimeSlot      string             `bson:"timeSlot" json:"timeSlot"`
-			IsActive      bool               `bson:"isActive" json:"isActive"`
-		} `bson:"foodCourts" json:"foodCourts"`
-	}
-
-	pipeline := []bson.M{
-		{"$match": bson.M{"vendor_id": vendorObjID}},
-		{"$lookup": bson.M{
-			"from":         "itemfoodcourts",
-			"localField":   "_id",
-			"foreignField": "item_id",
-			"as":           "foodCourtItems",
-		}},
-		{"$unwind": bson.M{"path": "$foodCourtItems", "preserveNullAndEmptyArrays": true}},
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodCourtItems.foodcourt_id",
-			"foreignField": "_id",
-			"as":           "foodCourt",
-		}},
-		{"$unwind": bson.M{"path": "$foodCourt", "preserveNullAndEmptyArrays": true}},
-		{"$match": bson.M{
-			"$or": []bson.M{
-				{"foodCourtItems.isActive": true},
-				{"foodCourtItems": bson.M{"$exists": false}},
-			},
-		}},
-		{"$group": bson.M{
-			"_id":         "$_id",
-			"name":        bson.M{"$first": "$name"},
-			"description": bson.M{"$first": "$description"},
-			"basePrice":   bson.M{"$first": "$basePrice"},
-			"category":    bson.M{"$first": "$category"},
-			"isVeg":       bson.M{"$first": "$isVeg"},
-			"isSpecial":   bson.M{"$first": "$isSpecial"},
-			"foodCourts": bson.M{"$push": bson.M{
-				"$cond": bson.M{
-					"if": bson.M{"$and": []bson.M{
-						{"$ne": bson.A{"$foodCourt", nil}},
-						{"$ne": bson.A{"$foodCourtItems", nil}},
-					}},
-					"then": bson.M{
-						"foodCourtId":   "$foodCourt._id",
-						"foodCourtName": "$foodCourt.name",
-						"location":      "$foodCourt.location",
-						"status":        "$foodCourtItems.status",
-						"price":         "$foodCourtItems.price",
-						"timeSlot":      "$foodCourtItems.timeSlot",
-						"isActive":      "$foodCourtItems.isActive",
-					},
-					"else": "$$REMOVE",
-				},
-			}},
-		}},
-		{"$project": bson.M{
-			"itemId":      "$_id",
-			"name":        1,
-			"description": 1,
-			"basePrice":   1,
-			"category":    1,
-			"isVeg":       1,
-			"isSpecial":   1,
-			"foodCourts":  1,
-			"_id":         0,
-		}},
-	}
-
-	cursor, err := collections.items.Aggregate(ctx, pipeline)
+
+	type itemFoodCourt struct {
+		FoodCourtID   primitive.ObjectID `json:"foodCourtId"`
+		FoodCourtName string             `json:"foodCourtName"`
+		Location      string             `json:"location"`
+		Status        string             `json:"status"`
+		Price         *float64           `json:"price,omitempty"`
+		TimeSlot      string             `json:"timeSlot"`
+		TimeSlots     []string           `json:"timeSlots,omitempty"`
+		IsActive      bool               `json:"isActive"`
+	}
+	type itemWithFoodCourts struct {
+		ItemID      primitive.ObjectID `json:"itemId"`
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		BasePrice   float64            `json:"basePrice"`
+		Category    string             `json:"category"`
+		IsVeg       bool               `json:"isVeg"`
+		IsSpecial   bool               `json:"isSpecial"`
+		FoodCourts  []itemFoodCourt    `json:"foodCourts"`
+	}
+
+	items, err := repos.Items.ListByVendor(ctx, vendorObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch vendor items")
 		return
 	}
-	defer cursor.Close(ctx)
+	itemIDs := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
+	}
 
-	if err := cursor.All(ctx, &itemsWithFoodCourts); err != nil {
+	listings, err := repos.ItemFoodCourts.ListByItems(ctx, itemIDs)
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to process vendor items")
 		return
 	}
+	courtIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		courtIDs = append(courtIDs, listing.FoodCourtID)
+	}
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process vendor items")
+		return
+	}
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
 	var filteredItems []interface{}
-	for _, item := range itemsWithFoodCourts {
+	for _, item := range items {
+		entry := itemWithFoodCourts{
+			ItemID:      item.ID,
+			Name:        item.Name,
+			Description: item.Description,
+			BasePrice:   item.BasePrice,
+			Category:    item.Category,
+			IsVeg:       item.IsVeg,
+			IsSpecial:   item.IsSpecial,
+		}
+		for _, listing := range listings {
+			court, ok := courtByID[listing.FoodCourtID]
+			if listing.ItemID != item.ID || !listing.IsActive || !ok {
+				continue
+			}
+			entry.FoodCourts = append(entry.FoodCourts, itemFoodCourt{
+				FoodCourtID:   court.ID,
+				FoodCourtName: court.Name,
+				Location:      court.Location,
+				Status:        listing.Status,
+				Price:         listing.Price,
+				TimeSlot:      listing.TimeSlot,
+				TimeSlots:     listing.TimeSlots,
+				IsActive:      listing.IsActive,
+			})
+		}
 
-		if len(item.FoodCourts) > 0 {
-			filteredItems = append(filteredItems, item)
+		if len(entry.FoodCourts) > 0 {
+			filteredItems = append(filteredItems, entry)
 		}
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Vendor items with food courts retrieved successfully", filteredItems)
 }
 
-func GetItemDetails(c *gin.Context, db *mongo.Database) {
+func GetItemDetails(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Param("id")
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
@@ -478,100 +554,45 @@ func GetItemDetails(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		items          *mongo.Collection
-		vendors        *mongo.Collection
-		foodCourts     *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		items:          db.Collection("items"),
-		vendors:        db.Collection("vendors"),
-		foodCourts:     db.Collection("foodcourts"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
-
-	pipeline := []bson.M{
-		{"$match": bson.M{"_id": itemObjID}},
-		{"$lookup": bson.M{
-			"from":         "vendors",
-			"localField":   "vendor_id",
-			"foreignField": "_id",
-			"as":           "vendor",
-		}},
-		{"$unwind": bson.M{"path": "$vendor", "preserveNullAndEmptyArrays": true}},
-		{"$project": bson.M{
-			"item": bson.M{
-				"id":          "$_id",
-				"name":        "$name",
-				"description": "$description",
-				"basePrice":   "$basePrice",
-				"category":    "$category",
-				"isVeg":       "$isVeg",
-				"isSpecial":   "$isSpecial",
-				"createdAt":   "$createdAt",
-				"updatedAt":   "$updatedAt",
-			},
-			"vendor": bson.M{
-				"id":       "$vendor._id",
-				"shopName": "$vendor.shopName",
-				"gst":      "$vendor.gst",
-			},
-		}},
-	}
-
-	cursor, err := collections.items.Aggregate(ctx, pipeline)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch item details")
-		return
-	}
-	defer cursor.Close(ctx)
 
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil || len(results) == 0 {
+	found, err := repos.Items.FindByID(ctx, itemObjID)
+	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found")
 		return
 	}
+	item := bson.M{"id": found.ID}
+	copyFields(item, document(found), "name", "description", "basePrice", "category", "isVeg", "isSpecial", "createdAt", "updatedAt")
 
-	itemData := results[0]
-	item := itemData["item"]
-	vendor := itemData["vendor"]
+	vendor := bson.M{}
+	if owner, err := repos.Vendors.FindByID(ctx, found.VendorID); err == nil {
+		vendor = pick(document(owner), "shopName", "gst")
+		vendor["id"] = owner.ID
+	}
 
 	var availability []bson.M
-	availabilityPipeline := []bson.M{
-		{"$match": bson.M{
-			"item_id":  itemObjID,
-			"isActive": true,
-		}},
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "foodCourt",
-		}},
-		{"$unwind": bson.M{"path": "$foodCourt", "preserveNullAndEmptyArrays": true}},
-		{"$match": bson.M{
-			"foodCourt": bson.M{"$ne": nil},
-		}},
-		{"$project": bson.M{
-			"foodCourtId":   "$foodCourt._id",
-			"foodCourtName": "$foodCourt.name",
-			"location":      "$foodCourt.location",
-			"timings":       "$foodCourt.timings",
-			"isOpen":        "$foodCourt.isOpen",
-			"weekends":      "$foodCourt.weekends",
-			"weekdays":      "$foodCourt.weekdays",
-			"status":        "$status",
-			"price":         "$price",
-			"timeSlot":      "$timeSlot",
-			"isActive":      "$isActive",
-			"updatedAt":     "$updatedAt",
-		}},
-	}
-
-	availabilityCursor, err := collections.foodCourtItems.Aggregate(ctx, availabilityPipeline)
-	if err == nil {
-		defer availabilityCursor.Close(ctx)
-		availabilityCursor.All(ctx, &availability)
+	if listings, err := repos.ItemFoodCourts.ListByItem(ctx, itemObjID); err == nil {
+		courtIDs := []primitive.ObjectID{}
+		for _, listing := range listings {
+			courtIDs = append(courtIDs, listing.FoodCourtID)
+		}
+		courts, _ := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+		courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
+
+		for _, listing := range listings {
+			court, ok := courtByID[listing.FoodCourtID]
+			if !listing.IsActive || !ok {
+				continue
+			}
+			entry := pick(document(listing), "_id", "status", "price", "timeSlot", "timeSlots", "isActive", "updatedAt")
+			entry["foodCourtId"] = court.ID
+			entry["foodCourtName"] = court.Name
+			entry["location"] = court.Location
+			entry["isOpen"] = court.IsOpen
+			entry["weekends"] = court.Weekends
+			entry["weekdays"] = court.Weekdays
+			copyFields(entry, document(court), "timings")
+			availability = append(availability, entry)
+		}
 	}
 
 	response := bson.M{
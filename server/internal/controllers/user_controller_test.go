@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+func TestLoadFoodCourtMenu(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	// A trashed item drops off the menu with its listing left in place.
+	trashed := models.Item{VendorID: f.vendor.ID, Name: "Idli", BasePrice: 30, Category: "breakfast"}
+	if err := f.repos.Items.Create(ctx, &trashed); err != nil {
+		t.Fatal(err)
+	}
+	listing := models.ItemFoodCourt{ItemID: trashed.ID, FoodCourtID: f.court.ID, Status: "available", TimeSlot: "breakfast", IsActive: true}
+	if err := f.repos.ItemFoodCourts.Create(ctx, &listing); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.repos.Items.UpdateOwned(ctx, f.vendor.ID, trashed.ID, repository.Fields{"deletedAt": time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	menu, err := LoadFoodCourtMenu(ctx, f.repos, f.court.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menu) != 1 || menu[0].VendorID != f.vendor.ID || menu[0].ShopName != f.vendor.ShopName {
+		t.Fatalf("menu = %+v, want one group for %s", menu, f.vendor.ShopName)
+	}
+	if len(menu[0].Items) != 1 {
+		t.Fatalf("items = %v, want only %s", menu[0].Items, f.item.Name)
+	}
+
+	lunch, err := LoadFoodCourtMenu(ctx, f.repos, f.court.ID, []string{"lunch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lunch) != 0 {
+		t.Errorf("lunch menu = %+v, want empty", lunch)
+	}
+
+	missing, err := LoadFoodCourtMenu(ctx, f.repos, primitive.NewObjectID(), nil)
+	if err != nil || len(missing) != 0 {
+		t.Errorf("unknown court: menu = %+v, err = %v, want empty", missing, err)
+	}
+}
+
+func TestGetUserProfile(t *testing.T) {
+	f := newFixture(t)
+
+	var profile struct {
+		ID    primitive.ObjectID `json:"id"`
+		Email string             `json:"email"`
+	}
+	recorder := serve(t, request{userID: f.user.ID}, func(c *gin.Context) { GetUserProfile(c, f.repos) })
+	response(t, recorder, http.StatusOK, &profile)
+	if profile.ID != f.user.ID || profile.Email != f.user.Email {
+		t.Errorf("profile = %+v, want %s", profile, f.user.Email)
+	}
+
+	recorder = serve(t, request{userID: primitive.NewObjectID()}, func(c *gin.Context) { GetUserProfile(c, f.repos) })
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown user: status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
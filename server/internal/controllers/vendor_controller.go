@@ -3,152 +3,98 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/cascade"
 	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 )
 
 type VendorProfileResponse struct {
-	User           interface{}   `json:"user"`
-	Vendor         interface{}   `json:"vendor"`
-	Items          []interface{} `json:"items"`
-	FoodCourts     []interface{} `json:"foodCourts"`
-	Managers       []interface{} `json:"managers"`
-	FoodCourtItems []interface{} `json:"foodCourtItems"`
+	User           interface{}            `json:"user"`
+	Vendor         interface{}            `json:"vendor"`
+	Items          []models.Item          `json:"items"`
+	FoodCourts     []interface{}          `json:"foodCourts"`
+	Managers       []interface{}          `json:"managers"`
+	FoodCourtItems []models.ItemFoodCourt `json:"foodCourtItems"`
 }
 
-func GetVendorProfile(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func GetVendorProfile(c *gin.Context, repos *repository.Repositories) {
+	vendor := actor.From(c).Vendor
 	ctx := context.Background()
-	collections := struct {
-		users          *mongo.Collection
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		managers       *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		users:          db.Collection("users"),
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		managers:       db.Collection("managers"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
 	var response VendorProfileResponse
 
-	var user struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		Role      string             `bson:"role" json:"role"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-	err = collections.users.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
+	user, err := repos.Users.FindByID(ctx, vendor.UserID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
-	response.User = user
+	response.User = struct {
+		ID        primitive.ObjectID `json:"id,omitempty"`
+		Name      string             `json:"name"`
+		Email     string             `json:"email"`
+		Role      string             `json:"role"`
+		CreatedAt time.Time          `json:"createdAt"`
+	}{user.ID, user.Name, user.Email, user.Role, user.CreatedAt}
 
-	var vendor struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-		ShopName  string             `bson:"shopName" json:"shopName"`
-		GST       string             `bson:"gst,omitempty" json:"gst,omitempty"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor profile not found")
-		return
-	}
-	response.Vendor = vendor
+	response.Vendor = struct {
+		ID        primitive.ObjectID `json:"id,omitempty"`
+		UserID    primitive.ObjectID `json:"user_id"`
+		ShopName  string             `json:"shopName"`
+		GST       string             `json:"gst,omitempty"`
+		CreatedAt time.Time          `json:"createdAt"`
+	}{vendor.ID, vendor.UserID, vendor.ShopName, vendor.GST, vendor.CreatedAt}
 
-	itemsCursor, err := collections.items.Find(ctx, bson.M{"vendor_id": vendor.ID})
-	if err == nil {
-		defer itemsCursor.Close(ctx)
-		itemsCursor.All(ctx, &response.Items)
+	if items, err := repos.Items.ListByVendor(ctx, vendor.ID); err == nil {
+		response.Items = items
 	}
 
 	var foodCourtIDs []primitive.ObjectID
-	foodCourtsCursor, err := collections.foodCourts.Find(ctx, bson.M{"vendor_ids": vendor.ID})
-	if err == nil {
-		defer foodCourtsCursor.Close(ctx)
-		var foodCourts []interface{}
-		for foodCourtsCursor.Next(ctx) {
-			var foodCourt struct {
-				ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-				Name      string             `bson:"name" json:"name"`
-				Location  string             `bson:"location" json:"location"`
-				IsOpen    bool               `bson:"isOpen" json:"isOpen"`
-				CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-			}
-			if err := foodCourtsCursor.Decode(&foodCourt); err == nil {
-				foodCourts = append(foodCourts, foodCourt)
-				foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
-			}
+	if foodCourts, err := repos.FoodCourts.ListByVendor(ctx, vendor.ID); err == nil {
+		for _, foodCourt := range foodCourts {
+			response.FoodCourts = append(response.FoodCourts, struct {
+				ID        primitive.ObjectID `json:"id,omitempty"`
+				Name      string             `json:"name"`
+				Location  string             `json:"location"`
+				IsOpen    bool               `json:"isOpen"`
+				CreatedAt time.Time          `json:"createdAt"`
+			}{foodCourt.ID, foodCourt.Name, foodCourt.Location, foodCourt.IsOpen, foodCourt.CreatedAt})
+			foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
 		}
-		response.FoodCourts = foodCourts
 	}
 
-	managersCursor, err := collections.managers.Find(ctx, bson.M{"vendor_id": vendor.ID})
-	if err == nil {
-		defer managersCursor.Close(ctx)
-		var managers []interface{}
-		for managersCursor.Next(ctx) {
-			var manager struct {
-				ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-				ContactNo string             `bson:"contact_no" json:"contact_no"`
-				IsActive  bool               `bson:"isActive" json:"isActive"`
-				CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-			}
-			if err := managersCursor.Decode(&manager); err == nil {
-				managers = append(managers, manager)
-			}
+	if managers, err := repos.Managers.ListByVendor(ctx, vendor.ID); err == nil {
+		for _, manager := range managers {
+			response.Managers = append(response.Managers, struct {
+				ID        primitive.ObjectID `json:"id,omitempty"`
+				ContactNo string             `json:"contact_no"`
+				IsActive  bool               `json:"isActive"`
+				CreatedAt time.Time          `json:"createdAt"`
+			}{manager.ID, manager.ContactNo, manager.IsActive, manager.CreatedAt})
 		}
-		response.Managers = managers
 	}
 
-	if len(response.Items) > 0 {
-		var itemIDs []primitive.ObjectID
+	if len(response.Items) > 0 && len(foodCourtIDs) > 0 {
+		itemIDs := map[primitive.ObjectID]bool{}
 		for _, item := range response.Items {
-			if itemMap, ok := item.(bson.M); ok {
-				if id, exists := itemMap["_id"]; exists {
-					if objID, ok := id.(primitive.ObjectID); ok {
-						itemIDs = append(itemIDs, objID)
-					}
-				}
-			}
+			itemIDs[item.ID] = true
 		}
 
-		if len(itemIDs) > 0 && len(foodCourtIDs) > 0 {
-			foodCourtItemsCursor, err := collections.foodCourtItems.Find(ctx, bson.M{
-				"item_id":      bson.M{"$in": itemIDs},
-				"foodcourt_id": bson.M{"$in": foodCourtIDs},
-			})
-			if err == nil {
-				defer foodCourtItemsCursor.Close(ctx)
-				foodCourtItemsCursor.All(ctx, &response.FoodCourtItems)
+		if listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, foodCourtIDs); err == nil {
+			for _, listing := range listings {
+				if itemIDs[listing.ItemID] {
+					response.FoodCourtItems = append(response.FoodCourtItems, listing)
+				}
 			}
 		}
 	}
@@ -156,19 +102,7 @@ func GetVendorProfile(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Vendor profile retrieved successfully", response)
 }
 
-func UpdateVendorProfile(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func UpdateVendorProfile(c *gin.Context, repos *repository.Repositories) {
 	var updateData struct {
 		Name     *string `json:"name,omitempty"`
 		Email    *string `json:"email,omitempty"`
@@ -181,17 +115,11 @@ func UpdateVendorProfile(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
+	vendor := actor.From(c).Vendor
 	ctx := context.Background()
-	collections := struct {
-		users   *mongo.Collection
-		vendors *mongo.Collection
-	}{
-		users:   db.Collection("users"),
-		vendors: db.Collection("vendors"),
-	}
 
 	if updateData.Name != nil || updateData.Email != nil {
-		userUpdate := bson.M{}
+		userUpdate := repository.Fields{}
 		if updateData.Name != nil {
 			userUpdate["name"] = *updateData.Name
 		}
@@ -200,19 +128,17 @@ func UpdateVendorProfile(c *gin.Context, db *mongo.Database) {
 		}
 		userUpdate["updatedAt"] = primitive.NewDateTimeFromTime(time.Now())
 
-		_, err := collections.users.UpdateOne(
-			ctx,
-			bson.M{"_id": userObjID},
-			bson.M{"$set": userUpdate},
-		)
-		if err != nil {
+		if err := repos.Users.Update(ctx, vendor.UserID, userUpdate); mongo.IsDuplicateKeyError(err) {
+			utils.RespondError(c, http.StatusConflict, "Email already exists")
+			return
+		} else if err != nil {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to update user profile")
 			return
 		}
 	}
 
 	if updateData.ShopName != nil || updateData.GST != nil {
-		vendorUpdate := bson.M{}
+		vendorUpdate := repository.Fields{}
 		if updateData.ShopName != nil {
 			vendorUpdate["shopName"] = *updateData.ShopName
 		}
@@ -221,21 +147,17 @@ func UpdateVendorProfile(c *gin.Context, db *mongo.Database) {
 		}
 		vendorUpdate["updatedAt"] = primitive.NewDateTimeFromTime(time.Now())
 
-		_, err := collections.vendors.UpdateOne(
-			ctx,
-			bson.M{"user_id": userObjID},
-			bson.M{"$set": vendorUpdate},
-		)
-		if err != nil {
+		if err := repos.Vendors.Update(ctx, vendor.ID, vendorUpdate); err != nil {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to update vendor profile")
 			return
 		}
+		actor.Invalidate(vendor.UserID)
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Profile updated successfully", nil)
 }
 
-func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
+func GetVendorProfileByID(c *gin.Context, repos *repository.Repositories) {
 	vendorID := c.Param("id")
 	vendorObjID, err := primitive.ObjectIDFromHex(vendorID)
 	if err != nil {
@@ -244,27 +166,17 @@ func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
 
 	type ItemFoodCourtDetail struct {
-		ItemFoodCourtID primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		ItemID          primitive.ObjectID `bson:"item_id" json:"item_id"`
-		FoodCourtID     primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id"`
-		Status          string             `bson:"status" json:"status"`
-		Price           *float64           `bson:"price,omitempty" json:"price,omitempty"`
-		TimeSlot        string             `bson:"timeSlot" json:"timeSlot"`
-		IsActive        bool               `bson:"isActive" json:"isActive"`
-		UpdatedAt       primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
+		ItemFoodCourtID primitive.ObjectID `json:"id,omitempty"`
+		ItemID          primitive.ObjectID `json:"item_id"`
+		FoodCourtID     primitive.ObjectID `json:"foodcourt_id"`
+		Status          string             `json:"status"`
+		Price           *float64           `json:"price,omitempty"`
+		TimeSlot        string             `json:"timeSlot"`
+		TimeSlots       []string           `json:"timeSlots,omitempty"`
+		IsActive        bool               `json:"isActive"`
+		UpdatedAt       time.Time          `json:"updatedAt"`
 
 		ItemName        string  `json:"item_name"`
 		ItemDescription string  `json:"item_description,omitempty"`
@@ -281,10 +193,10 @@ func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
 
 	var response struct {
 		Vendor struct {
-			ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-			ShopName  string             `bson:"shopName" json:"shopName"`
-			GST       string             `bson:"gst,omitempty" json:"gst,omitempty"`
-			CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
+			ID        primitive.ObjectID `json:"id,omitempty"`
+			ShopName  string             `json:"shopName"`
+			GST       string             `json:"gst,omitempty"`
+			CreatedAt time.Time          `json:"createdAt"`
 		} `json:"vendor"`
 		ItemFoodCourtDetails []ItemFoodCourtDetail `json:"item_foodcourt_details"`
 		FoodCourts           []interface{}         `json:"foodCourts"`
@@ -296,60 +208,32 @@ func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
 		} `json:"summary"`
 	}
 
-	var vendor struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		ShopName  string             `bson:"shopName" json:"shopName"`
-		GST       string             `bson:"gst,omitempty" json:"gst,omitempty"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"_id": vendorObjID}).Decode(&vendor)
+	vendor, err := repos.Vendors.FindByID(ctx, vendorObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
 		return
 	}
-	response.Vendor = vendor
+	response.Vendor.ID = vendor.ID
+	response.Vendor.ShopName = vendor.ShopName
+	response.Vendor.GST = vendor.GST
+	response.Vendor.CreatedAt = vendor.CreatedAt
 
 	var foodCourtIDs []primitive.ObjectID
-	var foodCourtMap = make(map[primitive.ObjectID]struct {
-		Name     string
-		Location string
-		IsOpen   bool
-		Timings  *string
-	})
+	foodCourtMap := make(map[primitive.ObjectID]models.FoodCourt)
 
-	foodCourtsCursor, err := collections.foodCourts.Find(ctx, bson.M{"vendor_ids": vendorObjID})
+	foodCourts, err := repos.FoodCourts.ListByVendor(ctx, vendorObjID)
 	if err == nil {
-		defer foodCourtsCursor.Close(ctx)
-		for foodCourtsCursor.Next(ctx) {
-			var foodCourt struct {
-				ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-				Name     string             `bson:"name" json:"name"`
-				Location string             `bson:"location" json:"location"`
-				IsOpen   bool               `bson:"isOpen" json:"isOpen"`
-				Timings  *string            `bson:"timings,omitempty" json:"timings,omitempty"`
-			}
-			if err := foodCourtsCursor.Decode(&foodCourt); err == nil {
-				foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
-				foodCourtMap[foodCourt.ID] = struct {
-					Name     string
-					Location string
-					IsOpen   bool
-					Timings  *string
-				}{
-					Name:     foodCourt.Name,
-					Location: foodCourt.Location,
-					IsOpen:   foodCourt.IsOpen,
-					Timings:  foodCourt.Timings,
-				}
-
-				response.FoodCourts = append(response.FoodCourts, map[string]interface{}{
-					"id":       foodCourt.ID,
-					"name":     foodCourt.Name,
-					"location": foodCourt.Location,
-					"isOpen":   foodCourt.IsOpen,
-					"timings":  foodCourt.Timings,
-				})
-			}
+		for _, foodCourt := range foodCourts {
+			foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
+			foodCourtMap[foodCourt.ID] = foodCourt
+
+			response.FoodCourts = append(response.FoodCourts, map[string]interface{}{
+				"id":       foodCourt.ID,
+				"name":     foodCourt.Name,
+				"location": foodCourt.Location,
+				"isOpen":   foodCourt.IsOpen,
+				"timings":  optionalString(foodCourt.Timings),
+			})
 		}
 	}
 
@@ -359,107 +243,52 @@ func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	foodCourtItemsCursor, err := collections.foodCourtItems.Find(ctx, bson.M{
-		"foodcourt_id": bson.M{"$in": foodCourtIDs},
-	})
-	if err == nil {
-		defer foodCourtItemsCursor.Close(ctx)
-
-		var itemIDs []primitive.ObjectID
-		var itemFoodCourtMap = make(map[primitive.ObjectID][]bson.M)
-
-		for foodCourtItemsCursor.Next(ctx) {
-			var fci bson.M
-			if err := foodCourtItemsCursor.Decode(&fci); err == nil {
-				if itemID, ok := fci["item_id"].(primitive.ObjectID); ok {
-					itemIDs = append(itemIDs, itemID)
-
-					if _, exists := itemFoodCourtMap[itemID]; !exists {
-						itemFoodCourtMap[itemID] = []bson.M{}
-					}
-					itemFoodCourtMap[itemID] = append(itemFoodCourtMap[itemID], fci)
-				}
-			}
+	listings, listingsErr := repos.ItemFoodCourts.ListByFoodCourts(ctx, foodCourtIDs)
+	items, itemsErr := repos.Items.ListByVendor(ctx, vendorObjID)
+	if listingsErr == nil && itemsErr == nil {
+		itemMap := make(map[primitive.ObjectID]models.Item)
+		for _, item := range items {
+			itemMap[item.ID] = item
 		}
 
-		if len(itemIDs) > 0 {
+		for _, fci := range listings {
+			item, exists := itemMap[fci.ItemID]
+			if !exists {
+				continue
+			}
+			foodCourt := foodCourtMap[fci.FoodCourtID]
+
+			detail := ItemFoodCourtDetail{
+				ItemFoodCourtID: fci.ID,
+				ItemID:          fci.ItemID,
+				FoodCourtID:     fci.FoodCourtID,
+				Status:          fci.Status,
+				Price:           fci.Price,
+				TimeSlot:        fci.TimeSlot,
+				TimeSlots:       fci.TimeSlots,
+				IsActive:        fci.IsActive,
+				UpdatedAt:       fci.UpdatedAt,
+
+				ItemName:        item.Name,
+				ItemDescription: item.Description,
+				ItemBasePrice:   item.BasePrice,
+				ItemCategory:    item.Category,
+				ItemIsVeg:       item.IsVeg,
+				ItemIsSpecial:   item.IsSpecial,
+
+				FoodCourtName:     foodCourt.Name,
+				FoodCourtLocation: foodCourt.Location,
+				FoodCourtIsOpen:   foodCourt.IsOpen,
+				FoodCourtTimings:  optionalString(foodCourt.Timings),
+			}
 
-			itemsCursor, err := collections.items.Find(ctx, bson.M{
-				"_id":       bson.M{"$in": itemIDs},
-				"vendor_id": vendorObjID,
-			})
-			if err == nil {
-				defer itemsCursor.Close(ctx)
-
-				var itemMap = make(map[primitive.ObjectID]bson.M)
-				for itemsCursor.Next(ctx) {
-					var item bson.M
-					if err := itemsCursor.Decode(&item); err == nil {
-						if itemID, ok := item["_id"].(primitive.ObjectID); ok {
-							itemMap[itemID] = item
-						}
-					}
-				}
+			response.ItemFoodCourtDetails = append(response.ItemFoodCourtDetails, detail)
 
-				for itemID, itemDetails := range itemMap {
-
-					if fciList, exists := itemFoodCourtMap[itemID]; exists {
-						for _, fci := range fciList {
-							foodCourtID, ok := fci["foodcourt_id"].(primitive.ObjectID)
-							if !ok {
-								continue
-							}
-
-							fcDetails, fcExists := foodCourtMap[foodCourtID]
-							if !fcExists {
-								continue
-							}
-
-							detail := ItemFoodCourtDetail{
-								ItemFoodCourtID: fci["_id"].(primitive.ObjectID),
-								ItemID:          itemID,
-								FoodCourtID:     foodCourtID,
-								Status:          fci["status"].(string),
-								TimeSlot:        fci["timeSlot"].(string),
-							}
-
-							if price, ok := fci["price"].(float64); ok {
-								detail.Price = &price
-							}
-
-							if isActive, ok := fci["isActive"].(bool); ok {
-								detail.IsActive = isActive
-							}
-
-							if updatedAt, ok := fci["updatedAt"].(primitive.DateTime); ok {
-								detail.UpdatedAt = updatedAt
-							}
-
-							detail.ItemName = itemDetails["name"].(string)
-							if desc, ok := itemDetails["description"].(string); ok {
-								detail.ItemDescription = desc
-							}
-							detail.ItemBasePrice = itemDetails["basePrice"].(float64)
-							detail.ItemCategory = itemDetails["category"].(string)
-							detail.ItemIsVeg = itemDetails["isVeg"].(bool)
-							detail.ItemIsSpecial = itemDetails["isSpecial"].(bool)
-
-							detail.FoodCourtName = fcDetails.Name
-							detail.FoodCourtLocation = fcDetails.Location
-							detail.FoodCourtIsOpen = fcDetails.IsOpen
-							detail.FoodCourtTimings = fcDetails.Timings
-
-							response.ItemFoodCourtDetails = append(response.ItemFoodCourtDetails, detail)
-
-							response.Summary.TotalItems++
-							if detail.Status == "available" {
-								response.Summary.AvailableItems++
-							} else if detail.Status == "sellingfast" {
-								response.Summary.SellingFastItems++
-							}
-						}
-					}
-				}
+			response.Summary.TotalItems++
+			if detail.Status == "available" {
+				response.Summary.AvailableItems++
+			} else if detail.Status == "sellingfast" {
+				response.Summary.SellingFastItems++
 			}
 		}
 	}
@@ -469,77 +298,30 @@ func GetVendorProfileByID(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Vendor profile retrieved successfully", response)
 }
 
-func GetVendorItems(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
+// optionalString is nil for an empty value, which is how an unset string
+// field reads back from the database.
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
 	}
+	return &value
+}
 
+func GetVendorItems(c *gin.Context, repos *repository.Repositories) {
 	ctx := context.Background()
-	collections := struct {
-		vendors *mongo.Collection
-		items   *mongo.Collection
-	}{
-		vendors: db.Collection("vendors"),
-		items:   db.Collection("items"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	cursor, err := collections.items.Find(ctx, bson.M{"vendor_id": vendor.ID})
+	items, err := repos.Items.ListByVendor(ctx, vendor.ID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch items")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var items []struct {
-		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name        string             `bson:"name" json:"name"`
-		Description string             `bson:"description,omitempty" json:"description,omitempty"`
-		BasePrice   float64            `bson:"basePrice" json:"basePrice"`
-		Category    string             `bson:"category" json:"category"`
-		IsVeg       bool               `bson:"isVeg" json:"isVeg"`
-		IsSpecial   bool               `bson:"isSpecial" json:"isSpecial"`
-		CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt   primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
-
-	if err := cursor.All(ctx, &items); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process items")
-		return
-	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Items retrieved successfully", items)
 }
 
-func CreateItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func CreateItem(c *gin.Context, repos *repository.Repositories) {
 	var itemData struct {
 		Name        string  `json:"name" validate:"required,min=2,max=100"`
 		Description string  `json:"description,omitempty" validate:"omitempty,max=500"`
@@ -555,57 +337,31 @@ func CreateItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors *mongo.Collection
-		items   *mongo.Collection
-	}{
-		vendors: db.Collection("vendors"),
-		items:   db.Collection("items"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	item := bson.M{
-		"name":        itemData.Name,
-		"description": itemData.Description,
-		"basePrice":   itemData.BasePrice,
-		"category":    itemData.Category,
-		"isVeg":       itemData.IsVeg,
-		"isSpecial":   itemData.IsSpecial,
-		"vendor_id":   vendor.ID,
-		"createdAt":   primitive.NewDateTimeFromTime(time.Now()),
-		"updatedAt":   primitive.NewDateTimeFromTime(time.Now()),
+	now := time.Now()
+	item := models.Item{
+		Name:        itemData.Name,
+		Description: itemData.Description,
+		BasePrice:   itemData.BasePrice,
+		Category:    itemData.Category,
+		IsVeg:       itemData.IsVeg,
+		IsSpecial:   itemData.IsSpecial,
+		VendorID:    vendor.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	result, err := collections.items.InsertOne(ctx, item)
-	if err != nil {
+	if err := repos.Items.Create(ctx, &item); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to create item")
 		return
 	}
 
-	utils.RespondSuccess(c, http.StatusCreated, "Item created successfully", bson.M{"id": result.InsertedID})
+	utils.RespondSuccess(c, http.StatusCreated, "Item created successfully", bson.M{"id": item.ID})
 }
 
-func UpdateItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func UpdateItem(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Param("id")
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
@@ -628,24 +384,10 @@ func UpdateItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors *mongo.Collection
-		items   *mongo.Collection
-	}{
-		vendors: db.Collection("vendors"),
-		items:   db.Collection("items"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	updateFields := bson.M{}
+	updateFields := repository.Fields{}
 	if updateData.Name != nil {
 		updateFields["name"] = *updateData.Name
 	}
@@ -672,37 +414,23 @@ func UpdateItem(c *gin.Context, db *mongo.Database) {
 
 	updateFields["updatedAt"] = primitive.NewDateTimeFromTime(time.Now())
 
-	result, err := collections.items.UpdateOne(
-		ctx,
-		bson.M{"_id": itemObjID, "vendor_id": vendor.ID},
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
+	err = repos.Items.UpdateOwned(ctx, vendor.ID, itemObjID, updateFields)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
+		return
+	} else if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to update item")
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
-		return
+	if updatedItem, err := repos.Items.FindByID(ctx, itemObjID); err == nil {
+		broadcastItem(repos, updatedItem, "update")
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Item updated successfully", nil)
 }
 
-func DeleteItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func DeleteItem(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	itemID := c.Param("id")
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
@@ -711,53 +439,28 @@ func DeleteItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors *mongo.Collection
-		items   *mongo.Collection
-	}{
-		vendors: db.Collection("vendors"),
-		items:   db.Collection("items"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	result, err := collections.items.DeleteOne(
-		ctx,
-		bson.M{"_id": itemObjID, "vendor_id": vendor.ID},
-	)
+	itemToDelete, err := repos.Items.FindOwned(ctx, vendor.ID, itemObjID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to delete item")
+		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
 		return
 	}
 
-	if result.DeletedCount == 0 {
+	report, err := cascadeFor(c, db).TrashItem(ctx, itemToDelete.ID)
+	if err == cascade.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
 		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Item deleted successfully", nil)
-}
-
-func GetVendorItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to delete item")
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+	utils.RespondSuccess(c, http.StatusOK, "Item deleted successfully", gin.H{"removed": report})
+}
 
+func GetVendorItem(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Param("id")
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
@@ -766,38 +469,12 @@ func GetVendorItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors *mongo.Collection
-		items   *mongo.Collection
-	}{
-		vendors: db.Collection("vendors"),
-		items:   db.Collection("items"),
-	}
-
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
 
-	var item struct {
-		ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name        string             `bson:"name" json:"name"`
-		Description string             `bson:"description,omitempty" json:"description,omitempty"`
-		BasePrice   float64            `bson:"basePrice" json:"basePrice"`
-		Category    string             `bson:"category" json:"category"`
-		IsVeg       bool               `bson:"isVeg" json:"isVeg"`
-		IsSpecial   bool               `bson:"isSpecial" json:"isSpecial"`
-		CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt"`
-		UpdatedAt   primitive.DateTime `bson:"updatedAt" json:"updatedAt"`
-	}
+	vendor := actor.From(c).Vendor
 
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": vendor.ID}).Decode(&item)
+	item, err := repos.Items.FindOwned(ctx, vendor.ID, itemObjID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			utils.RespondError(c, http.StatusNotFound, "Item not found")
 		} else {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch item")
@@ -808,106 +485,62 @@ func GetVendorItem(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Item retrieved successfully", item)
 }
 
-func GetVendorFoodCourtItems(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
+func GetVendorFoodCourtItems(c *gin.Context, repos *repository.Repositories) {
+	ctx := context.Background()
+
+	vendor := actor.From(c).Vendor
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	items, err := repos.Items.ListByVendor(ctx, vendor.ID)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food court items")
 		return
 	}
-
-	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		foodCourtItems *mongo.Collection
-		foodCourts     *mongo.Collection
-		items          *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
+	itemIDs := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, item.ID)
 	}
+	itemByID := indexBy(items, func(item models.Item) primitive.ObjectID { return item.ID })
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
+	listings, err := repos.ItemFoodCourts.ListByItems(ctx, itemIDs)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food court items")
 		return
 	}
-
-	pipeline := []bson.M{
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item",
-		}},
-		{"$unwind": "$item"},
-		{"$match": bson.M{"item.vendor_id": vendor.ID}},
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "foodcourt",
-		}},
-		{"$unwind": "$foodcourt"},
-		{"$project": bson.M{
-			"_id":           1,
-			"item_id":       1,
-			"itemName":      "$item.name",
-			"foodcourt_id":  1,
-			"foodcourtName": "$foodcourt.name",
-			"location":      "$foodcourt.location",
-			"status":        1,
-			"price":         1,
-			"isActive":      1,
-			"timeSlot":      1,
-			"createdAt":     1,
-			"updatedAt":     1,
-		}},
-	}
-
-	cursor, err := collections.foodCourtItems.Aggregate(ctx, pipeline)
+	courtIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		courtIDs = append(courtIDs, listing.FoodCourtID)
+	}
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food court items")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process food court items")
 		return
 	}
-	defer cursor.Close(ctx)
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
 	var foodCourtItems []bson.M
-	if err := cursor.All(ctx, &foodCourtItems); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process food court items")
-		return
+	for _, listing := range listings {
+		court, ok := courtByID[listing.FoodCourtID]
+		if !ok {
+			continue
+		}
+		entry := pick(document(listing), "_id", "item_id", "foodcourt_id", "status", "price", "isActive", "timeSlot", "timeSlots", "createdAt", "updatedAt")
+		entry["itemName"] = itemByID[listing.ItemID].Name
+		entry["foodcourtName"] = court.Name
+		entry["location"] = court.Location
+		foodCourtItems = append(foodCourtItems, entry)
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Food court items retrieved successfully", foodCourtItems)
 }
 
-func CreateFoodCourtItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func CreateFoodCourtItem(c *gin.Context, repos *repository.Repositories) {
 	var itemData struct {
 		ItemID      primitive.ObjectID `json:"itemId" validate:"required"`
 		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
 		Status      string             `json:"status" validate:"required,oneof=available notavailable sellingfast finishingsoon"`
 		Price       *float64           `json:"price,omitempty"`
-		TimeSlot    string             `json:"timeSlot" validate:"required,oneof=breakfast lunch snacks dinner"`
+		TimeSlot    string             `json:"timeSlot" validate:"required_without=TimeSlots,omitempty,oneof=breakfast lunch snacks dinner"`
+		TimeSlots   []string           `json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"`
 	}
 
 	if err := c.BindJSON(&itemData); err != nil {
@@ -915,41 +548,22 @@ func CreateFoodCourtItem(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		foodCourtItems *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-	}
-
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
+	timeSlot, timeSlots, err := models.NormalizeTimeSlots(itemData.TimeSlot, itemData.TimeSlots)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
+		utils.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	var item struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemData.ItemID, "vendor_id": vendor.ID}).Decode(&item)
-	if err != nil {
+	ctx := context.Background()
+
+	vendor := actor.From(c).Vendor
+
+	if _, err := repos.Items.FindOwned(ctx, vendor.ID, itemData.ItemID); err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
 		return
 	}
 
-	var foodCourt struct {
-		VendorIDs []primitive.ObjectID `bson:"vendor_ids"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": itemData.FoodCourtID}).Decode(&foodCourt)
+	foodCourt, err := repos.FoodCourts.FindByID(ctx, itemData.FoodCourtID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
@@ -967,57 +581,38 @@ func CreateFoodCourtItem(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	existing, err := collections.foodCourtItems.CountDocuments(ctx, bson.M{
-		"item_id":      itemData.ItemID,
-		"foodcourt_id": itemData.FoodCourtID,
-	})
-	if err == nil && existing > 0 {
+	if _, err := repos.ItemFoodCourts.Find(ctx, itemData.ItemID, itemData.FoodCourtID); err == nil {
 		utils.RespondError(c, http.StatusConflict, "Item already exists in this food court")
 		return
 	}
 
-	foodCourtItem := bson.M{
-		"item_id":      itemData.ItemID,
-		"foodcourt_id": itemData.FoodCourtID,
-		"status":       itemData.Status,
-		"price":        itemData.Price,
-		"timeSlot":     itemData.TimeSlot,
-		"isActive":     true,
-		"createdAt":    primitive.NewDateTimeFromTime(time.Now()),
-		"updatedAt":    primitive.NewDateTimeFromTime(time.Now()),
+	now := time.Now()
+	foodCourtItem := models.ItemFoodCourt{
+		ItemID:      itemData.ItemID,
+		FoodCourtID: itemData.FoodCourtID,
+		Status:      itemData.Status,
+		Price:       itemData.Price,
+		TimeSlot:    timeSlot,
+		TimeSlots:   timeSlots,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	result, err := collections.foodCourtItems.InsertOne(ctx, foodCourtItem)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to add item to food court")
+	if err := repos.ItemFoodCourts.Create(ctx, &foodCourtItem); mongo.IsDuplicateKeyError(err) {
+		utils.RespondError(c, http.StatusConflict, "Item already exists in this food court")
 		return
-	}
-
-	var createdItemFoodCourt models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&createdItemFoodCourt)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch created item")
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to add item to food court")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(createdItemFoodCourt, "create")
+	broadcastItemFoodCourt(repos, foodCourtItem, "create")
 
-	utils.RespondSuccess(c, http.StatusCreated, "Item added to food court successfully", bson.M{"id": result.InsertedID})
+	utils.RespondSuccess(c, http.StatusCreated, "Item added to food court successfully", bson.M{"id": foodCourtItem.ID})
 }
 
-func UpdateFoodCourtItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func UpdateFoodCourtItem(c *gin.Context, repos *repository.Repositories) {
 	foodCourtItemID := c.Param("id")
 	foodCourtItemObjID, err := primitive.ObjectIDFromHex(foodCourtItemID)
 	if err != nil {
@@ -1026,10 +621,11 @@ func UpdateFoodCourtItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	var updateData struct {
-		Status   *string  `json:"status,omitempty" validate:"omitempty,oneof=available notavailable sellingfast finishingsoon"`
-		Price    *float64 `json:"price,omitempty"`
-		TimeSlot *string  `json:"timeSlot,omitempty" validate:"omitempty,oneof=breakfast lunch snacks dinner"`
-		IsActive *bool    `json:"isActive,omitempty"`
+		Status    *string  `json:"status,omitempty" validate:"omitempty,oneof=available notavailable sellingfast finishingsoon"`
+		Price     *float64 `json:"price,omitempty"`
+		TimeSlot  *string  `json:"timeSlot,omitempty" validate:"omitempty,oneof=breakfast lunch snacks dinner"`
+		TimeSlots []string `json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"`
+		IsActive  *bool    `json:"isActive,omitempty"`
 	}
 
 	if err := c.BindJSON(&updateData); err != nil {
@@ -1038,113 +634,77 @@ func UpdateFoodCourtItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		foodCourtItems *mongo.Collection
-		items          *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	updateFields := bson.M{}
+	updateFields := repository.Fields{}
 	if updateData.Status != nil {
 		updateFields["status"] = *updateData.Status
 	}
 	if updateData.Price != nil {
 		updateFields["price"] = *updateData.Price
 	}
-	if updateData.TimeSlot != nil {
-		updateFields["timeSlot"] = *updateData.TimeSlot
-	}
 	if updateData.IsActive != nil {
 		updateFields["isActive"] = *updateData.IsActive
 	}
+	updateSlots := updateData.TimeSlot != nil || updateData.TimeSlots != nil
 
-	if len(updateFields) == 0 {
+	if len(updateFields) == 0 && !updateSlots {
 		utils.RespondError(c, http.StatusBadRequest, "No valid fields to update")
 		return
 	}
 
 	updateFields["updatedAt"] = primitive.NewDateTimeFromTime(time.Now())
 
-	pipeline := []bson.M{
-		{"$match": bson.M{"_id": foodCourtItemObjID}},
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item",
-		}},
-		{"$unwind": "$item"},
-		{"$match": bson.M{"item.vendor_id": vendor.ID}},
-	}
-
-	cursor, err := collections.foodCourtItems.Aggregate(ctx, pipeline)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to verify ownership")
-		return
+	listing, err := repos.ItemFoodCourts.FindByID(ctx, foodCourtItemObjID)
+	if err == nil {
+		_, err = repos.Items.FindOwned(ctx, vendor.ID, listing.ItemID)
 	}
-	defer cursor.Close(ctx)
-
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil || len(results) == 0 {
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Food court item not found or access denied")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to verify ownership")
+		return
 	}
 
-	result, err := collections.foodCourtItems.UpdateOne(
-		ctx,
-		bson.M{"_id": foodCourtItemObjID},
-		bson.M{"$set": updateFields},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update food court item")
-		return
+	if updateSlots {
+		primary := ""
+		if updateData.TimeSlot != nil {
+			primary = *updateData.TimeSlot
+		}
+		timeSlot, timeSlots, err := listing.UpdatedTimeSlots(primary, updateData.TimeSlots)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		updateFields["timeSlot"] = timeSlot
+		updateFields["timeSlots"] = timeSlots
 	}
 
-	if result.MatchedCount == 0 {
+	err = repos.ItemFoodCourts.Update(ctx, foodCourtItemObjID, updateFields)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Food court item not found")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update food court item")
+		return
 	}
 
-	var updatedItem models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{"_id": foodCourtItemObjID}).Decode(&updatedItem)
+	updatedItem, err := repos.ItemFoodCourts.FindByID(ctx, foodCourtItemObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch updated item")
 		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(updatedItem, "update")
+	broadcastItemFoodCourt(repos, updatedItem, "update")
 
 	utils.RespondSuccess(c, http.StatusOK, "Food court item updated successfully", gin.H{
 		"updatedItem": updatedItem,
 	})
 }
 
-func DeleteFoodCourtItem(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func DeleteFoodCourtItem(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Query("itemId")
 	foodCourtID := c.Query("foodCourtId")
 
@@ -1166,40 +726,15 @@ func DeleteFoodCourtItem(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		foodCourtItems *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	var item struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": vendor.ID}).Decode(&item)
-	if err != nil {
+	if _, err := repos.Items.FindOwned(ctx, vendor.ID, itemObjID); err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
 		return
 	}
 
-	var foodCourt struct {
-		VendorIDs []primitive.ObjectID `bson:"vendor_ids"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": foodCourtObjID}).Decode(&foodCourt)
+	foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Food court not found")
 		return
@@ -1217,107 +752,56 @@ func DeleteFoodCourtItem(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
-	var itemToDelete models.ItemFoodCourt
-	err = collections.foodCourtItems.FindOne(ctx, bson.M{
-		"item_id":      itemObjID,
-		"foodcourt_id": foodCourtObjID,
-	}).Decode(&itemToDelete)
+	itemToDelete, err := repos.ItemFoodCourts.Find(ctx, itemObjID, foodCourtObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Food court item association not found")
 		return
 	}
 
-	result, err := collections.foodCourtItems.DeleteOne(
-		ctx,
-		bson.M{
-			"item_id":      itemObjID,
-			"foodcourt_id": foodCourtObjID,
-		},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to delete food court item")
-		return
-	}
-
-	if result.DeletedCount == 0 {
+	err = repos.ItemFoodCourts.Delete(ctx, itemToDelete.ID)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Food court item association not found")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to delete food court item")
+		return
 	}
 
-	utils.BroadcastItemFoodCourtUpdate(itemToDelete, "delete")
+	broadcastItemFoodCourt(repos, itemToDelete, "delete")
 
 	utils.RespondSuccess(c, http.StatusOK, "Item removed from food court successfully", nil)
 }
 
-func GetVendorFoodCourts(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
+func GetVendorFoodCourts(c *gin.Context, repos *repository.Repositories) {
+	ctx := context.Background()
+
+	vendor := actor.From(c).Vendor
 
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	found, err := repos.FoodCourts.ListByVendor(ctx, vendor.ID)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
 		return
 	}
 
-	ctx := context.Background()
-	collections := struct {
-		vendors    *mongo.Collection
-		foodCourts *mongo.Collection
-	}{
-		vendors:    db.Collection("vendors"),
-		foodCourts: db.Collection("foodcourts"),
+	type foodCourtSummary struct {
+		ID        primitive.ObjectID `json:"id,omitempty"`
+		Name      string             `json:"name"`
+		Location  string             `json:"location"`
+		Timings   string             `json:"timings,omitempty"`
+		IsOpen    bool               `json:"isOpen"`
+		Weekends  bool               `json:"weekends"`
+		Weekdays  bool               `json:"weekdays"`
+		CreatedAt time.Time          `json:"createdAt"`
 	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
-
-	cursor, err := collections.foodCourts.Find(ctx, bson.M{"vendor_ids": vendor.ID})
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch food courts")
-		return
-	}
-	defer cursor.Close(ctx)
-
-	var foodCourts []struct {
-		ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name      string             `bson:"name" json:"name"`
-		Location  string             `bson:"location" json:"location"`
-		Timings   string             `bson:"timings,omitempty" json:"timings,omitempty"`
-		IsOpen    bool               `bson:"isOpen" json:"isOpen"`
-		Weekends  bool               `bson:"weekends" json:"weekends"`
-		Weekdays  bool               `bson:"weekdays" json:"weekdays"`
-		CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt"`
-	}
-
-	if err := cursor.All(ctx, &foodCourts); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process food courts")
-		return
+	foodCourts := []foodCourtSummary{}
+	for _, fc := range found {
+		foodCourts = append(foodCourts, foodCourtSummary{fc.ID, fc.Name, fc.Location, fc.Timings, fc.IsOpen, fc.Weekends, fc.Weekdays, fc.CreatedAt})
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Food courts retrieved successfully", foodCourts)
 }
-func GetItemFoodCourts(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func GetItemFoodCourts(c *gin.Context, repos *repository.Repositories) {
 	itemID := c.Param("id")
 	itemObjID, err := primitive.ObjectIDFromHex(itemID)
 	if err != nil {
@@ -1326,75 +810,46 @@ func GetItemFoodCourts(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourtItems *mongo.Collection
-		foodCourts     *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-		foodCourts:     db.Collection("foodcourts"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	var item struct {
-		ID   primitive.ObjectID `bson:"_id"`
-		Name string             `bson:"name"`
-	}
-	err = collections.items.FindOne(ctx, bson.M{"_id": itemObjID, "vendor_id": vendor.ID}).Decode(&item)
+	item, err := repos.Items.FindOwned(ctx, vendor.ID, itemObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusNotFound, "Item not found or access denied")
 		return
 	}
 
-	pipeline := []bson.M{
-		{"$match": bson.M{"item_id": itemObjID}},
-		{"$lookup": bson.M{
-			"from":         "foodcourts",
-			"localField":   "foodcourt_id",
-			"foreignField": "_id",
-			"as":           "foodcourt",
-		}},
-		{"$unwind": "$foodcourt"},
-		{"$project": bson.M{
-			"id":            "$_id",
-			"_id":           0,
-			"status":        1,
-			"price":         1,
-			"timeSlot":      1,
-			"isActive":      1,
-			"createdAt":     1,
-			"updatedAt":     1,
-			"foodCourtId":   "$foodcourt._id",
-			"foodCourtName": "$foodcourt.name",
-			"location":      "$foodcourt.location",
-			"isOpen":        "$foodcourt.isOpen",
-			"timings":       "$foodcourt.timings",
-		}},
-	}
-
-	cursor, err := collections.foodCourtItems.Aggregate(ctx, pipeline)
+	listings, err := repos.ItemFoodCourts.ListByItem(ctx, itemObjID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch item food courts")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var foodCourtAssociations []bson.M
-	if err := cursor.All(ctx, &foodCourtAssociations); err != nil {
+	courtIDs := []primitive.ObjectID{}
+	for _, listing := range listings {
+		courtIDs = append(courtIDs, listing.FoodCourtID)
+	}
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to process item food courts")
 		return
 	}
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
+
+	var foodCourtAssociations []bson.M
+	for _, listing := range listings {
+		court, ok := courtByID[listing.FoodCourtID]
+		if !ok {
+			continue
+		}
+		association := pick(document(listing), "status", "price", "timeSlot", "timeSlots", "isActive", "createdAt", "updatedAt")
+		association["id"] = listing.ID
+		association["foodCourtId"] = court.ID
+		association["foodCourtName"] = court.Name
+		association["location"] = court.Location
+		association["isOpen"] = court.IsOpen
+		copyFields(association, document(court), "timings")
+		foodCourtAssociations = append(foodCourtAssociations, association)
+	}
 
 	response := struct {
 		Item       interface{} `json:"item"`
@@ -1410,203 +865,71 @@ func GetItemFoodCourts(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Item food courts retrieved successfully", response)
 }
 
-func GetVendorManagers(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func GetVendorManagers(c *gin.Context, repos *repository.Repositories) {
 	ctx := context.Background()
-	collections := struct {
-		vendors  *mongo.Collection
-		managers *mongo.Collection
-		users    *mongo.Collection
-	}{
-		vendors:  db.Collection("vendors"),
-		managers: db.Collection("managers"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	pipeline := []bson.M{
-		{"$match": bson.M{"vendor_id": vendor.ID}},
-		{"$lookup": bson.M{
-			"from":         "users",
-			"localField":   "user_id",
-			"foreignField": "_id",
-			"as":           "user",
-		}},
-		{"$unwind": "$user"},
-		{"$project": bson.M{
-			"_id":        1,
-			"user_id":    1,
-			"userName":   "$user.name",
-			"userEmail":  "$user.email",
-			"contact_no": 1,
-			"isActive":   1,
-			"createdAt":  1,
-			"updatedAt":  1,
-		}},
-	}
-
-	cursor, err := collections.managers.Aggregate(ctx, pipeline)
+	records, err := repos.Managers.ListByVendor(ctx, vendor.ID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch managers")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var managers []bson.M
-	if err := cursor.All(ctx, &managers); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to process managers")
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Managers retrieved successfully", managers)
-}
-
-func AddManager(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
+	userIDs := []primitive.ObjectID{}
+	for _, manager := range records {
+		userIDs = append(userIDs, manager.UserID)
 	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
+	users, err := repos.Users.List(ctx, repository.UserQuery{IDs: userIDs})
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
-	var managerData struct {
-		UserID      primitive.ObjectID `json:"userId" validate:"required"`
-		ContactNo   string             `json:"contactNo" validate:"required,e164"`
-		FoodCourtID primitive.ObjectID `json:"foodCourtId" validate:"required"`
-	}
-
-	if err := c.BindJSON(&managerData); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid request payload")
-		return
-	}
-
-	ctx := context.Background()
-	collections := struct {
-		vendors    *mongo.Collection
-		managers   *mongo.Collection
-		users      *mongo.Collection
-		foodCourts *mongo.Collection
-	}{
-		vendors:    db.Collection("vendors"),
-		managers:   db.Collection("managers"),
-		users:      db.Collection("users"),
-		foodCourts: db.Collection("foodcourts"),
-	}
-
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
-
-	var user struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.users.FindOne(ctx, bson.M{"_id": managerData.UserID}).Decode(&user)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "User not found")
-		return
-	}
-
-	var foodCourt struct {
-		VendorIDs []primitive.ObjectID `bson:"vendor_ids"`
-	}
-	err = collections.foodCourts.FindOne(ctx, bson.M{"_id": managerData.FoodCourtID}).Decode(&foodCourt)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Food court not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to process managers")
 		return
 	}
+	userByID := indexBy(users, func(user models.User) primitive.ObjectID { return user.ID })
 
-	vendorInFoodCourt := false
-	for _, vid := range foodCourt.VendorIDs {
-		if vid == vendor.ID {
-			vendorInFoodCourt = true
-			break
+	var managers []bson.M
+	for _, manager := range records {
+		user, ok := userByID[manager.UserID]
+		if !ok {
+			continue
 		}
-	}
-	if !vendorInFoodCourt {
-		utils.RespondError(c, http.StatusForbidden, "Vendor is not part of this food court")
-		return
+		entry := pick(document(manager), "_id", "user_id", "contact_no", "isActive", "createdAt", "updatedAt")
+		entry["userName"] = user.Name
+		entry["userEmail"] = user.Email
+		entry["foodCourtIds"] = manager.Courts()
+		managers = append(managers, entry)
 	}
 
-	existing, err := collections.managers.CountDocuments(ctx, bson.M{
-		"user_id":   managerData.UserID,
-		"vendor_id": vendor.ID,
-	})
-	if err == nil && existing > 0 {
-		utils.RespondError(c, http.StatusConflict, "Manager already exists for this vendor")
-		return
-	}
+	utils.RespondSuccess(c, http.StatusOK, "Managers retrieved successfully", managers)
+}
 
-	manager := bson.M{
-		"user_id":      managerData.UserID,
-		"vendor_id":    vendor.ID,
-		"foodcourt_id": managerData.FoodCourtID,
-		"contact_no":   managerData.ContactNo,
-		"isActive":     true,
-		"createdAt":    primitive.NewDateTimeFromTime(time.Now()),
-		"updatedAt":    primitive.NewDateTimeFromTime(time.Now()),
+// checkVendorFoodCourts makes sure the vendor is live and has a stall in
+// every court, so it can only put managers where it trades. A zero status means all is well.
+func checkVendorFoodCourts(ctx context.Context, repos *repository.Repositories, vendorID primitive.ObjectID, foodCourtIDs []primitive.ObjectID) (int, string) {
+	if _, err := repos.Vendors.FindByID(ctx, vendorID); err != nil {
+		return http.StatusNotFound, "Vendor not found"
 	}
 
-	result, err := collections.managers.InsertOne(ctx, manager)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to add manager")
-		return
-	}
+	for _, foodCourtID := range foodCourtIDs {
+		foodCourt, err := repos.FoodCourts.FindByID(ctx, foodCourtID)
+		if err != nil {
+			return http.StatusNotFound, "Food court not found"
+		}
 
-	_, err = collections.users.UpdateOne(
-		ctx,
-		bson.M{"_id": managerData.UserID},
-		bson.M{"$set": bson.M{
-			"role":      "manager",
-			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
-		}},
-	)
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
-		return
+		vendorInFoodCourt := false
+		for _, vid := range foodCourt.VendorIDs {
+			if vid == vendorID {
+				vendorInFoodCourt = true
+				break
+			}
+		}
+		if !vendorInFoodCourt {
+			return http.StatusForbidden, "Vendor is not part of this food court"
+		}
 	}
-	utils.RespondSuccess(c, http.StatusCreated, "Manager added successfully", bson.M{"id": result.InsertedID})
+	return 0, ""
 }
-func UpdateManager(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
 
+func UpdateManager(c *gin.Context, repos *repository.Repositories) {
 	managerID := c.Param("id")
 	managerObjID, err := primitive.ObjectIDFromHex(managerID)
 	if err != nil {
@@ -1615,9 +938,10 @@ func UpdateManager(c *gin.Context, db *mongo.Database) {
 	}
 
 	var updateData struct {
-		ContactNo   *string             `json:"contactNo,omitempty"`
-		IsActive    *bool               `json:"isActive,omitempty"`
-		FoodCourtID *primitive.ObjectID `json:"foodCourtId,omitempty"`
+		ContactNo    *string               `json:"contactNo,omitempty"`
+		IsActive     *bool                 `json:"isActive,omitempty"`
+		FoodCourtIDs *[]primitive.ObjectID `json:"foodCourtIds,omitempty"` // Replaces the manager's courts
+		FoodCourtID  *primitive.ObjectID   `json:"foodCourtId,omitempty"`  // Replaces them with this one court
 	}
 
 	if err := c.BindJSON(&updateData); err != nil {
@@ -1628,16 +952,10 @@ func UpdateManager(c *gin.Context, db *mongo.Database) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = db.Collection("vendors").FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 
-	updateFields := bson.M{"updatedAt": time.Now()}
+	updateFields := repository.Fields{"updatedAt": time.Now()}
+	var unset []string
 
 	if updateData.ContactNo != nil {
 		updateFields["contact_no"] = *updateData.ContactNo
@@ -1646,63 +964,45 @@ func UpdateManager(c *gin.Context, db *mongo.Database) {
 		updateFields["isActive"] = *updateData.IsActive
 	}
 
-	if updateData.FoodCourtID != nil {
-		var foodCourt struct {
-			VendorIDs []primitive.ObjectID `bson:"vendor_ids"`
+	if updateData.FoodCourtIDs != nil || updateData.FoodCourtID != nil {
+		var foodCourtIDs []primitive.ObjectID
+		if updateData.FoodCourtIDs != nil {
+			foodCourtIDs = *updateData.FoodCourtIDs
 		}
-		err = db.Collection("foodcourts").FindOne(ctx, bson.M{"_id": *updateData.FoodCourtID}).Decode(&foodCourt)
-		if err != nil {
-			utils.RespondError(c, http.StatusNotFound, "Target food court not found")
+		if updateData.FoodCourtID != nil {
+			foodCourtIDs = append(foodCourtIDs, *updateData.FoodCourtID)
+		}
+		foodCourtIDs = models.Manager{FoodCourtIDs: foodCourtIDs}.Courts()
+		if len(foodCourtIDs) == 0 {
+			utils.RespondError(c, http.StatusBadRequest, "At least one food court is required")
 			return
 		}
 
-		isAuthorized := false
-		for _, vID := range foodCourt.VendorIDs {
-			if vID == vendor.ID {
-				isAuthorized = true
-				break
+		if status, message := checkVendorFoodCourts(ctx, repos, vendor.ID, foodCourtIDs); status != 0 {
+			if status == http.StatusForbidden {
+				message = "You are not authorized to assign managers to this food court"
 			}
-		}
-
-		if !isAuthorized {
-			utils.RespondError(c, http.StatusForbidden, "You are not authorized to assign managers to this food court")
+			utils.RespondError(c, status, message)
 			return
 		}
 
-		updateFields["foodcourt_id"] = *updateData.FoodCourtID
-	}
-
-	result, err := db.Collection("managers").UpdateOne(
-		ctx,
-		bson.M{"_id": managerObjID, "vendor_id": vendor.ID},
-		bson.M{"$set": updateFields},
-	)
-
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Database error during update")
-		return
+		updateFields["foodcourt_ids"] = foodCourtIDs
+		unset = append(unset, "foodcourt_id")
 	}
 
-	if result.MatchedCount == 0 {
+	manager, err := repos.Managers.UpdateForVendor(ctx, vendor.ID, managerObjID, updateFields, unset...)
+	if err == repository.ErrNotFound {
 		utils.RespondError(c, http.StatusNotFound, "Manager record not found or access denied")
 		return
+	} else if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Database error during update")
+		return
 	}
+	actor.Invalidate(manager.UserID)
 
 	utils.RespondSuccess(c, http.StatusOK, "Manager assignment updated successfully", nil)
 }
-func RemoveManager(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
-
+func RemoveManager(c *gin.Context, db *mongo.Database, repos *repository.Repositories) {
 	managerID := c.Param("id")
 	managerObjID, err := primitive.ObjectIDFromHex(managerID)
 	if err != nil {
@@ -1711,51 +1011,29 @@ func RemoveManager(c *gin.Context, db *mongo.Database) {
 	}
 
 	ctx := context.Background()
-	collections := struct {
-		vendors  *mongo.Collection
-		managers *mongo.Collection
-	}{
-		vendors:  db.Collection("vendors"),
-		managers: db.Collection("managers"),
-	}
 
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
+	vendor := actor.From(c).Vendor
+
+	manager, err := repos.Managers.DeleteForVendor(ctx, vendor.ID, managerObjID)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, http.StatusNotFound, "Manager not found or access denied")
 		return
 	}
-
-	result, err := collections.managers.DeleteOne(
-		ctx,
-		bson.M{"_id": managerObjID, "vendor_id": vendor.ID},
-	)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to remove manager")
 		return
 	}
 
-	if result.DeletedCount == 0 {
-		utils.RespondError(c, http.StatusNotFound, "Manager not found or access denied")
-		return
-	}
+	actor.Invalidate(manager.UserID)
 
-	var manager struct {
-		UserID primitive.ObjectID `bson:"user_id"`
+	// The user stays a manager while they still manage for another vendor.
+	remaining, err := repos.Managers.CountByUser(ctx, manager.UserID)
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
+		return
 	}
-	err = collections.managers.FindOne(ctx, bson.M{"_id": managerObjID}).Decode(&manager)
-	if err == nil {
-
-		_, err = db.Collection("users").UpdateOne(
-			ctx,
-			bson.M{"_id": manager.UserID},
-			bson.M{"$set": bson.M{
-				"role":      "user",
-				"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
-			}},
-		)
+	if remaining == 0 {
+		_, err = updateUserRoles(ctx, db, repos, manager.UserID, nil, []string{permissions.RoleManager})
 		if err != nil {
 			utils.RespondError(c, http.StatusInternalServerError, "Failed to update user role")
 			return
@@ -1765,197 +1043,79 @@ func RemoveManager(c *gin.Context, db *mongo.Database) {
 	utils.RespondSuccess(c, http.StatusOK, "Manager removed successfully", nil)
 }
 
-func GetAllUsersForManager(c *gin.Context, db *mongo.Database) {
-	userID, _ := c.Get("userID")
-	userObjID, _ := primitive.ObjectIDFromHex(userID.(string))
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	searchEmail := c.Query("email")
-
-	filter := bson.M{
-		"_id":  bson.M{"$ne": userObjID},
-		"role": bson.M{"$nin": []string{"admin", "vendor"}},
-	}
-
-	if searchEmail != "" {
-		filter["email"] = bson.M{
-			"$regex": primitive.Regex{Pattern: searchEmail, Options: "i"},
-		}
-	}
-
-	findOptions := options.Find().
-		SetLimit(100).
-		SetProjection(bson.M{"password": 0}).
-		SetSort(bson.M{"name": 1})
-
-	cursor, err := db.Collection("users").Find(ctx, filter, findOptions)
+func GetVendorManager(c *gin.Context, repos *repository.Repositories) {
+	managerID := c.Param("id")
+	managerObjID, err := primitive.ObjectIDFromHex(managerID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to fetch users")
+		utils.RespondError(c, http.StatusBadRequest, "Invalid manager ID")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var users []struct {
-		ID        primitive.ObjectID `bson:"_id" json:"id"`
-		Name      string             `bson:"name" json:"name"`
-		Email     string             `bson:"email" json:"email"`
-		CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	}
 
-	if err := cursor.All(ctx, &users); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Error decoding users")
-		return
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	utils.RespondSuccess(c, http.StatusOK, "Users fetched successfully", gin.H{
-		"users": users,
-	})
-}
+	vendor := actor.From(c).Vendor
 
-func GetVendorManager(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+	manager, err := repos.Managers.FindForVendor(ctx, vendor.ID, managerObjID)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, 404, "Manager not found")
 		return
 	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
+		utils.RespondError(c, 500, "Failed to fetch manager data")
 		return
 	}
-
-	managerID := c.Param("id")
-	managerObjID, err := primitive.ObjectIDFromHex(managerID)
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid manager ID")
+	user, err := repos.Users.FindByID(ctx, manager.UserID)
+	if err == repository.ErrNotFound {
+		utils.RespondError(c, 404, "Manager not found")
 		return
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	var vendor struct {
-		ID primitive.ObjectID `bson:"_id"`
-	}
-	err = db.Collection("vendors").FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
+		utils.RespondError(c, 500, "Failed to fetch manager data")
 		return
 	}
 
-	pipeline := []bson.M{
-
-		{"$match": bson.M{"_id": managerObjID, "vendor_id": vendor.ID}},
-
-		{"$lookup": bson.M{
-			"from":         "users",
-			"localField":   "user_id",
-			"foreignField": "_id",
-			"as":           "user_info",
-		}},
-		{"$unwind": "$user_info"},
-
-		{"$lookup": bson.M{
-			"from": "managers",
-			"let":  bson.M{"m_user_id": "$user_id"},
-			"pipeline": []bson.M{
-				{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$user_id", "$$m_user_id"}}}},
-				{"$lookup": bson.M{
-					"from":         "foodcourts",
-					"localField":   "foodcourt_id",
-					"foreignField": "_id",
-					"as":           "details",
-				}},
-				{"$unwind": "$details"},
-				{"$replaceRoot": bson.M{"newRoot": "$details"}},
-			},
-			"as": "assigned_food_courts",
-		}},
-
-		{"$project": bson.M{
-			"id":         "$_id",
-			"user_id":    1,
-			"userName":   "$user_info.name",
-			"userEmail":  "$user_info.email",
-			"contact_no": 1,
-			"isActive":   1,
-			"foodCourts": "$assigned_food_courts",
-
-			"createdAt": 1,
-			"updatedAt": 1,
-		}},
-	}
-
-	cursor, err := db.Collection("managers").Aggregate(ctx, pipeline)
+	// The courts the user runs for any vendor, as their full records.
+	records, err := repos.Managers.ListByUser(ctx, manager.UserID)
 	if err != nil {
-		utils.RespondError(c, 500, "Failed to fetch manager data")
+		utils.RespondError(c, 500, "Error processing results")
 		return
 	}
-	defer cursor.Close(ctx)
-
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
+	courtIDs := []primitive.ObjectID{}
+	for _, record := range records {
+		courtIDs = append(courtIDs, record.Courts()...)
+	}
+	courts, err := repos.FoodCourts.List(ctx, repository.FoodCourtQuery{IDs: courtIDs})
+	if err != nil {
 		utils.RespondError(c, 500, "Error processing results")
 		return
 	}
+	courtByID := indexBy(courts, func(fc models.FoodCourt) primitive.ObjectID { return fc.ID })
 
-	if len(results) == 0 {
-		utils.RespondError(c, 404, "Manager not found")
-		return
+	foodCourts := []bson.M{}
+	for _, courtID := range courtIDs {
+		if court, ok := courtByID[courtID]; ok {
+			foodCourts = append(foodCourts, document(court))
+		}
 	}
 
-	utils.RespondSuccess(c, 200, "Manager details with food courts fetched", results[0])
+	result := pick(document(manager), "_id", "user_id", "contact_no", "isActive", "createdAt", "updatedAt")
+	result["id"] = manager.ID
+	result["userName"] = user.Name
+	result["userEmail"] = user.Email
+	result["foodCourts"] = foodCourts
+
+	utils.RespondSuccess(c, 200, "Manager details with food courts fetched", result)
 }
 
-func GetVendorDashboardStats(c *gin.Context, db *mongo.Database) {
-
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "Invalid user ID")
-		return
-	}
+func GetVendorDashboardStats(c *gin.Context, repos *repository.Repositories) {
 
 	ctx := context.Background()
-	collections := struct {
-		vendors        *mongo.Collection
-		items          *mongo.Collection
-		foodCourts     *mongo.Collection
-		foodCourtItems *mongo.Collection
-		managers       *mongo.Collection
-	}{
-		vendors:        db.Collection("vendors"),
-		items:          db.Collection("items"),
-		foodCourts:     db.Collection("foodcourts"),
-		foodCourtItems: db.Collection("itemfoodcourts"),
-		managers:       db.Collection("managers"),
-	}
 
-	var vendor struct {
-		ID       primitive.ObjectID `bson:"_id"`
-		ShopName string             `bson:"shopName"`
-	}
-	err = collections.vendors.FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
-	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor not found")
-		return
-	}
+	vendor := actor.From(c).Vendor
 	vendorObjID := vendor.ID
 
-	totalItems, err := collections.items.CountDocuments(ctx, bson.M{"vendor_id": vendorObjID})
-	if err != nil {
-		totalItems = 0
-	}
-
-	totalManagers, err := collections.managers.CountDocuments(ctx, bson.M{"vendor_id": vendorObjID})
+	totalManagers, err := repos.Managers.CountByVendor(ctx, vendorObjID)
 	if err != nil {
 		totalManagers = 0
 	}
@@ -1963,58 +1123,46 @@ func GetVendorDashboardStats(c *gin.Context, db *mongo.Database) {
 	var foodCourtIDs []primitive.ObjectID
 	var foodCourtMap = make(map[primitive.ObjectID]string)
 
-	foodCourtsCursor, err := collections.foodCourts.Find(ctx, bson.M{"vendor_ids": vendorObjID})
-	if err == nil {
-		defer foodCourtsCursor.Close(ctx)
-		for foodCourtsCursor.Next(ctx) {
-			var foodCourt struct {
-				ID   primitive.ObjectID `bson:"_id"`
-				Name string             `bson:"name"`
-			}
-			if err := foodCourtsCursor.Decode(&foodCourt); err == nil {
-				foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
-				foodCourtMap[foodCourt.ID] = foodCourt.Name
-			}
+	if foodCourts, err := repos.FoodCourts.ListByVendor(ctx, vendorObjID); err == nil {
+		for _, foodCourt := range foodCourts {
+			foodCourtIDs = append(foodCourtIDs, foodCourt.ID)
+			foodCourtMap[foodCourt.ID] = foodCourt.Name
 		}
 	}
 	totalFoodCourts := len(foodCourtIDs)
 
-	var itemIDs []primitive.ObjectID
-	itemsCursor, err := collections.items.Find(ctx, bson.M{"vendor_id": vendorObjID})
-	if err == nil {
-		defer itemsCursor.Close(ctx)
-		for itemsCursor.Next(ctx) {
-			var item struct {
-				ID primitive.ObjectID `bson:"_id"`
-			}
-			if err := itemsCursor.Decode(&item); err == nil {
-				itemIDs = append(itemIDs, item.ID)
+	itemNames := make(map[primitive.ObjectID]string)
+	if items, err := repos.Items.ListByVendor(ctx, vendorObjID); err == nil {
+		for _, item := range items {
+			itemNames[item.ID] = item.Name
+		}
+	}
+	totalItems := int64(len(itemNames))
+
+	// The vendor's listings in the courts it trades in.
+	var listings []models.ItemFoodCourt
+	if len(itemNames) > 0 && len(foodCourtIDs) > 0 {
+		if found, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, foodCourtIDs); err == nil {
+			for _, listing := range found {
+				if _, ok := itemNames[listing.ItemID]; ok {
+					listings = append(listings, listing)
+				}
 			}
 		}
 	}
 
-	totalFoodCourtItems := int64(0)
+	totalFoodCourtItems := int64(len(listings))
 	activeItems := int64(0)
 	availableItems := int64(0)
-
-	if len(itemIDs) > 0 && len(foodCourtIDs) > 0 {
-
-		totalFoodCourtItems, _ = collections.foodCourtItems.CountDocuments(ctx, bson.M{
-			"item_id":      bson.M{"$in": itemIDs},
-			"foodcourt_id": bson.M{"$in": foodCourtIDs},
-		})
-
-		activeItems, _ = collections.foodCourtItems.CountDocuments(ctx, bson.M{
-			"item_id":      bson.M{"$in": itemIDs},
-			"foodcourt_id": bson.M{"$in": foodCourtIDs},
-			"isActive":     true,
-		})
-
-		availableItems, _ = collections.foodCourtItems.CountDocuments(ctx, bson.M{
-			"item_id":      bson.M{"$in": itemIDs},
-			"foodcourt_id": bson.M{"$in": foodCourtIDs},
-			"status":       "available",
-		})
+	itemCounts := make(map[primitive.ObjectID]int64)
+	for _, listing := range listings {
+		if listing.IsActive {
+			activeItems++
+		}
+		if listing.Status == "available" {
+			availableItems++
+		}
+		itemCounts[listing.FoodCourtID]++
 	}
 
 	response := struct {
@@ -2050,10 +1198,7 @@ func GetVendorDashboardStats(c *gin.Context, db *mongo.Database) {
 	response.TotalStats.AvailableItems = availableItems
 
 	for foodCourtID, foodCourtName := range foodCourtMap {
-		itemCount, _ := collections.foodCourtItems.CountDocuments(ctx, bson.M{
-			"foodcourt_id": foodCourtID,
-			"item_id":      bson.M{"$in": itemIDs},
-		})
+		itemCount := itemCounts[foodCourtID]
 
 		response.FoodCourts = append(response.FoodCourts, struct {
 			ID        string `json:"id"`
@@ -2066,217 +1211,109 @@ func GetVendorDashboardStats(c *gin.Context, db *mongo.Database) {
 		})
 	}
 
-	if len(itemIDs) > 0 && len(foodCourtIDs) > 0 {
-		cursor, err := collections.foodCourtItems.Find(ctx, bson.M{
-			"item_id":      bson.M{"$in": itemIDs},
-			"foodcourt_id": bson.M{"$in": foodCourtIDs},
-		}, options.Find().SetSort(bson.M{"updatedAt": -1}).SetLimit(5))
-
-		if err == nil {
-			defer cursor.Close(ctx)
-			for cursor.Next(ctx) {
-				var fci struct {
-					ItemID      primitive.ObjectID `bson:"item_id"`
-					FoodCourtID primitive.ObjectID `bson:"foodcourt_id"`
-					Status      string             `bson:"status"`
-					UpdatedAt   primitive.DateTime `bson:"updatedAt"`
-				}
-				if err := cursor.Decode(&fci); err == nil {
-
-					var item struct {
-						Name string `bson:"name"`
-					}
-					collections.items.FindOne(ctx, bson.M{"_id": fci.ItemID}).Decode(&item)
-
-					foodCourtName := foodCourtMap[fci.FoodCourtID]
-
-					response.RecentUpdates = append(response.RecentUpdates, struct {
-						ItemName      string `json:"itemName"`
-						FoodCourtName string `json:"foodCourtName"`
-						Status        string `json:"status"`
-						UpdatedAt     string `json:"updatedAt"`
-					}{
-						ItemName:      item.Name,
-						FoodCourtName: foodCourtName,
-						Status:        fci.Status,
-						UpdatedAt:     fci.UpdatedAt.Time().Format(time.RFC3339),
-					})
-				}
-			}
-		}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].UpdatedAt.After(listings[j].UpdatedAt) })
+	if len(listings) > 5 {
+		listings = listings[:5]
+	}
+	for _, fci := range listings {
+		response.RecentUpdates = append(response.RecentUpdates, struct {
+			ItemName      string `json:"itemName"`
+			FoodCourtName string `json:"foodCourtName"`
+			Status        string `json:"status"`
+			UpdatedAt     string `json:"updatedAt"`
+		}{
+			ItemName:      itemNames[fci.ItemID],
+			FoodCourtName: foodCourtMap[fci.FoodCourtID],
+			Status:        fci.Status,
+			UpdatedAt:     fci.UpdatedAt.Format(time.RFC3339),
+		})
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Dashboard stats retrieved successfully", response)
 }
 
-func SingleFoodCourtItems(c *gin.Context, db *mongo.Database) {
+func SingleFoodCourtItems(c *gin.Context, repos *repository.Repositories) {
 	foodCourtID := c.Param("id")
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User authentication required")
-		return
-	}
-
 	foodCourtObjID, err := primitive.ObjectIDFromHex(foodCourtID)
-	userObjID, err2 := primitive.ObjectIDFromHex(userID.(string))
-	if err != nil || err2 != nil {
+	if err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	ctx := context.TODO()
 
-	var vendor models.Vendor
-	err = db.Collection("vendors").FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
+	vendor := actor.From(c).Vendor
+
+	listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, []primitive.ObjectID{foodCourtObjID})
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor profile not found")
+		utils.RespondError(c, http.StatusInternalServerError, "Aggregation failed")
 		return
 	}
-
-	pipeline := []bson.M{
-
-		{"$match": bson.M{"foodcourt_id": foodCourtObjID}},
-
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "item_id",
-			"foreignField": "_id",
-			"as":           "item_details",
-		}},
-		{"$unwind": "$item_details"},
-
-		{"$match": bson.M{"item_details.vendor_id": vendor.ID}},
-
-		{"$project": bson.M{
-			"id":          "$_id",
-			"item_id":     "$item_id",
-			"name":        "$item_details.name",
-			"category":    "$item_details.category",
-			"description": "$item_details.description",
-			"basePrice":   "$item_details.basePrice",
-			"price":       "$price",
-
-			"status":   "$status",
-			"isActive": "$isActive",
-			"timeSlot": "$timeSlot",
-			"isVeg":    "$item_details.isVeg",
-		}},
-	}
-
-	cursor, err := db.Collection("itemfoodcourts").Aggregate(ctx, pipeline)
+	items, err := repos.Items.ListByVendor(ctx, vendor.ID)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Aggregation failed")
 		return
 	}
-	defer cursor.Close(ctx)
+	itemByID := indexBy(items, func(item models.Item) primitive.ObjectID { return item.ID })
 
 	var results []bson.M = []bson.M{}
-
-	if err := cursor.All(ctx, &results); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Decoding failed")
-		return
+	for _, listing := range listings {
+		item, ok := itemByID[listing.ItemID]
+		if !ok {
+			continue
+		}
+		result := listingWithItem(listing,
+			[]string{"_id", "item_id", "price", "status", "isActive", "timeSlot", "timeSlots"},
+			item, "name", "category", "description", "basePrice", "isVeg")
+		result["id"] = listing.ID
+		results = append(results, result)
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Items retrieved", results)
 }
 
-func GetVendorFoodCourtsForDisplay(c *gin.Context, db *mongo.Database) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
-		return
-	}
-
-	userObjID, _ := primitive.ObjectIDFromHex(userID.(string))
+func GetVendorFoodCourtsForDisplay(c *gin.Context, repos *repository.Repositories) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var vendor models.Vendor
-	err := db.Collection("vendors").FindOne(ctx, bson.M{"user_id": userObjID}).Decode(&vendor)
+	vendor := actor.From(c).Vendor
+
+	courts, err := repos.FoodCourts.ListByVendor(ctx, vendor.ID)
 	if err != nil {
-		utils.RespondError(c, http.StatusNotFound, "Vendor profile not found")
-		return
-	}
-
-	pipeline := []bson.M{
-		{"$match": bson.M{"vendor_ids": vendor.ID}},
-
-		{"$lookup": bson.M{
-			"from":         "itemfoodcourts",
-			"localField":   "_id",
-			"foreignField": "foodcourt_id",
-			"as":           "all_fc_items",
-		}},
-
-		{"$lookup": bson.M{
-			"from":         "items",
-			"localField":   "all_fc_items.item_id",
-			"foreignField": "_id",
-			"as":           "master_items",
-		}},
-
-		{"$project": bson.M{
-			"id":       "$_id",
-			"name":     1,
-			"location": 1,
-			"isOpen":   1,
-			"timings":  1,
-
-			"vendorItems": bson.M{
-				"$filter": bson.M{
-					"input": "$all_fc_items",
-					"as":    "fcItem",
-					"cond": bson.M{"$in": []interface{}{
-						"$$fcItem.item_id",
-						bson.M{
-							"$map": bson.M{
-								"input": bson.M{
-									"$filter": bson.M{
-										"input": "$master_items",
-										"as":    "mItem",
-										"cond":  bson.M{"$eq": []interface{}{"$$mItem.vendor_id", vendor.ID}},
-									},
-								},
-								"as": "filtered",
-								"in": "$$filtered._id",
-							},
-						},
-					}},
-				},
-			},
-		}},
-
-		{"$project": bson.M{
-			"id":         1,
-			"name":       1,
-			"location":   1,
-			"isOpen":     1,
-			"timings":    1,
-			"totalItems": bson.M{"$size": "$vendorItems"},
-			"activeItems": bson.M{
-				"$size": bson.M{
-					"$filter": bson.M{
-						"input": "$vendorItems",
-						"as":    "vi",
-						"cond":  bson.M{"$eq": []interface{}{"$$vi.isActive", true}},
-					},
-				},
-			},
-		}},
-		{"$sort": bson.M{"name": 1}},
-	}
-
-	cursor, err := db.Collection("foodcourts").Aggregate(ctx, pipeline)
+		utils.RespondError(c, http.StatusInternalServerError, "Failed to aggregate food court data")
+		return
+	}
+	courtIDs := []primitive.ObjectID{}
+	for _, court := range courts {
+		courtIDs = append(courtIDs, court.ID)
+	}
+	listings, err := repos.ItemFoodCourts.ListByFoodCourts(ctx, courtIDs)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "Failed to aggregate food court data")
 		return
 	}
-	defer cursor.Close(ctx)
+	vendorItems := vendorItemSet(ctx, repos, vendor.ID)
+
+	sort.SliceStable(courts, func(i, j int) bool { return courts[i].Name < courts[j].Name })
 
 	var results []bson.M = []bson.M{}
-	if err := cursor.All(ctx, &results); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "Failed to decode results")
-		return
+	for _, court := range courts {
+		totalItems, activeItems := 0, 0
+		for _, listing := range listings {
+			if listing.FoodCourtID != court.ID || !vendorItems[listing.ItemID] {
+				continue
+			}
+			totalItems++
+			if listing.IsActive {
+				activeItems++
+			}
+		}
+
+		result := pick(document(court), "_id", "name", "location", "isOpen", "timings")
+		result["id"] = court.ID
+		result["totalItems"] = totalItems
+		result["activeItems"] = activeItems
+		results = append(results, result)
 	}
 
 	utils.RespondSuccess(c, http.StatusOK, "Vendor food courts retrieved successfully", results)
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+func TestGetVendorManagersSkipsTrashedUsers(t *testing.T) {
+	f := newFixture(t)
+	live := f.addManager(t, f.addUser(t, "Ravi", "ravi@example.com"))
+	trashed := f.addUser(t, "Meena", "meena@example.com")
+	f.addManager(t, trashed)
+	if err := f.repos.Users.Update(context.Background(), trashed.ID, repository.Fields{"deletedAt": time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var managers []struct {
+		ID           primitive.ObjectID   `json:"_id"`
+		UserEmail    string               `json:"userEmail"`
+		FoodCourtIDs []primitive.ObjectID `json:"foodCourtIds"`
+	}
+	recorder := serve(t, request{actor: f.vendorActor()}, func(c *gin.Context) { GetVendorManagers(c, f.repos) })
+	response(t, recorder, http.StatusOK, &managers)
+
+	if len(managers) != 1 || managers[0].ID != live.ID || managers[0].UserEmail != "ravi@example.com" {
+		t.Fatalf("managers = %+v, want only ravi@example.com", managers)
+	}
+	if len(managers[0].FoodCourtIDs) != 1 || managers[0].FoodCourtIDs[0] != f.court.ID {
+		t.Errorf("foodCourtIds = %v, want [%s]", managers[0].FoodCourtIDs, f.court.ID.Hex())
+	}
+}
+
+func TestGetVendorFoodCourtsForDisplay(t *testing.T) {
+	f := newFixture(t)
+	if err := f.repos.ItemFoodCourts.Update(context.Background(), f.listing.ID, repository.Fields{"isActive": false}); err != nil {
+		t.Fatal(err)
+	}
+
+	var courts []struct {
+		ID          primitive.ObjectID `json:"id"`
+		Name        string             `json:"name"`
+		TotalItems  int                `json:"totalItems"`
+		ActiveItems int                `json:"activeItems"`
+	}
+	recorder := serve(t, request{actor: f.vendorActor()}, func(c *gin.Context) { GetVendorFoodCourtsForDisplay(c, f.repos) })
+	response(t, recorder, http.StatusOK, &courts)
+
+	if len(courts) != 1 || courts[0].ID != f.court.ID {
+		t.Fatalf("courts = %+v, want %s", courts, f.court.Name)
+	}
+	if courts[0].TotalItems != 1 || courts[0].ActiveItems != 0 {
+		t.Errorf("totalItems = %d, activeItems = %d, want 1 and 0", courts[0].TotalItems, courts[0].ActiveItems)
+	}
+}
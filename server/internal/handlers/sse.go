@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+	myws "github.com/MohdMusaiyab/infybyte/server/internal/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+const sseHeartbeatPeriod = 15 * time.Second
+
+// HandleEvents streams the same hub topics as HandleWebSocket over
+// Server-Sent Events, for networks that block WebSocket upgrades. Extra
+// topics come from ?topics=a,b. Each event id is a cursor of the hub's
+// epoch and the last sequence seen per topic, so the browser's automatic
+// Last-Event-ID on reconnect is enough to replay what was missed.
+func (h *WebSocketHandler) HandleEvents(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
+		return
+	}
+
+	claims, err := utils.ValidateToken(token, false)
+	if err != nil {
+		log.Printf("🚫 SSE Auth Failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+
+	roles := claims.EffectiveRoles()
+	client := myws.NewClient(claims.UserID, claims.Role, roles, h.resolveVendorIDs(claims.UserID, roles))
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	epoch, cursor, ok := parseEventCursor(lastEventID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+		return
+	}
+
+	topics := make(map[string]bool)
+	for _, topic := range strings.Split(c.Query("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics[topic] = true
+		}
+	}
+	for topic := range cursor {
+		topics[topic] = true
+	}
+	for topic := range topics {
+		if !canSubscribe(client, topic) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Topic not allowed: " + topic})
+			return
+		}
+	}
+
+	// The server-wide WriteTimeout would otherwise cut the stream off.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("SSE: could not clear write deadline: %v", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	h.Hub.Register <- client
+	defer func() {
+		h.Hub.Unregister <- client
+		log.Printf("❌ SSE stream closed for user %s", client.UserID)
+	}()
+
+	for topic := range topics {
+		if since, resuming := cursor[topic]; resuming {
+			h.Hub.Resume <- myws.ResumeRequest{Client: client, Topic: topic, Since: since, Epoch: epoch}
+		} else {
+			client.Subscribe(topic)
+		}
+	}
+
+	log.Printf("✅ SSE stream established for user %s (role: %s)", claims.UserID, claims.Role)
+
+	heartbeat := time.NewTicker(sseHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+
+		case frame, ok := <-client.Send:
+			if !ok {
+				return
+			}
+
+			var header struct {
+				Topic string `json:"topic"`
+				Seq   uint64 `json:"seq"`
+			}
+			if err := json.Unmarshal(frame, &header); err == nil && header.Topic != "" && header.Seq > 0 {
+				cursor[header.Topic] = header.Seq
+			}
+
+			if _, err := fmt.Fprintf(c.Writer, "id: %s\ndata: %s\n\n", formatEventCursor(h.Hub.Epoch(), cursor), frame); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// parseEventCursor reads "epoch;topic@seq,topic@seq". A cursor without an
+// epoch parses with an empty one, so resuming from it asks for a resync.
+func parseEventCursor(raw string) (string, map[string]uint64, bool) {
+	cursor := make(map[string]uint64)
+	epoch, list, found := strings.Cut(raw, ";")
+	if !found {
+		epoch, list = "", raw
+	}
+	if list == "" {
+		return epoch, cursor, true
+	}
+	for _, part := range strings.Split(list, ",") {
+		at := strings.LastIndex(part, "@")
+		if at <= 0 {
+			return "", nil, false
+		}
+		seq, err := strconv.ParseUint(part[at+1:], 10, 64)
+		if err != nil {
+			return "", nil, false
+		}
+		cursor[part[:at]] = seq
+	}
+	return epoch, cursor, true
+}
+
+func formatEventCursor(epoch string, cursor map[string]uint64) string {
+	parts := make([]string, 0, len(cursor))
+	for topic, seq := range cursor {
+		parts = append(parts, topic+"@"+strconv.FormatUint(seq, 10))
+	}
+	sort.Strings(parts)
+	return epoch + ";" + strings.Join(parts, ",")
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseEventCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantEpoch string
+		want      map[string]uint64
+		wantOK    bool
+	}{
+		{"empty", "", "", map[string]uint64{}, true},
+		{"epoch only", "e1;", "e1", map[string]uint64{}, true},
+		{"one topic", "e1;foodcourt:abc@12", "e1", map[string]uint64{"foodcourt:abc": 12}, true},
+		{"several topics", "e1;foodcourt:abc@12,user:u1@3", "e1", map[string]uint64{"foodcourt:abc": 12, "user:u1": 3}, true},
+		{"no epoch", "foodcourt:abc@12", "", map[string]uint64{"foodcourt:abc": 12}, true},
+		{"topic containing @", "e1;odd@topic@4", "e1", map[string]uint64{"odd@topic": 4}, true},
+		{"missing seq", "e1;foodcourt:abc", "", nil, false},
+		{"missing topic", "e1;@12", "", nil, false},
+		{"seq not a number", "e1;foodcourt:abc@twelve", "", nil, false},
+		{"negative seq", "e1;foodcourt:abc@-1", "", nil, false},
+		{"trailing comma", "e1;foodcourt:abc@12,", "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			epoch, cursor, ok := parseEventCursor(tt.raw)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %t, want %t", ok, tt.wantOK)
+			}
+			if epoch != tt.wantEpoch {
+				t.Errorf("epoch = %q, want %q", epoch, tt.wantEpoch)
+			}
+			if !maps.Equal(cursor, tt.want) {
+				t.Errorf("cursor = %v, want %v", cursor, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventCursorRoundTrip(t *testing.T) {
+	cursor := map[string]uint64{"foodcourt:abc": 12, "user:u1": 3, "role:admin": 0}
+	raw := formatEventCursor("e1", cursor)
+	if raw != "e1;foodcourt:abc@12,role:admin@0,user:u1@3" {
+		t.Errorf("formatEventCursor = %q", raw)
+	}
+
+	epoch, parsed, ok := parseEventCursor(raw)
+	if !ok || epoch != "e1" || !maps.Equal(parsed, cursor) {
+		t.Errorf("parseEventCursor(%q) = %q, %v, %t", raw, epoch, parsed, ok)
+	}
+}
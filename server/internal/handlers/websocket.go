@@ -1,15 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
 	"time"
 
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
 	myws "github.com/MohdMusaiyab/infybyte/server/internal/websocket"
 	"github.com/gin-gonic/gin"
 	gorillaws "github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -38,11 +44,12 @@ var upgrader = gorillaws.Upgrader{
 }
 
 type WebSocketHandler struct {
-	Hub *myws.Hub
+	Hub   *myws.Hub
+	Repos *repository.Repositories
 }
 
-func NewWebSocketHandler(hub *myws.Hub) *WebSocketHandler {
-	return &WebSocketHandler{Hub: hub}
+func NewWebSocketHandler(hub *myws.Hub, repos *repository.Repositories) *WebSocketHandler {
+	return &WebSocketHandler{Hub: hub, Repos: repos}
 }
 
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
@@ -61,14 +68,37 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	roles := claims.EffectiveRoles()
+	client := myws.NewClient(claims.UserID, claims.Role, roles, h.resolveVendorIDs(claims.UserID, roles))
+
+	// ?topic=<topic>&since=<seq>&epoch=<epoch> lets a reconnecting client
+	// pick up the events it missed on one topic.
+	resumeTopic := c.Query("topic")
+	var since uint64
+	if resumeTopic != "" {
+		if !canSubscribe(client, resumeTopic) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Topic not allowed"})
+			return
+		}
+		if raw := c.Query("since"); raw != "" {
+			since, err = strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since"})
+				return
+			}
+		}
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
 
-	client := myws.NewClient(claims.UserID, claims.Role)
 	h.Hub.Register <- client
+	if resumeTopic != "" {
+		h.Hub.Resume <- myws.ResumeRequest{Client: client, Topic: resumeTopic, Since: since, Epoch: c.Query("epoch")}
+	}
 
 	log.Printf("✅ WebSocket connection established for user %s (role: %s)", claims.UserID, claims.Role)
 
@@ -96,12 +126,75 @@ func (h *WebSocketHandler) readPump(conn *gorillaws.Conn, client *myws.Client) {
 	})
 
 	for {
-		_, _, err := conn.ReadMessage()
+		_, message, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("Read error for client %s: %v", client.ID, err)
 			break
 		}
+
+		reply, after := h.handleRequest(client, message)
+		h.sendReply(client, reply)
+		if after != nil {
+			after()
+		}
+	}
+}
+
+// canSubscribe allows anyone to follow a food court, while user, role and
+// vendor topics are limited to the client they belong to. Admins may follow
+// anything.
+func canSubscribe(client *myws.Client, topic string) bool {
+	kind, id, ok := myws.ParseTopic(topic)
+	if !ok {
+		return false
+	}
+	if permissions.Has(client.Roles, permissions.RoleAdmin) {
+		return true
+	}
+	switch kind {
+	case myws.TopicFoodCourt:
+		return true
+	case myws.TopicUser:
+		return id == client.UserID
+	case myws.TopicRole:
+		return permissions.Has(client.Roles, id)
+	case myws.TopicVendor:
+		return slices.Contains(client.VendorIDs, id)
+	}
+	return false
+}
+
+// resolveVendorIDs finds every vendor a vendor or manager account works
+// for: its own shop, and the vendor behind each of its manager records. The
+// client is put on each of those vendors' topics.
+func (h *WebSocketHandler) resolveVendorIDs(userID string, roles []string) []string {
+	if h.Repos == nil {
+		return nil
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var vendorIDs []string
+	if permissions.Has(roles, permissions.RoleVendor) {
+		if vendor, err := h.Repos.Vendors.FindByUser(ctx, userObjID); err == nil {
+			vendorIDs = append(vendorIDs, vendor.ID.Hex())
+		}
+	}
+	if permissions.Has(roles, permissions.RoleManager) {
+		managers, _ := h.Repos.Managers.ListByUser(ctx, userObjID)
+		for _, manager := range managers {
+			if id := manager.VendorID.Hex(); !slices.Contains(vendorIDs, id) {
+				vendorIDs = append(vendorIDs, id)
+			}
+		}
 	}
+	return vendorIDs
 }
 
 func (h *WebSocketHandler) writePump(conn *gorillaws.Conn, client *myws.Client) {
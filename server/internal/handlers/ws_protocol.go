@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+	myws "github.com/MohdMusaiyab/infybyte/server/internal/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpSnapshot    = "snapshot"
+	OpPing        = "ping"
+
+	ReplyAck      = "ack"
+	ReplyError    = "error"
+	ReplySnapshot = "snapshot"
+	ReplyPong     = "pong"
+)
+
+// Request is a frame sent by the client. ID is echoed back on the reply so
+// the client can match the two up.
+type Request struct {
+	ID    string          `json:"id,omitempty" validate:"omitempty,max=64"`
+	Op    string          `json:"op" validate:"required,oneof=subscribe unsubscribe snapshot ping"`
+	Topic string          `json:"topic,omitempty" validate:"omitempty,max=100"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Reply answers exactly one Request.
+type Reply struct {
+	ID    string      `json:"id,omitempty"`
+	Type  string      `json:"type"`
+	Op    string      `json:"op,omitempty"`
+	Topic string      `json:"topic,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+var (
+	errTopicRequired = errors.New("topic is required")
+	errInvalidTopic  = errors.New("invalid topic")
+	errForbidden     = errors.New("not allowed for your role")
+)
+
+// authorizeOp decides whether the client may run op against topic.
+func authorizeOp(client *myws.Client, op, topic string) error {
+	switch op {
+	case OpPing:
+		return nil
+	case OpSubscribe, OpUnsubscribe:
+		if topic == "" {
+			return errTopicRequired
+		}
+		if _, _, ok := myws.ParseTopic(topic); !ok {
+			return errInvalidTopic
+		}
+		if !canSubscribe(client, topic) {
+			return errForbidden
+		}
+		return nil
+	case OpSnapshot:
+		if topic == "" {
+			return errTopicRequired
+		}
+		kind, id, ok := myws.ParseTopic(topic)
+		if !ok || kind != myws.TopicFoodCourt || !primitive.IsValidObjectID(id) {
+			return errInvalidTopic
+		}
+		return nil
+	}
+	return errInvalidTopic
+}
+
+// subscribeData is the optional payload of a subscribe request. When Since
+// is set the hub replays the topic's events after that sequence number,
+// provided Epoch is the one the client's frames carried.
+type subscribeData struct {
+	Since *uint64 `json:"since"`
+	Epoch string  `json:"epoch"`
+}
+
+// handleRequest runs one client request. The returned func, if any, must be
+// called after the reply is queued; it is used to replay missed events so
+// they arrive after the ack.
+func (h *WebSocketHandler) handleRequest(client *myws.Client, frame []byte) (Reply, func()) {
+	var request Request
+	if err := json.Unmarshal(frame, &request); err != nil {
+		return Reply{Type: ReplyError, Error: "malformed message"}, nil
+	}
+
+	reply := Reply{ID: request.ID, Op: request.Op, Topic: request.Topic}
+
+	if err := utils.Validate.Struct(request); err != nil {
+		reply.Type = ReplyError
+		reply.Error = "invalid request"
+		return reply, nil
+	}
+
+	if err := authorizeOp(client, request.Op, request.Topic); err != nil {
+		reply.Type = ReplyError
+		reply.Error = err.Error()
+		return reply, nil
+	}
+
+	switch request.Op {
+	case OpPing:
+		reply.Type = ReplyPong
+		reply.Data = map[string]int64{"serverTime": time.Now().UnixMilli()}
+
+	case OpSubscribe:
+		var data subscribeData
+		if len(request.Data) > 0 {
+			if err := json.Unmarshal(request.Data, &data); err != nil {
+				reply.Type = ReplyError
+				reply.Error = "invalid subscribe data"
+				return reply, nil
+			}
+		}
+
+		reply.Type = ReplyAck
+		if data.Since == nil {
+			client.Subscribe(request.Topic)
+			return reply, nil
+		}
+		resume := myws.ResumeRequest{Client: client, Topic: request.Topic, Since: *data.Since, Epoch: data.Epoch}
+		return reply, func() { h.Hub.Resume <- resume }
+
+	case OpUnsubscribe:
+		client.Unsubscribe(request.Topic)
+		reply.Type = ReplyAck
+
+	case OpSnapshot:
+		_, id, _ := myws.ParseTopic(request.Topic)
+		foodCourtObjID, _ := primitive.ObjectIDFromHex(id)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		menu, err := controllers.LoadFoodCourtMenu(ctx, h.Repos, foodCourtObjID, nil)
+		if err != nil {
+			log.Printf("Snapshot failed for client %s: %v", client.ID, err)
+			reply.Type = ReplyError
+			reply.Error = "failed to load snapshot"
+			return reply, nil
+		}
+		reply.Type = ReplySnapshot
+		reply.Data = menu
+	}
+
+	return reply, nil
+}
+
+func (h *WebSocketHandler) sendReply(client *myws.Client, reply Reply) {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		log.Printf("Error marshaling reply for client %s: %v", client.ID, err)
+		return
+	}
+	if !client.Deliver(data) {
+		log.Printf("Dropped reply to client %s: send buffer full or closed", client.ID)
+	}
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer records messages instead of sending them, for local development
+// and tests. With a path it appends to that file, otherwise it logs.
+type LogMailer struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func NewLogMailer(path string) (*LogMailer, error) {
+	if path == "" {
+		return &LogMailer{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &LogMailer{file: file}, nil
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if m.file == nil {
+		log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, err := fmt.Fprintf(m.file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email such as password resets and address
+// verification.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv builds the mailer selected by MAILER. "smtp" needs SMTP_HOST
+// and MAIL_FROM; anything else falls back to the log mailer, which writes to
+// MAIL_LOG_FILE or to the server log.
+func NewFromEnv() (Mailer, error) {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		port := 587
+		if value := os.Getenv("SMTP_PORT"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT %q", value)
+			}
+			port = parsed
+		}
+		return NewSMTPMailer(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		})
+	default:
+		return NewLogMailer(os.Getenv("MAIL_LOG_FILE"))
+	}
+}
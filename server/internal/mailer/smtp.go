@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends through a relay using STARTTLS when the server offers it.
+type SMTPMailer struct {
+	config SMTPConfig
+	sender string
+}
+
+func NewSMTPMailer(config SMTPConfig) (*SMTPMailer, error) {
+	if config.Host == "" || config.From == "" {
+		return nil, errors.New("SMTP_HOST and MAIL_FROM are required for the smtp mailer")
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM %q: %w", config.From, err)
+	}
+	return &SMTPMailer{config: config, sender: from.Address}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.sender, []string{msg.To}, m.buildMessage(msg))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *SMTPMailer) buildMessage(msg Message) []byte {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&builder, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&builder, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(builder.String())
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/actor"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// VendorActor loads the shop the authenticated user owns into the context.
+// Users without a live shop are rejected.
+func VendorActor(repos *repository.Repositories) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userObjID, ok := authenticatedUser(c)
+		if !ok {
+			return
+		}
+
+		a, err := actor.LoadVendor(c.Request.Context(), repos, userObjID)
+		if err != nil {
+			actor.RespondError(c, err)
+			c.Abort()
+			return
+		}
+
+		actor.Set(c, a)
+		c.Next()
+	}
+}
+
+// ManagerActor loads the authenticated user's manager records into the
+// context, scoped to the court selected by the foodCourtId query parameter
+// or to their first court.
+func ManagerActor(repos *repository.Repositories) gin.HandlerFunc {
+	return managerActor(repos, false)
+}
+
+// ActiveManagerActor is ManagerActor for routes that change data: only
+// active manager records count, and users with none are rejected.
+func ActiveManagerActor(repos *repository.Repositories) gin.HandlerFunc {
+	return managerActor(repos, true)
+}
+
+func managerActor(repos *repository.Repositories, activeOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userObjID, ok := authenticatedUser(c)
+		if !ok {
+			return
+		}
+
+		foodCourtObjID := primitive.NilObjectID
+		if id := c.Query("foodCourtId"); id != "" {
+			var err error
+			if foodCourtObjID, err = primitive.ObjectIDFromHex(id); err != nil {
+				utils.RespondError(c, http.StatusBadRequest, "Invalid food court ID")
+				c.Abort()
+				return
+			}
+		}
+
+		a, err := actor.LoadManager(c.Request.Context(), repos, userObjID)
+		if err == nil && activeOnly {
+			a, err = a.Active()
+		}
+		if err == nil {
+			a, err = a.ForFoodCourt(foodCourtObjID)
+		}
+		if err != nil {
+			actor.RespondError(c, err)
+			c.Abort()
+			return
+		}
+
+		actor.Set(c, a)
+		c.Next()
+	}
+}
+
+func authenticatedUser(c *gin.Context) (primitive.ObjectID, bool) {
+	userObjID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		utils.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		c.Abort()
+		return primitive.NilObjectID, false
+	}
+	return userObjID, true
+}
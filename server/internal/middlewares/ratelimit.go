@@ -0,0 +1,148 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPolicy is a token bucket: Burst requests may be made at once and
+// the bucket refills at Rate tokens per second.
+type RateLimitPolicy struct {
+	Name  string
+	Rate  float64
+	Burst int
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // until the next token, when not allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// RateLimitStore keeps the buckets. The in-memory store is per instance;
+// a shared implementation makes limits hold across replicas.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error)
+}
+
+// DefaultRateLimitPolicies apply per route group unless overridden by
+// RATE_LIMIT_<GROUP>, e.g. RATE_LIMIT_USER="5/s:30" for five requests a
+// second with bursts of thirty.
+var DefaultRateLimitPolicies = map[string]RateLimitPolicy{
+	"auth":    {Name: "auth", Rate: 20.0 / 60, Burst: 10},
+	"user":    {Name: "user", Rate: 5, Burst: 30},
+	"vendor":  {Name: "vendor", Rate: 10, Burst: 60},
+	"manager": {Name: "manager", Rate: 10, Burst: 60},
+	"admin":   {Name: "admin", Rate: 10, Burst: 60},
+}
+
+type RateLimiter struct {
+	store    RateLimitStore
+	policies map[string]RateLimitPolicy
+}
+
+// NewRateLimiter loads the group policies, applying any environment
+// overrides.
+func NewRateLimiter(store RateLimitStore) (*RateLimiter, error) {
+	policies := make(map[string]RateLimitPolicy, len(DefaultRateLimitPolicies))
+	for name, policy := range DefaultRateLimitPolicies {
+		if spec := os.Getenv("RATE_LIMIT_" + strings.ToUpper(name)); spec != "" {
+			rate, burst, err := ParseRateLimit(spec)
+			if err != nil {
+				return nil, fmt.Errorf("RATE_LIMIT_%s: %w", strings.ToUpper(name), err)
+			}
+			policy.Rate, policy.Burst = rate, burst
+		}
+		policies[name] = policy
+	}
+	return &RateLimiter{store: store, policies: policies}, nil
+}
+
+// ParseRateLimit reads "<requests>/<s|m|h>[:<burst>]". The burst defaults
+// to the request count.
+func ParseRateLimit(spec string) (float64, int, error) {
+	limit, burstSpec, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+	count, unit, ok := strings.Cut(limit, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid rate limit %q", spec)
+	}
+
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests < 1 {
+		return 0, 0, fmt.Errorf("invalid request count in %q", spec)
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return 0, 0, fmt.Errorf("invalid period in %q, use s, m or h", spec)
+	}
+
+	burst := requests
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst < 1 {
+			return 0, 0, fmt.Errorf("invalid burst in %q", spec)
+		}
+	}
+
+	return float64(requests) / period.Seconds(), burst, nil
+}
+
+// Limit returns the middleware for a route group. Requests are keyed by the
+// authenticated user when AuthMiddleware ran first, otherwise by client IP.
+func (l *RateLimiter) Limit(group string) gin.HandlerFunc {
+	policy, ok := l.policies[group]
+	if !ok {
+		panic("rate limit policy not defined: " + group)
+	}
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, exists := c.Get("userID"); exists {
+			key = "user:" + userID.(string)
+		}
+
+		result, err := l.store.Take(c.Request.Context(), policy.Name+":"+key, policy)
+		if err != nil {
+			// A broken store must not take the API down with it.
+			log.Printf("Rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			utils.RespondError(c, http.StatusTooManyRequests, "Too many requests, please slow down")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
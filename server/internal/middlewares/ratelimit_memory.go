@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const rateLimitSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time
+}
+
+// MemoryRateLimitStore keeps buckets in this process. Full buckets are
+// dropped periodically, since a missing bucket behaves the same as a full one.
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	burst := float64(policy.Burst)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, updated: now}
+		s.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updated).Seconds()
+	bucket.tokens = math.Min(burst, bucket.tokens+elapsed*policy.Rate)
+	bucket.updated = now
+
+	result := RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / policy.Rate)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsToDuration((burst - bucket.tokens) / policy.Rate)
+	bucket.fullAt = now.Add(result.Reset)
+
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	return result, nil
+}
+
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/config"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+// RequireTransactions answers 503 on routes that write in a transaction
+// when MongoDB runs standalone, instead of failing half way through. The
+// topology is checked on first use and remembered once known.
+func RequireTransactions(db *mongo.Database) gin.HandlerFunc {
+	var (
+		mutex     sync.Mutex
+		checked   bool
+		supported bool
+	)
+	return func(c *gin.Context) {
+		mutex.Lock()
+		if !checked {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+			ok, err := config.SupportsTransactions(ctx, db.Client())
+			cancel()
+			if err != nil {
+				log.Printf("Failed to check MongoDB transaction support: %v", err)
+			} else {
+				checked, supported = true, ok
+			}
+		}
+		available := supported
+		mutex.Unlock()
+
+		if !available {
+			utils.RespondError(c, http.StatusServiceUnavailable, "This action needs MongoDB running as a replica set")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// backfillLegacyFields rewrites documents created before the current
+// models: managers with the single foodcourt_id get it appended to
+// foodcourt_ids, and users without roles get their primary role. The
+// models still read both shapes, so this only saves the fallbacks and lets
+// the validators require the new fields.
+func backfillLegacyFields(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("managers").UpdateMany(ctx,
+		bson.M{"foodcourt_id": bson.M{"$type": "objectId"}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"foodcourt_ids": bson.M{"$let": bson.M{
+				"vars": bson.M{"ids": bson.M{"$ifNull": bson.A{"$foodcourt_ids", bson.A{}}}},
+				"in": bson.M{"$cond": bson.A{
+					bson.M{"$in": bson.A{"$foodcourt_id", "$$ids"}},
+					"$$ids",
+					bson.M{"$concatArrays": bson.A{"$$ids", bson.A{"$foodcourt_id"}}},
+				}},
+			}}}}},
+			{{Key: "$unset", Value: "foodcourt_id"}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("folding manager courts: %w", err)
+	}
+
+	_, err = db.Collection("users").UpdateMany(ctx,
+		bson.M{"roles": bson.M{"$exists": false}, "role": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"roles": bson.A{"$role"}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("backfilling user roles: %w", err)
+	}
+	return nil
+}
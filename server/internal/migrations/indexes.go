@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func ascending(fields ...string) bson.D {
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+	return keys
+}
+
+func unique(keys bson.D) mongo.IndexModel {
+	return mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)}
+}
+
+func lookup(keys bson.D) mongo.IndexModel {
+	return mongo.IndexModel{Keys: keys}
+}
+
+// newestFirst indexes field for queries that also sort by newest createdAt.
+func newestFirst(field string) mongo.IndexModel {
+	return lookup(bson.D{{Key: field, Value: 1}, {Key: "createdAt", Value: -1}})
+}
+
+// createIndexes enforces the uniqueness the handlers used to check by hand
+// and indexes the fields lookups filter on. Trashed documents keep their
+// keys, so a trashed account still holds its email until it is purged.
+// Building a unique index fails if the collection already has duplicates;
+// those have to be cleaned up before the migration can apply.
+func createIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		"users": {
+			unique(ascending("email")),
+			lookup(ascending("deletedAt")),
+		},
+		"vendors": {
+			// Not unique: a user's trashed shops stay next to the live one.
+			lookup(ascending("user_id")),
+			lookup(ascending("deletedAt")),
+		},
+		"items": {
+			lookup(ascending("vendor_id")),
+			lookup(ascending("deletedAt")),
+		},
+		"foodcourts": {
+			lookup(ascending("vendor_ids")),
+			lookup(ascending("deletedAt")),
+		},
+		"itemfoodcourts": {
+			unique(ascending("foodcourt_id", "item_id")),
+			lookup(ascending("item_id")),
+		},
+		"managers": {
+			unique(ascending("user_id", "vendor_id")),
+			lookup(ascending("vendor_id")),
+		},
+		"manager_invites": {
+			lookup(ascending("vendor_id", "status")),
+			lookup(ascending("email", "status")),
+			lookup(ascending("user_id")),
+		},
+		"orders": {
+			newestFirst("user_id"),
+			newestFirst("vendor_id"),
+			lookup(ascending("foodcourt_id", "status")),
+		},
+		"refresh_sessions": {
+			lookup(ascending("user_id")),
+		},
+		"auth_tokens": {
+			lookup(ascending("tokenHash")),
+		},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(indexes)) {
+		if _, err := db.Collection(name).Indexes().CreateMany(ctx, indexes[name]); err != nil {
+			return fmt.Errorf("indexing %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package migrations brings the database schema up to date: indexes,
+// collection validators and data backfills. Each migration has a version
+// and runs once; applied versions are recorded in the schema_migrations
+// collection.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "schema_migrations"
+
+// Migration is one schema change. Up must be safe to run again: instances
+// booting together can both apply a version before either records it.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// All lists every migration in version order.
+var All = []Migration{
+	{1, "Create unique and lookup indexes", createIndexes},
+	{2, "Backfill manager courts and user roles", backfillLegacyFields},
+	{3, "Add JSON schema validators", addValidators},
+}
+
+// Pending returns the migrations in All that have not been applied to db.
+func Pending(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	cursor, err := db.Collection(collectionName).Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var applied []record
+	if err := cursor.All(ctx, &applied); err != nil {
+		return nil, err
+	}
+	done := map[int]bool{}
+	for _, r := range applied {
+		done[r.Version] = true
+	}
+
+	pending := []Migration{}
+	for _, migration := range All {
+		if !done[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies the pending migrations in order and returns how many it
+// applied. It stops at the first failure, so later versions never run on
+// top of a schema they do not expect.
+func Run(ctx context.Context, db *mongo.Database) (int, error) {
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		return 0, fmt.Errorf("reading applied migrations: %w", err)
+	}
+
+	for i, migration := range pending {
+		if err := migration.Up(ctx, db); err != nil {
+			return i, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		_, err := db.Collection(collectionName).InsertOne(ctx, record{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return i, fmt.Errorf("recording migration %d: %w", migration.Version, err)
+		}
+		log.Printf("Applied migration %d: %s", migration.Version, migration.Description)
+	}
+	return len(pending), nil
+}
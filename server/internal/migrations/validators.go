@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+)
+
+// schema builds a $jsonSchema that requires fields and checks the types of
+// properties. Fields it does not mention are left alone.
+func schema(required []string, properties bson.M) bson.M {
+	return bson.M{"$jsonSchema": bson.M{
+		"bsonType":   "object",
+		"required":   required,
+		"properties": properties,
+	}}
+}
+
+func typed(bsonType string) bson.M {
+	return bson.M{"bsonType": bsonType}
+}
+
+func arrayOf(items bson.M) bson.M {
+	return bson.M{"bsonType": "array", "items": items}
+}
+
+func oneOf(values ...string) bson.M {
+	return bson.M{"bsonType": "string", "enum": values}
+}
+
+// addValidators rejects writes that break the shape the models decode. The
+// level is moderate, so documents that were already invalid can still be
+// updated; they are checked again once they pass.
+func addValidators(ctx context.Context, db *mongo.Database) error {
+	roles := oneOf(permissions.RoleAdmin, permissions.RoleVendor, permissions.RoleManager, permissions.RoleUser)
+
+	validators := map[string]bson.M{
+		"users": schema([]string{"name", "email", "password", "role"}, bson.M{
+			"name":     typed("string"),
+			"email":    typed("string"),
+			"password": typed("string"),
+			"role":     roles,
+			"roles":    arrayOf(roles),
+		}),
+		"vendors": schema([]string{"user_id", "shopName"}, bson.M{
+			"user_id":  typed("objectId"),
+			"shopName": typed("string"),
+			"gst":      typed("string"),
+		}),
+		"items": schema([]string{"name", "vendor_id", "basePrice", "category"}, bson.M{
+			"name":      typed("string"),
+			"vendor_id": typed("objectId"),
+			"basePrice": typed("number"),
+			"category":  typed("string"),
+		}),
+		"foodcourts": schema([]string{"name", "location"}, bson.M{
+			"name":       typed("string"),
+			"location":   typed("string"),
+			"vendor_ids": arrayOf(typed("objectId")),
+		}),
+		"itemfoodcourts": schema([]string{"item_id", "foodcourt_id", "status"}, bson.M{
+			"item_id":      typed("objectId"),
+			"foodcourt_id": typed("objectId"),
+			"status":       typed("string"),
+		}),
+		"managers": schema([]string{"user_id", "vendor_id", "foodcourt_ids"}, bson.M{
+			"user_id":       typed("objectId"),
+			"vendor_id":     typed("objectId"),
+			"foodcourt_ids": arrayOf(typed("objectId")),
+		}),
+		"orders": schema([]string{"user_id", "vendor_id", "foodcourt_id", "items", "status"}, bson.M{
+			"user_id":      typed("objectId"),
+			"vendor_id":    typed("objectId"),
+			"foodcourt_id": typed("objectId"),
+			"items":        typed("array"),
+			"status": oneOf(
+				models.OrderStatusPlaced, models.OrderStatusAccepted, models.OrderStatusPreparing,
+				models.OrderStatusReady, models.OrderStatusPickedUp, models.OrderStatusRejected,
+				models.OrderStatusCancelled,
+			),
+		}),
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(validators)) {
+		if err := setValidator(ctx, db, name, validators[name]); err != nil {
+			return fmt.Errorf("validating %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setValidator creates the collection with validator, or replaces the
+// validator of an existing one.
+func setValidator(ctx context.Context, db *mongo.Database, name string, validator bson.M) error {
+	err := db.CreateCollection(ctx, name,
+		options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate"))
+	var cmdErr mongo.CommandError
+	if err == nil || !(errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists") {
+		return err
+	}
+
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: name},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditLog struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Action       string              `bson:"action" json:"action"`
+	ActorID      *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	TargetUserID *primitive.ObjectID `bson:"target_user_id,omitempty" json:"target_user_id,omitempty"`
+	Email        string              `bson:"email,omitempty" json:"email,omitempty"`
+	IP           string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	Details      map[string]any      `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+const (
+	AuditAccountLocked   = "account_locked"
+	AuditIPLocked        = "ip_locked"
+	AuditAccountUnlocked = "account_unlocked"
+)
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuthToken is a single-use link token mailed to a user. Only the SHA-256
+// of the token is stored, so a database leak cannot be replayed.
+type AuthToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Purpose   string             `bson:"purpose" json:"purpose"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	Email     string             `bson:"email" json:"email"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+const (
+	AuthTokenPasswordReset     = "password_reset"
+	AuthTokenEmailVerification = "email_verification"
+)
+
+var AuthTokenTTL = map[string]time.Duration{
+	AuthTokenPasswordReset:     time.Hour,
+	AuthTokenEmailVerification: 48 * time.Hour,
+}
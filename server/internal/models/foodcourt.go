@@ -12,10 +12,41 @@ type FoodCourt struct {
 	Location  string               `bson:"location" json:"location" validate:"required"`
 	AdminID   primitive.ObjectID   `bson:"admin_id" json:"admin_id" validate:"required"`
 	VendorIDs []primitive.ObjectID `bson:"vendor_ids,omitempty" json:"vendor_ids"`
-	Timings   string               `bson:"timings,omitempty" json:"timings,omitempty" validate:"omitempty,max=100"`
+	Schedule  *OpeningSchedule     `bson:"schedule,omitempty" json:"schedule,omitempty"`                                   // When set, the scheduler drives IsOpen
+	Timings   string               `bson:"timings,omitempty" json:"timings,omitempty" validate:"omitempty,max=100"`        // Summary of Schedule when set
+	Timezone  string               `bson:"timezone,omitempty" json:"timezone,omitempty" validate:"omitempty,timezone"`     // IANA name, e.g. Asia/Kolkata
+	MealSlots []MealSlot           `bson:"mealSlots,omitempty" json:"mealSlots,omitempty" validate:"omitempty,max=4,dive"` // Overrides DefaultMealSlots per slot
 	IsOpen    bool                 `bson:"isOpen" json:"isOpen"`
-	Weekends  bool                 `bson:"weekends" json:"weekends"`
-	Weekdays  bool                 `bson:"weekdays" json:"weekdays"`
+	Weekends  bool                 `bson:"weekends" json:"weekends"` // Derived from Schedule when set
+	Weekdays  bool                 `bson:"weekdays" json:"weekdays"` // Derived from Schedule when set
 	CreatedAt time.Time            `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time            `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt *time.Time           `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy *primitive.ObjectID  `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+const DefaultFoodCourtTimezone = "Asia/Kolkata"
+
+// FoodCourtLocation resolves a food court's timezone, falling back to the
+// default for courts created before the field existed.
+func FoodCourtLocation(timezone string) *time.Location {
+	if timezone == "" {
+		timezone = DefaultFoodCourtTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(DefaultFoodCourtTimezone)
+	}
+	return loc
+}
+
+// ApplySchedule refreshes the fields derived from Schedule.
+func (fc *FoodCourt) ApplySchedule(now time.Time) {
+	if fc.Schedule == nil {
+		return
+	}
+	fc.Timings = fc.Schedule.Summary()
+	fc.Weekdays = fc.Schedule.OpenOnWeekdays()
+	fc.Weekends = fc.Schedule.OpenOnWeekends()
+	fc.IsOpen = fc.Schedule.IsOpenAt(now.In(FoodCourtLocation(fc.Timezone)))
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,6 +16,52 @@ type ItemFoodCourt struct {
 	Price       *float64           `bson:"price,omitempty" json:"price,omitempty"` // Optional: different pricing per location
 	IsActive    bool               `bson:"isActive" json:"isActive"`               // Can disable item in specific food court
 	TimeSlot    string             `bson:"timeSlot" json:"timeSlot" validate:"required,oneof=breakfast lunch snacks dinner"`
+	TimeSlots   []string           `bson:"timeSlots,omitempty" json:"timeSlots,omitempty" validate:"omitempty,max=4,dive,oneof=breakfast lunch snacks dinner"` // Every slot the item is served in; includes TimeSlot
 	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
+
+var MenuTimeSlots = []string{"breakfast", "lunch", "snacks", "dinner"}
+
+// NormalizeTimeSlots combines the legacy single TimeSlot with the optional
+// TimeSlots list. The primary slot is always part of the returned list,
+// which is deduplicated and kept in menu order.
+func NormalizeTimeSlots(primary string, slots []string) (string, []string, error) {
+	if primary == "" && len(slots) == 0 {
+		return "", nil, errors.New("at least one time slot is required")
+	}
+	if primary == "" {
+		primary = slots[0]
+	}
+
+	wanted := map[string]bool{primary: true}
+	for _, slot := range slots {
+		wanted[slot] = true
+	}
+
+	normalized := make([]string, 0, len(wanted))
+	for _, slot := range MenuTimeSlots {
+		if wanted[slot] {
+			normalized = append(normalized, slot)
+			delete(wanted, slot)
+		}
+	}
+	for slot := range wanted {
+		return "", nil, fmt.Errorf("unknown time slot %q", slot)
+	}
+
+	return primary, normalized, nil
+}
+
+// UpdatedTimeSlots applies an update to the listing's time slots. A slots
+// list replaces the current ones; a primary slot sent on its own joins the
+// slots the listing is already served in.
+func (l ItemFoodCourt) UpdatedTimeSlots(primary string, slots []string) (string, []string, error) {
+	if slots == nil {
+		slots = l.TimeSlots
+		if len(slots) == 0 && l.TimeSlot != "" {
+			slots = []string{l.TimeSlot}
+		}
+	}
+	return NormalizeTimeSlots(primary, slots)
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// LoginThrottle counts recent failed logins for one email or one client IP.
+// The ID is the key, e.g. "email:jane@example.com" or "ip:10.0.0.7".
+type LoginThrottle struct {
+	ID            string     `bson:"_id" json:"id"`
+	Failures      int        `bson:"failures" json:"failures"`
+	LastFailureAt time.Time  `bson:"lastFailureAt" json:"lastFailureAt"`
+	LockedUntil   *time.Time `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+}
+
+// LoginThrottlePolicy decides how a key slows down and when it locks.
+type LoginThrottlePolicy struct {
+	FreeFailures int           // failures allowed before any delay
+	MaxBackoff   time.Duration // cap on the delay between attempts
+	LockAfter    int           // failures that lock the key
+	LockFor      time.Duration
+	Window       time.Duration // quiet period after which failures are forgotten
+}
+
+var (
+	// Accounts lock quickly; a real user rarely fails ten times in a row.
+	EmailLoginPolicy = LoginThrottlePolicy{
+		FreeFailures: 3,
+		MaxBackoff:   5 * time.Minute,
+		LockAfter:    10,
+		LockFor:      30 * time.Minute,
+		Window:       time.Hour,
+	}
+	// A campus or office often shares one IP, so it gets more headroom.
+	IPLoginPolicy = LoginThrottlePolicy{
+		FreeFailures: 20,
+		MaxBackoff:   time.Minute,
+		LockAfter:    100,
+		LockFor:      15 * time.Minute,
+		Window:       time.Hour,
+	}
+)
+
+// RetryAfter returns how long the key must wait before its next attempt.
+func (p LoginThrottlePolicy) RetryAfter(throttle LoginThrottle, now time.Time) time.Duration {
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+		return throttle.LockedUntil.Sub(now)
+	}
+	if now.Sub(throttle.LastFailureAt) >= p.Window || throttle.Failures <= p.FreeFailures {
+		return 0
+	}
+
+	backoff := p.MaxBackoff
+	if shift := throttle.Failures - p.FreeFailures - 1; shift < 16 {
+		if delay := time.Second << shift; delay < backoff {
+			backoff = delay
+		}
+	}
+	if wait := throttle.LastFailureAt.Add(backoff).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginThrottlePolicyRetryAfter(t *testing.T) {
+	now := time.Now()
+	failed := func(failures int, ago time.Duration) LoginThrottle {
+		return LoginThrottle{Failures: failures, LastFailureAt: now.Add(-ago)}
+	}
+	locked := func(throttle LoginThrottle, until time.Duration) LoginThrottle {
+		at := now.Add(until)
+		throttle.LockedUntil = &at
+		return throttle
+	}
+
+	tests := []struct {
+		name     string
+		policy   LoginThrottlePolicy
+		throttle LoginThrottle
+		want     time.Duration
+	}{
+		{"no failures", EmailLoginPolicy, LoginThrottle{}, 0},
+		{"free failures", EmailLoginPolicy, failed(3, 0), 0},
+		{"first delayed failure", EmailLoginPolicy, failed(4, 0), time.Second},
+		{"backoff doubles", EmailLoginPolicy, failed(6, 0), 4 * time.Second},
+		{"backoff partly waited", EmailLoginPolicy, failed(5, 500*time.Millisecond), 1500 * time.Millisecond},
+		{"backoff fully waited", EmailLoginPolicy, failed(5, 2*time.Second), 0},
+		{"below the cap", EmailLoginPolicy, failed(12, 0), 256 * time.Second},
+		{"capped", EmailLoginPolicy, failed(13, 0), 5 * time.Minute},
+		{"huge count stays capped", EmailLoginPolicy, failed(500, 0), 5 * time.Minute},
+		{"forgotten after the window", EmailLoginPolicy, failed(9, time.Hour), 0},
+		{"locked", EmailLoginPolicy, locked(failed(10, 0), 30*time.Minute), 30 * time.Minute},
+		{"lock outlasts the window", EmailLoginPolicy, locked(failed(10, 2*time.Hour), time.Minute), time.Minute},
+		{"lock over, backoff pending", EmailLoginPolicy, locked(failed(10, 30*time.Second), -time.Second), 34 * time.Second},
+		{"lock over, backoff waited", EmailLoginPolicy, locked(failed(10, 31*time.Minute), -time.Minute), 0},
+		{"IP gets more headroom", IPLoginPolicy, failed(20, 0), 0},
+		{"IP cap", IPLoginPolicy, failed(99, 0), time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.RetryAfter(tt.throttle, now); got != tt.want {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,36 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Manager struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id" validate:"required"`
-	VendorID    primitive.ObjectID `bson:"vendor_id" json:"vendor_id" validate:"required"`
-	FoodCourtID primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id" validate:"required"`
-	ContactNo   string             `bson:"contact_no" json:"contact_no" validate:"required,e164"`
-	IsActive    bool               `bson:"isActive" json:"isActive"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID       primitive.ObjectID   `bson:"user_id" json:"user_id" validate:"required"`
+	VendorID     primitive.ObjectID   `bson:"vendor_id" json:"vendor_id" validate:"required"`
+	FoodCourtIDs []primitive.ObjectID `bson:"foodcourt_ids" json:"foodcourt_ids" validate:"required,min=1"`
+	FoodCourtID  primitive.ObjectID   `bson:"foodcourt_id,omitempty" json:"-"` // Single court of records written before FoodCourtIDs; read through Courts
+	ContactNo    string               `bson:"contact_no" json:"contact_no" validate:"required,e164"`
+	IsActive     bool                 `bson:"isActive" json:"isActive"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Courts lists the food courts the manager runs, in assignment order,
+// folding in the legacy single court.
+func (m Manager) Courts() []primitive.ObjectID {
+	courts := []primitive.ObjectID{}
+	for _, id := range append(append([]primitive.ObjectID{}, m.FoodCourtIDs...), m.FoodCourtID) {
+		if !id.IsZero() && !slices.Contains(courts, id) {
+			courts = append(courts, id)
+		}
+	}
+	return courts
+}
+
+func (m Manager) RunsCourt(foodCourtID primitive.ObjectID) bool {
+	return slices.Contains(m.Courts(), foodCourtID)
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ManagerInvite asks the owner of an email address to manage for a vendor.
+// Nothing changes for the invitee until they accept it.
+type ManagerInvite struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	VendorID     primitive.ObjectID   `bson:"vendor_id" json:"vendor_id"`
+	InvitedBy    primitive.ObjectID   `bson:"invited_by" json:"invited_by"`
+	Email        string               `bson:"email" json:"email"`                         // Lowercased
+	UserID       *primitive.ObjectID  `bson:"user_id,omitempty" json:"user_id,omitempty"` // Set once the email belongs to an account
+	FoodCourtIDs []primitive.ObjectID `bson:"foodcourt_ids" json:"foodcourt_ids"`
+	ContactNo    string               `bson:"contact_no" json:"contact_no"`
+	Status       string               `bson:"status" json:"status"`
+	ExpiresAt    time.Time            `bson:"expiresAt" json:"expiresAt"`
+	RespondedAt  *time.Time           `bson:"respondedAt,omitempty" json:"respondedAt,omitempty"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+const (
+	ManagerInvitePending  = "pending"
+	ManagerInviteAccepted = "accepted"
+	ManagerInviteDeclined = "declined"
+	ManagerInviteRevoked  = "revoked"
+	ManagerInviteExpired  = "expired" // Never stored; see CurrentStatus
+)
+
+const ManagerInviteTTL = 7 * 24 * time.Hour
+
+// CurrentStatus is Status, except that a pending invite past its expiry
+// reads as expired.
+func (i ManagerInvite) CurrentStatus(now time.Time) string {
+	if i.Status == ManagerInvitePending && !now.Before(i.ExpiresAt) {
+		return ManagerInviteExpired
+	}
+	return i.Status
+}
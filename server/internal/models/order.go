@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderItem struct {
+	ItemFoodCourtID primitive.ObjectID `bson:"itemfoodcourt_id" json:"itemfoodcourt_id"`
+	ItemID          primitive.ObjectID `bson:"item_id" json:"item_id"`
+	Name            string             `bson:"name" json:"name"`
+	Quantity        int                `bson:"quantity" json:"quantity" validate:"required,gt=0,lte=50"`
+	UnitPrice       float64            `bson:"unitPrice" json:"unitPrice"` // Snapshot at order time
+	LineTotal       float64            `bson:"lineTotal" json:"lineTotal"`
+}
+
+type OrderStatusChange struct {
+	From      string             `bson:"from,omitempty" json:"from,omitempty"`
+	To        string             `bson:"to" json:"to"`
+	ChangedBy primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	Role      string             `bson:"role" json:"role"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	At        time.Time          `bson:"at" json:"at"`
+}
+
+// OrderTokenCounter holds the last token handed out by a food court on a
+// given local day. The _id is "<foodcourt_id>:<YYYY-MM-DD>", so the count
+// starts over at each court's midnight without any cleanup job.
+type OrderTokenCounter struct {
+	ID          string             `bson:"_id" json:"id"`
+	FoodCourtID primitive.ObjectID `bson:"foodcourt_id" json:"foodcourt_id"`
+	Date        string             `bson:"date" json:"date"`
+	Seq         int                `bson:"seq" json:"seq"`
+}
+
+type Order struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	GroupID     primitive.ObjectID  `bson:"group_id" json:"group_id"` // Shared by all vendor orders of one checkout
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id" validate:"required"`
+	VendorID    primitive.ObjectID  `bson:"vendor_id" json:"vendor_id" validate:"required"`
+	FoodCourtID primitive.ObjectID  `bson:"foodcourt_id" json:"foodcourt_id" validate:"required"`
+	Items       []OrderItem         `bson:"items" json:"items" validate:"required,min=1,dive"`
+	TotalAmount float64             `bson:"totalAmount" json:"totalAmount"`
+	Status      string              `bson:"status" json:"status" validate:"required,oneof=placed accepted preparing ready picked_up rejected cancelled"`
+	Note        string              `bson:"note,omitempty" json:"note,omitempty" validate:"omitempty,max=300"`
+	Token       int                 `bson:"token,omitempty" json:"token,omitempty"`         // Counter number, issued on acceptance
+	TokenDate   string              `bson:"tokenDate,omitempty" json:"tokenDate,omitempty"` // Court-local YYYY-MM-DD the token belongs to
+	History     []OrderStatusChange `bson:"history" json:"history"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusAccepted  = "accepted"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusPickedUp  = "picked_up"
+	OrderStatusRejected  = "rejected"
+	OrderStatusCancelled = "cancelled"
+)
+
+// OrderTransitions lists the statuses each status may move to. Kitchen staff
+// (vendors and their managers) drive the happy path; customers may only
+// cancel an order the kitchen has not accepted yet.
+var OrderTransitions = map[string][]string{
+	OrderStatusPlaced:    {OrderStatusAccepted, OrderStatusRejected, OrderStatusCancelled},
+	OrderStatusAccepted:  {OrderStatusPreparing, OrderStatusCancelled},
+	OrderStatusPreparing: {OrderStatusReady, OrderStatusCancelled},
+	OrderStatusReady:     {OrderStatusPickedUp},
+}
+
+var customerOrderTransitions = map[string][]string{
+	OrderStatusPlaced: {OrderStatusCancelled},
+}
+
+func CanTransitionOrder(from, to string, byCustomer bool) bool {
+	transitions := OrderTransitions
+	if byCustomer {
+		transitions = customerOrderTransitions
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
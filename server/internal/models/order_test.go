@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   string
+		byCustomer bool
+		want       bool
+	}{
+		{"kitchen accepts", OrderStatusPlaced, OrderStatusAccepted, false, true},
+		{"kitchen rejects", OrderStatusPlaced, OrderStatusRejected, false, true},
+		{"kitchen starts preparing", OrderStatusAccepted, OrderStatusPreparing, false, true},
+		{"kitchen marks ready", OrderStatusPreparing, OrderStatusReady, false, true},
+		{"kitchen hands over", OrderStatusReady, OrderStatusPickedUp, false, true},
+		{"kitchen cancels while preparing", OrderStatusPreparing, OrderStatusCancelled, false, true},
+		{"kitchen skips preparing", OrderStatusAccepted, OrderStatusReady, false, false},
+		{"kitchen rejects accepted order", OrderStatusAccepted, OrderStatusRejected, false, false},
+		{"kitchen cancels ready order", OrderStatusReady, OrderStatusCancelled, false, false},
+		{"picked up is final", OrderStatusPickedUp, OrderStatusReady, false, false},
+		{"rejected is final", OrderStatusRejected, OrderStatusAccepted, false, false},
+		{"cancelled is final", OrderStatusCancelled, OrderStatusPlaced, false, false},
+		{"same status", OrderStatusPlaced, OrderStatusPlaced, false, false},
+		{"unknown status", "lost", OrderStatusCancelled, false, false},
+		{"customer cancels placed order", OrderStatusPlaced, OrderStatusCancelled, true, true},
+		{"customer cancels accepted order", OrderStatusAccepted, OrderStatusCancelled, true, false},
+		{"customer accepts own order", OrderStatusPlaced, OrderStatusAccepted, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransitionOrder(tt.from, tt.to, tt.byCustomer); got != tt.want {
+				t.Errorf("CanTransitionOrder(%q, %q, %t) = %t, want %t", tt.from, tt.to, tt.byCustomer, got, tt.want)
+			}
+		})
+	}
+}
+
+// Every status an order can move to must itself be a known status, and the
+// final ones must lead nowhere.
+func TestOrderTransitionsAreClosed(t *testing.T) {
+	known := map[string]bool{
+		OrderStatusPlaced:    true,
+		OrderStatusAccepted:  true,
+		OrderStatusPreparing: true,
+		OrderStatusReady:     true,
+		OrderStatusPickedUp:  true,
+		OrderStatusRejected:  true,
+		OrderStatusCancelled: true,
+	}
+	for from, next := range OrderTransitions {
+		if !known[from] {
+			t.Errorf("unknown status %q has transitions", from)
+		}
+		for _, to := range next {
+			if !known[to] {
+				t.Errorf("%q moves to unknown status %q", from, to)
+			}
+		}
+	}
+	for _, final := range []string{OrderStatusPickedUp, OrderStatusRejected, OrderStatusCancelled} {
+		if len(OrderTransitions[final]) > 0 {
+			t.Errorf("final status %q has transitions %v", final, OrderTransitions[final])
+		}
+	}
+}
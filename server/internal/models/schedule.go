@@ -0,0 +1,239 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// OpeningInterval is a same-day span in the court's local time, "HH:MM".
+// Close is exclusive; a court open past midnight uses two intervals.
+type OpeningInterval struct {
+	Open  string `bson:"open" json:"open" validate:"required,datetime=15:04"`
+	Close string `bson:"close" json:"close" validate:"required,datetime=15:04"`
+}
+
+// ScheduleException overrides the weekly hours on one local date, e.g. a
+// holiday (Closed) or shorter hours during exams.
+type ScheduleException struct {
+	Date      string            `bson:"date" json:"date" validate:"required,datetime=2006-01-02"`
+	Closed    bool              `bson:"closed" json:"closed"`
+	Intervals []OpeningInterval `bson:"intervals,omitempty" json:"intervals,omitempty" validate:"omitempty,max=6,dive"`
+	Note      string            `bson:"note,omitempty" json:"note,omitempty" validate:"omitempty,max=100"`
+}
+
+type OpeningSchedule struct {
+	Weekly     map[string][]OpeningInterval `bson:"weekly" json:"weekly" validate:"required,dive,keys,oneof=monday tuesday wednesday thursday friday saturday sunday,endkeys,max=6,dive"`
+	Exceptions []ScheduleException          `bson:"exceptions,omitempty" json:"exceptions,omitempty" validate:"omitempty,max=366,dive"`
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func validateIntervals(intervals []OpeningInterval) error {
+	type span struct{ open, close int }
+	spans := make([]span, 0, len(intervals))
+	for _, interval := range intervals {
+		open, err := parseClock(interval.Open)
+		if err != nil {
+			return err
+		}
+		close, err := parseClock(interval.Close)
+		if err != nil {
+			return err
+		}
+		if close <= open {
+			return fmt.Errorf("interval %s-%s must close after it opens", interval.Open, interval.Close)
+		}
+		spans = append(spans, span{open, close})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].open < spans[j].open })
+	for i := 1; i < len(spans); i++ {
+		if spans[i].open < spans[i-1].close {
+			return errors.New("intervals must not overlap")
+		}
+	}
+	return nil
+}
+
+// Validate checks what struct tags cannot: interval ordering, overlaps and
+// duplicate exception dates.
+func (s OpeningSchedule) Validate() error {
+	for day, intervals := range s.Weekly {
+		if err := validateIntervals(intervals); err != nil {
+			return fmt.Errorf("%s: %w", day, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, exception := range s.Exceptions {
+		if seen[exception.Date] {
+			return fmt.Errorf("duplicate exception for %s", exception.Date)
+		}
+		seen[exception.Date] = true
+		if exception.Closed && len(exception.Intervals) > 0 {
+			return fmt.Errorf("%s: a closed day cannot have intervals", exception.Date)
+		}
+		if err := validateIntervals(exception.Intervals); err != nil {
+			return fmt.Errorf("%s: %w", exception.Date, err)
+		}
+	}
+	return nil
+}
+
+// IsOpenAt reports whether the schedule has the court open at t, which must
+// already be in the court's timezone.
+func (s OpeningSchedule) IsOpenAt(t time.Time) bool {
+	intervals := s.Weekly[strings.ToLower(t.Weekday().String())]
+
+	date := t.Format("2006-01-02")
+	for _, exception := range s.Exceptions {
+		if exception.Date == date {
+			if exception.Closed {
+				return false
+			}
+			intervals = exception.Intervals
+			break
+		}
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	for _, interval := range intervals {
+		open, err := parseClock(interval.Open)
+		if err != nil {
+			continue
+		}
+		close, err := parseClock(interval.Close)
+		if err != nil {
+			continue
+		}
+		if minute >= open && minute < close {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary renders the weekly hours as short text, e.g.
+// "Mon-Fri 08:00-20:00, Sat 09:00-14:00". It fills the legacy Timings field.
+func (s OpeningSchedule) Summary() string {
+	describe := func(day string) string {
+		var parts []string
+		for _, interval := range s.Weekly[day] {
+			parts = append(parts, interval.Open+"-"+interval.Close)
+		}
+		return strings.Join(parts, " & ")
+	}
+	short := func(day string) string {
+		return strings.ToUpper(day[:1]) + day[1:3]
+	}
+
+	var groups []string
+	for i := 0; i < len(Weekdays); {
+		hours := describe(Weekdays[i])
+		j := i
+		for j+1 < len(Weekdays) && describe(Weekdays[j+1]) == hours {
+			j++
+		}
+		if hours != "" {
+			days := short(Weekdays[i])
+			if j > i {
+				days += "-" + short(Weekdays[j])
+			}
+			groups = append(groups, days+" "+hours)
+		}
+		i = j + 1
+	}
+
+	if len(groups) == 0 {
+		return "Closed"
+	}
+	return strings.Join(groups, ", ")
+}
+
+// OpenOnWeekdays and OpenOnWeekends fill the legacy Weekdays/Weekends flags.
+func (s OpeningSchedule) OpenOnWeekdays() bool {
+	for _, day := range Weekdays[:5] {
+		if len(s.Weekly[day]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s OpeningSchedule) OpenOnWeekends() bool {
+	return len(s.Weekly["saturday"]) > 0 || len(s.Weekly["sunday"]) > 0
+}
+
+// MealSlot is the clock range during which a food court serves a menu time
+// slot (breakfast, lunch, snacks, dinner).
+type MealSlot struct {
+	Slot  string `bson:"slot" json:"slot" validate:"required,oneof=breakfast lunch snacks dinner"`
+	Start string `bson:"start" json:"start" validate:"required,datetime=15:04"`
+	End   string `bson:"end" json:"end" validate:"required,datetime=15:04"`
+}
+
+// DefaultMealSlots apply to any slot a food court has not configured.
+var DefaultMealSlots = []MealSlot{
+	{Slot: "breakfast", Start: "07:00", End: "11:00"},
+	{Slot: "lunch", Start: "11:00", End: "15:30"},
+	{Slot: "snacks", Start: "15:30", End: "19:00"},
+	{Slot: "dinner", Start: "19:00", End: "23:00"},
+}
+
+func ValidateMealSlots(slots []MealSlot) error {
+	seen := make(map[string]bool)
+	for _, slot := range slots {
+		if seen[slot.Slot] {
+			return fmt.Errorf("%s is configured twice", slot.Slot)
+		}
+		seen[slot.Slot] = true
+		if err := validateIntervals([]OpeningInterval{{Open: slot.Start, Close: slot.End}}); err != nil {
+			return fmt.Errorf("%s: %w", slot.Slot, err)
+		}
+	}
+	return nil
+}
+
+// ActiveTimeSlots returns the slots whose range contains t, which must be
+// in the court's timezone. Ranges may overlap, so more than one slot can be
+// active at once.
+func ActiveTimeSlots(configured []MealSlot, t time.Time) []string {
+	ranges := make(map[string]MealSlot)
+	for _, slot := range DefaultMealSlots {
+		ranges[slot.Slot] = slot
+	}
+	for _, slot := range configured {
+		ranges[slot.Slot] = slot
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	active := []string{}
+	for _, name := range MenuTimeSlots {
+		slot, ok := ranges[name]
+		if !ok {
+			continue
+		}
+		start, err := parseClock(slot.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(slot.End)
+		if err != nil {
+			continue
+		}
+		if minute >= start && minute < end {
+			active = append(active, name)
+		}
+	}
+	return active
+}
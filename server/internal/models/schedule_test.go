@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestOpeningScheduleIsOpenAt(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := func(value string) time.Time {
+		at, err := time.ParseInLocation("2006-01-02 15:04", value, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+	// utc gives an instant in New York time, for wall clocks that are
+	// skipped or repeated when the clocks change.
+	utc := func(value string) time.Time {
+		at, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at.In(newYork)
+	}
+
+	schedule := OpeningSchedule{
+		Weekly: map[string][]OpeningInterval{
+			"monday":   {{Open: "08:00", Close: "11:00"}, {Open: "12:00", Close: "20:00"}},
+			"friday":   {{Open: "18:00", Close: "23:59"}},
+			"saturday": {{Open: "00:00", Close: "02:00"}},
+			"sunday":   {{Open: "01:00", Close: "03:30"}},
+		},
+		Exceptions: []ScheduleException{
+			{Date: "2026-10-19", Closed: true, Note: "Holiday"},
+			{Date: "2026-10-26", Intervals: []OpeningInterval{{Open: "10:00", Close: "12:00"}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"inside first interval", local("2026-10-12 08:00"), true},
+		{"close is exclusive", local("2026-10-12 11:00"), false},
+		{"between intervals", local("2026-10-12 11:30"), false},
+		{"inside second interval", local("2026-10-12 19:59"), true},
+		{"day without hours", local("2026-10-13 12:00"), false},
+		{"overnight, before midnight", local("2026-10-16 23:30"), true},
+		{"overnight, after midnight", local("2026-10-17 01:30"), true},
+		{"overnight, closed after", local("2026-10-17 02:00"), false},
+		{"closed exception", local("2026-10-19 09:00"), false},
+		{"exception hours replace weekly", local("2026-10-26 11:00"), true},
+		{"weekly hours ignored on exception day", local("2026-10-26 09:00"), false},
+		{"spring forward, before the gap", utc("2026-03-08 06:59"), true},        // 01:59 EST
+		{"spring forward, after the gap", utc("2026-03-08 07:00"), true},         // 03:00 EDT
+		{"spring forward, closes on wall clock", utc("2026-03-08 07:30"), false}, // 03:30 EDT
+		{"fall back, first 01:30", utc("2026-11-01 05:30"), true},                // 01:30 EDT
+		{"fall back, second 01:30", utc("2026-11-01 06:30"), true},               // 01:30 EST
+		{"fall back, closes on wall clock", utc("2026-11-01 08:30"), false},      // 03:30 EST
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.IsOpenAt(tt.at); got != tt.want {
+				t.Errorf("IsOpenAt(%s) = %t, want %t", tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshSession is one login and every refresh token rotated out of it
+// (a token family). Only the token whose jti matches CurrentJTI is usable;
+// presenting an older one means the family leaked, and it is revoked.
+type RefreshSession struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID        primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CurrentJTI    string             `bson:"currentJti" json:"-"`
+	PreviousJTI   string             `bson:"previousJti,omitempty" json:"-"`
+	RotatedAt     *time.Time         `bson:"rotatedAt,omitempty" json:"rotatedAt,omitempty"`
+	UserAgent     string             `bson:"userAgent" json:"userAgent"`
+	IP            string             `bson:"ip" json:"ip"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	LastUsedAt    time.Time          `bson:"lastUsedAt" json:"lastUsedAt"`
+	ExpiresAt     time.Time          `bson:"expiresAt" json:"expiresAt"`
+	RevokedAt     *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedReason string             `bson:"revokedReason,omitempty" json:"revokedReason,omitempty"`
+}
+
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedLogoutAll      = "logout_all"
+	SessionRevokedReuse          = "reuse_detected"
+	SessionRevokedPasswordChange = "password_change"
+	SessionRevokedRoleChange     = "role_change"
+	SessionRevokedUserDeleted    = "user_deleted"
+	SessionRevokedByUser         = "revoked_by_user"
+	SessionRevokedByAdmin        = "revoked_by_admin"
+)
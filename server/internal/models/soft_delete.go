@@ -0,0 +1,23 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Users, vendors, items and food courts are soft-deleted: they get
+// deletedAt and deletedBy and stay in their collection until the trash is
+// purged. Everything that reads them outside the trash must go through the
+// helpers below.
+
+// NotDeleted narrows filter to documents that are not soft-deleted. It
+// modifies filter and returns it.
+func NotDeleted(filter bson.M) bson.M {
+	filter["deletedAt"] = nil
+	return filter
+}
+
+// NotDeletedPipeline is the sub-pipeline that keeps soft-deleted documents
+// out of a $lookup.
+func NotDeletedPipeline() bson.A {
+	return bson.A{bson.M{"$match": bson.M{"deletedAt": nil}}}
+}
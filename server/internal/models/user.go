@@ -4,17 +4,29 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
 )
 
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name      string             `bson:"name" json:"name" validate:"required,min=2,max=50"`
-	Email     string             `bson:"email" json:"email" validate:"required,email"`
-	Password  string             `bson:"password" json:"password" validate:"required,min=6"` // hashed before save
-	Role      string             `bson:"role" json:"role" validate:"required,oneof=admin vendor user manager"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Name          string              `bson:"name" json:"name" validate:"required,min=2,max=50"`
+	Email         string              `bson:"email" json:"email" validate:"required,email"`
+	Password      string              `bson:"password" json:"password" validate:"required,min=6"`                   // hashed before save
+	Role          string              `bson:"role" json:"role" validate:"required,oneof=admin vendor user manager"` // primary role, see permissions.Primary
+	Roles         []string            `bson:"roles,omitempty" json:"roles,omitempty"`
+	EmailVerified bool                `bson:"emailVerified" json:"emailVerified"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time           `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt     *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy     *primitive.ObjectID `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+// EffectiveRoles is every role the user holds. Accounts created before
+// Roles existed only have Role.
+func (u User) EffectiveRoles() []string {
+	return permissions.Normalize(append([]string{u.Role}, u.Roles...))
 }
 
 
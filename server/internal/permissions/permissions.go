@@ -0,0 +1,105 @@
+package permissions
+
+import "slices"
+
+// Permission names one capability, as "<area>:<action>".
+type Permission string
+
+const (
+	ProfileManage   Permission = "profile:manage"   // own profile, password and sessions
+	MenuRead        Permission = "menu:read"        // browse food courts, vendors and items
+	OrdersPlace     Permission = "orders:place"     // place and cancel own orders
+	ShopManage      Permission = "shop:manage"      // vendor shop profile and dashboard
+	MenuWrite       Permission = "menu:write"       // vendor catalogue and food court listings
+	MenuOperate     Permission = "menu:operate"     // listings in the food court a manager runs
+	OrdersFulfil    Permission = "orders:fulfil"    // move orders through the kitchen
+	ManagersManage  Permission = "managers:manage"  // a vendor's managers
+	FoodCourtManage Permission = "foodcourt:manage" // food courts and their vendors
+	UsersManage     Permission = "users:manage"     // accounts, roles, sessions and lockouts
+	AuditRead       Permission = "audit:read"
+	TrashManage     Permission = "trash:manage" // restore soft-deleted records
+)
+
+const (
+	RoleUser    = "user"
+	RoleManager = "manager"
+	RoleVendor  = "vendor"
+	RoleAdmin   = "admin"
+)
+
+// Roles are named bundles of permissions. Every account holds RoleUser, so
+// staff can order food from the same account they work with.
+var Roles = map[string][]Permission{
+	RoleUser:    {ProfileManage, MenuRead, OrdersPlace},
+	RoleManager: {MenuOperate, OrdersFulfil},
+	RoleVendor:  {ShopManage, MenuWrite, ManagersManage, OrdersFulfil},
+	RoleAdmin:   {UsersManage, FoodCourtManage, AuditRead, TrashManage},
+}
+
+// precedence orders roles from most to least privileged; the first one a
+// user holds is their primary role.
+var precedence = []string{RoleAdmin, RoleVendor, RoleManager, RoleUser}
+
+type Set map[Permission]struct{}
+
+func (s Set) Has(permissions ...Permission) bool {
+	for _, permission := range permissions {
+		if _, ok := s[permission]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice lists the permissions in a stable order.
+func (s Set) Slice() []Permission {
+	var list []Permission
+	for _, role := range precedence {
+		for _, permission := range Roles[role] {
+			if _, ok := s[permission]; ok && !slices.Contains(list, permission) {
+				list = append(list, permission)
+			}
+		}
+	}
+	return list
+}
+
+// ForRoles collects the permissions granted by roles. Unknown roles grant
+// nothing.
+func ForRoles(roles []string) Set {
+	set := Set{}
+	for _, role := range Normalize(roles) {
+		for _, permission := range Roles[role] {
+			set[permission] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Normalize drops unknown and duplicate roles, adds RoleUser and sorts by
+// precedence.
+func Normalize(roles []string) []string {
+	normalized := []string{}
+	for _, role := range precedence {
+		if Has(roles, role) {
+			normalized = append(normalized, role)
+		}
+	}
+	return normalized
+}
+
+// Primary is the most privileged of roles. It is what the single role field
+// and the clients' routing use.
+func Primary(roles []string) string {
+	return Normalize(roles)[0]
+}
+
+func IsRole(role string) bool {
+	_, ok := Roles[role]
+	return ok
+}
+
+// Has reports whether roles include role. Every account holds RoleUser.
+func Has(roles []string, role string) bool {
+	return role == RoleUser || slices.Contains(roles, role)
+}
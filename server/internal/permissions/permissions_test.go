@@ -0,0 +1,34 @@
+package permissions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		roles []string
+		want  []string
+	}{
+		{"nil", nil, []string{RoleUser}},
+		{"empty", []string{}, []string{RoleUser}},
+		{"user only", []string{RoleUser}, []string{RoleUser}},
+		{"adds user", []string{RoleVendor}, []string{RoleVendor, RoleUser}},
+		{"sorts by precedence", []string{RoleUser, RoleManager, RoleAdmin, RoleVendor}, []string{RoleAdmin, RoleVendor, RoleManager, RoleUser}},
+		{"drops duplicates", []string{RoleManager, RoleManager, RoleUser, RoleUser}, []string{RoleManager, RoleUser}},
+		{"drops unknown roles", []string{"superuser", RoleManager, ""}, []string{RoleManager, RoleUser}},
+		{"is case sensitive", []string{"Admin"}, []string{RoleUser}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.roles)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.roles, got, tt.want)
+			}
+			if primary := Primary(tt.roles); primary != tt.want[0] {
+				t.Errorf("Primary(%q) = %q, want %q", tt.roles, primary, tt.want[0])
+			}
+		})
+	}
+}
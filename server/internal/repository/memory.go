@@ -0,0 +1,510 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// NewMemory returns empty repositories that keep documents in this process.
+// They follow the MongoDB ones, soft deletes included, and are meant for
+// tests and local tooling.
+func NewMemory() *Repositories {
+	return &Repositories{
+		Users:          &memoryUsers{newMemoryTable(func(u *models.User) *primitive.ObjectID { return &u.ID })},
+		Vendors:        &memoryVendors{newMemoryTable(func(v *models.Vendor) *primitive.ObjectID { return &v.ID })},
+		Items:          &memoryItems{newMemoryTable(func(i *models.Item) *primitive.ObjectID { return &i.ID })},
+		FoodCourts:     &memoryFoodCourts{newMemoryTable(func(f *models.FoodCourt) *primitive.ObjectID { return &f.ID })},
+		ItemFoodCourts: &memoryItemFoodCourts{newMemoryTable(func(l *models.ItemFoodCourt) *primitive.ObjectID { return &l.ID })},
+		Managers:       &memoryManagers{newMemoryTable(func(m *models.Manager) *primitive.ObjectID { return &m.ID })},
+	}
+}
+
+// memoryTable holds one collection in insertion order. Documents are copied
+// in and out, so callers never share them with the table.
+type memoryTable[T any] struct {
+	mutex sync.RWMutex
+	docs  []T
+	id    func(*T) *primitive.ObjectID
+}
+
+func newMemoryTable[T any](id func(*T) *primitive.ObjectID) *memoryTable[T] {
+	return &memoryTable[T]{id: id}
+}
+
+func (t *memoryTable[T]) find(match func(T) bool) (T, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	for _, doc := range t.docs {
+		if match(doc) {
+			return doc, nil
+		}
+	}
+	var zero T
+	return zero, ErrNotFound
+}
+
+func (t *memoryTable[T]) list(match func(T) bool) []T {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	docs := []T{}
+	for _, doc := range t.docs {
+		if match(doc) {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+func (t *memoryTable[T]) count(match func(T) bool) int64 {
+	return int64(len(t.list(match)))
+}
+
+func (t *memoryTable[T]) insert(doc *T) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if id := t.id(doc); id.IsZero() {
+		*id = primitive.NewObjectID()
+	}
+	t.docs = append(t.docs, *doc)
+	return nil
+}
+
+// update applies fields and unset to the first match.
+func (t *memoryTable[T]) update(match func(T) bool, fields Fields, unset ...string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, doc := range t.docs {
+		if match(doc) {
+			updated, err := applyUpdate(doc, fields, unset)
+			if err != nil {
+				return err
+			}
+			t.docs[i] = updated
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// updateAll applies fields to every match.
+func (t *memoryTable[T]) updateAll(match func(T) bool, fields Fields) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	matched := false
+	for i, doc := range t.docs {
+		if !match(doc) {
+			continue
+		}
+		updated, err := applyUpdate(doc, fields, nil)
+		if err != nil {
+			return err
+		}
+		t.docs[i] = updated
+		matched = true
+	}
+	if !matched {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// applyUpdate changes doc the way $set and $unset would, by round-tripping
+// it through BSON.
+func applyUpdate[T any](doc T, fields Fields, unset []string) (T, error) {
+	var updated T
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return updated, err
+	}
+	var values bson.M
+	if err := bson.Unmarshal(raw, &values); err != nil {
+		return updated, err
+	}
+	for field, value := range fields {
+		values[field] = value
+	}
+	for _, field := range unset {
+		delete(values, field)
+	}
+
+	if raw, err = bson.Marshal(values); err != nil {
+		return updated, err
+	}
+	err = bson.Unmarshal(raw, &updated)
+	return updated, err
+}
+
+func (t *memoryTable[T]) delete(match func(T) bool) (T, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, doc := range t.docs {
+		if match(doc) {
+			t.docs = append(t.docs[:i], t.docs[i+1:]...)
+			return doc, nil
+		}
+	}
+	var zero T
+	return zero, ErrNotFound
+}
+
+// pageNewestFirst orders docs by creation, newest first, and cuts out one
+// page. Documents created at the same time keep the newest insert first,
+// as the ObjectID tie-break does in MongoDB.
+func pageNewestFirst[T any](docs []T, createdAt func(T) time.Time, skip, limit int64) []T {
+	slices.Reverse(docs)
+	slices.SortStableFunc(docs, func(a, b T) int { return createdAt(b).Compare(createdAt(a)) })
+	if skip >= int64(len(docs)) {
+		return []T{}
+	}
+	docs = docs[skip:]
+	if limit > 0 && limit < int64(len(docs)) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+// matchPattern compiles a case-insensitive $regex. The empty pattern
+// matches everything.
+func matchPattern(pattern string) (func(string) bool, error) {
+	if pattern == "" {
+		return func(string) bool { return true }, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
+
+func userMatch(query UserQuery) (func(models.User) bool, error) {
+	email, err := matchPattern(query.Email)
+	if err != nil {
+		return nil, err
+	}
+	name, err := matchPattern(query.Name)
+	if err != nil {
+		return nil, err
+	}
+	return func(u models.User) bool {
+		return u.DeletedAt == nil &&
+			(query.IDs == nil || slices.Contains(query.IDs, u.ID)) &&
+			(query.Role == "" || u.Role == query.Role || slices.Contains(u.Roles, query.Role)) &&
+			email(u.Email) && name(u.Name)
+	}, nil
+}
+
+func foodCourtMatch(query FoodCourtQuery) (func(models.FoodCourt) bool, error) {
+	name, err := matchPattern(query.Name)
+	if err != nil {
+		return nil, err
+	}
+	return func(f models.FoodCourt) bool {
+		return f.DeletedAt == nil &&
+			(query.IDs == nil || slices.Contains(query.IDs, f.ID)) &&
+			(query.AdminID.IsZero() || f.AdminID == query.AdminID) &&
+			(!query.OpenOnly || f.IsOpen) &&
+			name(f.Name)
+	}, nil
+}
+
+type memoryUsers struct{ table *memoryTable[models.User] }
+
+func (r *memoryUsers) FindByID(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	return r.table.find(func(u models.User) bool { return u.DeletedAt == nil && u.ID == id })
+}
+
+func (r *memoryUsers) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.table.find(func(u models.User) bool { return u.DeletedAt == nil && u.Email == email })
+}
+
+func (r *memoryUsers) List(ctx context.Context, query UserQuery) ([]models.User, error) {
+	match, err := userMatch(query)
+	if err != nil {
+		return nil, err
+	}
+	return r.table.list(match), nil
+}
+
+func (r *memoryUsers) ListPage(ctx context.Context, query UserQuery, skip, limit int64) ([]models.User, error) {
+	users, err := r.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pageNewestFirst(users, func(u models.User) time.Time { return u.CreatedAt }, skip, limit), nil
+}
+
+func (r *memoryUsers) Count(ctx context.Context, query UserQuery) (int64, error) {
+	users, err := r.List(ctx, query)
+	return int64(len(users)), err
+}
+
+func (r *memoryUsers) EmailInUse(ctx context.Context, email string, except primitive.ObjectID) (bool, error) {
+	return r.table.count(func(u models.User) bool { return u.Email == email && u.ID != except }) > 0, nil
+}
+
+func (r *memoryUsers) Create(ctx context.Context, user *models.User) error {
+	return r.table.insert(user)
+}
+
+func (r *memoryUsers) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return r.table.update(func(u models.User) bool { return u.DeletedAt == nil && u.ID == id }, fields)
+}
+
+func (r *memoryUsers) UpdateWithEmail(ctx context.Context, id primitive.ObjectID, email string, fields Fields) error {
+	return r.table.update(func(u models.User) bool { return u.DeletedAt == nil && u.ID == id && u.Email == email }, fields)
+}
+
+type memoryVendors struct{ table *memoryTable[models.Vendor] }
+
+func (r *memoryVendors) FindByID(ctx context.Context, id primitive.ObjectID) (models.Vendor, error) {
+	return r.table.find(func(v models.Vendor) bool { return v.DeletedAt == nil && v.ID == id })
+}
+
+func (r *memoryVendors) FindByUser(ctx context.Context, userID primitive.ObjectID) (models.Vendor, error) {
+	return r.table.find(func(v models.Vendor) bool { return v.DeletedAt == nil && v.UserID == userID })
+}
+
+func (r *memoryVendors) List(ctx context.Context) ([]models.Vendor, error) {
+	return r.table.list(func(v models.Vendor) bool { return v.DeletedAt == nil }), nil
+}
+
+func (r *memoryVendors) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Vendor, error) {
+	return r.table.list(func(v models.Vendor) bool { return v.DeletedAt == nil && slices.Contains(ids, v.ID) }), nil
+}
+
+func (r *memoryVendors) ListByUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]models.Vendor, error) {
+	return r.table.list(func(v models.Vendor) bool { return v.DeletedAt == nil && slices.Contains(userIDs, v.UserID) }), nil
+}
+
+func (r *memoryVendors) Count(ctx context.Context) (int64, error) {
+	return r.table.count(func(v models.Vendor) bool { return v.DeletedAt == nil }), nil
+}
+
+func (r *memoryVendors) Create(ctx context.Context, vendor *models.Vendor) error {
+	return r.table.insert(vendor)
+}
+
+func (r *memoryVendors) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return r.table.update(func(v models.Vendor) bool { return v.DeletedAt == nil && v.ID == id }, fields)
+}
+
+type memoryItems struct{ table *memoryTable[models.Item] }
+
+func (r *memoryItems) FindByID(ctx context.Context, id primitive.ObjectID) (models.Item, error) {
+	return r.table.find(func(i models.Item) bool { return i.DeletedAt == nil && i.ID == id })
+}
+
+func (r *memoryItems) FindOwned(ctx context.Context, vendorID, id primitive.ObjectID) (models.Item, error) {
+	return r.table.find(r.owned(vendorID, id))
+}
+
+func (r *memoryItems) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Item, error) {
+	return r.table.list(func(i models.Item) bool { return i.DeletedAt == nil && slices.Contains(ids, i.ID) }), nil
+}
+
+func (r *memoryItems) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Item, error) {
+	return r.table.list(r.byVendor(vendorID)), nil
+}
+
+func (r *memoryItems) Count(ctx context.Context) (int64, error) {
+	return r.table.count(func(i models.Item) bool { return i.DeletedAt == nil }), nil
+}
+
+func (r *memoryItems) CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error) {
+	return r.table.count(r.byVendor(vendorID)), nil
+}
+
+func (r *memoryItems) Create(ctx context.Context, item *models.Item) error {
+	return r.table.insert(item)
+}
+
+func (r *memoryItems) UpdateOwned(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields) error {
+	return r.table.update(r.owned(vendorID, id), fields)
+}
+
+func (r *memoryItems) byVendor(vendorID primitive.ObjectID) func(models.Item) bool {
+	return func(i models.Item) bool { return i.DeletedAt == nil && i.VendorID == vendorID }
+}
+
+func (r *memoryItems) owned(vendorID, id primitive.ObjectID) func(models.Item) bool {
+	return func(i models.Item) bool { return i.DeletedAt == nil && i.VendorID == vendorID && i.ID == id }
+}
+
+type memoryFoodCourts struct {
+	table *memoryTable[models.FoodCourt]
+}
+
+func (r *memoryFoodCourts) FindByID(ctx context.Context, id primitive.ObjectID) (models.FoodCourt, error) {
+	return r.table.find(func(f models.FoodCourt) bool { return f.DeletedAt == nil && f.ID == id })
+}
+
+func (r *memoryFoodCourts) List(ctx context.Context, query FoodCourtQuery) ([]models.FoodCourt, error) {
+	match, err := foodCourtMatch(query)
+	if err != nil {
+		return nil, err
+	}
+	return r.table.list(match), nil
+}
+
+func (r *memoryFoodCourts) ListPage(ctx context.Context, query FoodCourtQuery, skip, limit int64) ([]models.FoodCourt, error) {
+	foodCourts, err := r.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pageNewestFirst(foodCourts, func(f models.FoodCourt) time.Time { return f.CreatedAt }, skip, limit), nil
+}
+
+func (r *memoryFoodCourts) Count(ctx context.Context, query FoodCourtQuery) (int64, error) {
+	foodCourts, err := r.List(ctx, query)
+	return int64(len(foodCourts)), err
+}
+
+func (r *memoryFoodCourts) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.FoodCourt, error) {
+	return r.table.list(func(f models.FoodCourt) bool {
+		return f.DeletedAt == nil && slices.Contains(f.VendorIDs, vendorID)
+	}), nil
+}
+
+func (r *memoryFoodCourts) ListScheduled(ctx context.Context) ([]models.FoodCourt, error) {
+	return r.table.list(func(f models.FoodCourt) bool { return f.DeletedAt == nil && f.Schedule != nil }), nil
+}
+
+func (r *memoryFoodCourts) NameTaken(ctx context.Context, adminID primitive.ObjectID, name string) (bool, error) {
+	return r.table.count(func(f models.FoodCourt) bool { return f.AdminID == adminID && f.Name == name }) > 0, nil
+}
+
+func (r *memoryFoodCourts) Create(ctx context.Context, foodCourt *models.FoodCourt) error {
+	return r.table.insert(foodCourt)
+}
+
+func (r *memoryFoodCourts) Update(ctx context.Context, id primitive.ObjectID, fields Fields, unset ...string) error {
+	return r.table.update(r.live(id), fields, unset...)
+}
+
+func (r *memoryFoodCourts) AddVendor(ctx context.Context, id, vendorID primitive.ObjectID) error {
+	foodCourt, err := r.table.find(r.live(id))
+	if err != nil {
+		return err
+	}
+	return r.table.update(r.live(id), Fields{
+		"vendor_ids": append(foodCourt.VendorIDs, vendorID),
+		"updatedAt":  time.Now(),
+	})
+}
+
+func (r *memoryFoodCourts) SetOpen(ctx context.Context, id primitive.ObjectID, open bool) error {
+	live := r.live(id)
+	return r.table.update(func(f models.FoodCourt) bool { return live(f) && f.IsOpen != open }, Fields{
+		"isOpen":    open,
+		"updatedAt": time.Now(),
+	})
+}
+
+func (r *memoryFoodCourts) live(id primitive.ObjectID) func(models.FoodCourt) bool {
+	return func(f models.FoodCourt) bool { return f.DeletedAt == nil && f.ID == id }
+}
+
+type memoryItemFoodCourts struct {
+	table *memoryTable[models.ItemFoodCourt]
+}
+
+func (r *memoryItemFoodCourts) FindByID(ctx context.Context, id primitive.ObjectID) (models.ItemFoodCourt, error) {
+	return r.table.find(func(l models.ItemFoodCourt) bool { return l.ID == id })
+}
+
+func (r *memoryItemFoodCourts) Find(ctx context.Context, itemID, foodCourtID primitive.ObjectID) (models.ItemFoodCourt, error) {
+	return r.table.find(func(l models.ItemFoodCourt) bool { return l.ItemID == itemID && l.FoodCourtID == foodCourtID })
+}
+
+func (r *memoryItemFoodCourts) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return r.table.list(func(l models.ItemFoodCourt) bool { return slices.Contains(ids, l.ID) }), nil
+}
+
+func (r *memoryItemFoodCourts) ListByItem(ctx context.Context, itemID primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return r.table.list(func(l models.ItemFoodCourt) bool { return l.ItemID == itemID }), nil
+}
+
+func (r *memoryItemFoodCourts) ListByItems(ctx context.Context, itemIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return r.table.list(func(l models.ItemFoodCourt) bool { return slices.Contains(itemIDs, l.ItemID) }), nil
+}
+
+func (r *memoryItemFoodCourts) ListByFoodCourts(ctx context.Context, foodCourtIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return r.table.list(func(l models.ItemFoodCourt) bool { return slices.Contains(foodCourtIDs, l.FoodCourtID) }), nil
+}
+
+func (r *memoryItemFoodCourts) ListRecent(ctx context.Context, limit int64) ([]models.ItemFoodCourt, error) {
+	listings := r.table.list(func(models.ItemFoodCourt) bool { return true })
+	return pageNewestFirst(listings, func(l models.ItemFoodCourt) time.Time { return l.CreatedAt }, 0, limit), nil
+}
+
+func (r *memoryItemFoodCourts) Create(ctx context.Context, listing *models.ItemFoodCourt) error {
+	return r.table.insert(listing)
+}
+
+func (r *memoryItemFoodCourts) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return r.table.update(func(l models.ItemFoodCourt) bool { return l.ID == id }, fields)
+}
+
+func (r *memoryItemFoodCourts) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.table.delete(func(l models.ItemFoodCourt) bool { return l.ID == id })
+	return err
+}
+
+type memoryManagers struct{ table *memoryTable[models.Manager] }
+
+func (r *memoryManagers) List(ctx context.Context) ([]models.Manager, error) {
+	return r.table.list(func(models.Manager) bool { return true }), nil
+}
+
+func (r *memoryManagers) Count(ctx context.Context) (int64, error) {
+	return r.table.count(func(models.Manager) bool { return true }), nil
+}
+
+func (r *memoryManagers) FindForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error) {
+	return r.table.find(func(m models.Manager) bool { return m.VendorID == vendorID && m.ID == id })
+}
+
+func (r *memoryManagers) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Manager, error) {
+	return r.table.list(func(m models.Manager) bool { return m.VendorID == vendorID }), nil
+}
+
+func (r *memoryManagers) CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error) {
+	return r.table.count(func(m models.Manager) bool { return m.VendorID == vendorID }), nil
+}
+
+// ListByUser relies on insertion order standing in for creation order.
+func (r *memoryManagers) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Manager, error) {
+	return r.table.list(func(m models.Manager) bool { return m.UserID == userID }), nil
+}
+
+func (r *memoryManagers) CountByUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.table.count(func(m models.Manager) bool { return m.UserID == userID }), nil
+}
+
+func (r *memoryManagers) Create(ctx context.Context, manager *models.Manager) error {
+	return r.table.insert(manager)
+}
+
+func (r *memoryManagers) UpdateByUser(ctx context.Context, userID primitive.ObjectID, fields Fields) error {
+	return r.table.updateAll(func(m models.Manager) bool { return m.UserID == userID }, fields)
+}
+
+func (r *memoryManagers) UpdateForVendor(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields, unset ...string) (models.Manager, error) {
+	match := func(m models.Manager) bool { return m.VendorID == vendorID && m.ID == id }
+	if err := r.table.update(match, fields, unset...); err != nil {
+		return models.Manager{}, err
+	}
+	return r.table.find(match)
+}
+
+func (r *memoryManagers) DeleteForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error) {
+	return r.table.delete(func(m models.Manager) bool { return m.VendorID == vendorID && m.ID == id })
+}
@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// NewMongo returns repositories backed by db. It only takes collection
+// handles, so it is cheap enough to call per request.
+func NewMongo(db *mongo.Database) *Repositories {
+	return &Repositories{
+		Users:          mongoUsers{db.Collection(UsersCollection)},
+		Vendors:        mongoVendors{db.Collection(VendorsCollection)},
+		Items:          mongoItems{db.Collection(ItemsCollection)},
+		FoodCourts:     mongoFoodCourts{db.Collection(FoodCourtsCollection)},
+		ItemFoodCourts: mongoItemFoodCourts{db.Collection(ItemFoodCourtsCollection)},
+		Managers:       mongoManagers{db.Collection(ManagersCollection)},
+	}
+}
+
+func findOne[T any](ctx context.Context, collection *mongo.Collection, filter bson.M) (T, error) {
+	var doc T
+	err := collection.FindOne(ctx, filter).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		err = ErrNotFound
+	}
+	return doc, err
+}
+
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter bson.M, opts ...*options.FindOptions) ([]T, error) {
+	docs := []T{}
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return docs, err
+	}
+	defer cursor.Close(ctx)
+	err = cursor.All(ctx, &docs)
+	return docs, err
+}
+
+func insertOne(ctx context.Context, collection *mongo.Collection, id *primitive.ObjectID, doc interface{}) error {
+	if id.IsZero() {
+		*id = primitive.NewObjectID()
+	}
+	_, err := collection.InsertOne(ctx, doc)
+	return err
+}
+
+func updateOne(ctx context.Context, collection *mongo.Collection, filter bson.M, update bson.M) error {
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func updateDocument(fields Fields, unset ...string) bson.M {
+	update := bson.M{"$set": bson.M(fields)}
+	if len(unset) > 0 {
+		removed := bson.M{}
+		for _, field := range unset {
+			removed[field] = ""
+		}
+		update["$unset"] = removed
+	}
+	return update
+}
+
+// newestFirst pages through documents by creation, newest first.
+func newestFirst(skip, limit int64) *options.FindOptions {
+	return options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetSkip(skip).SetLimit(limit)
+}
+
+// in matches any of ids. A nil list matches nothing, where $in: null would
+// fail the query.
+func in(ids []primitive.ObjectID) bson.M {
+	if ids == nil {
+		ids = []primitive.ObjectID{}
+	}
+	return bson.M{"$in": ids}
+}
+
+func regexFilter(pattern string) bson.M {
+	return bson.M{"$regex": pattern, "$options": "i"}
+}
+
+func userFilter(query UserQuery) bson.M {
+	filter := bson.M{}
+	if query.IDs != nil {
+		filter["_id"] = in(query.IDs)
+	}
+	if query.Role != "" {
+		filter["$or"] = []bson.M{{"role": query.Role}, {"roles": query.Role}}
+	}
+	if query.Email != "" {
+		filter["email"] = regexFilter(query.Email)
+	}
+	if query.Name != "" {
+		filter["name"] = regexFilter(query.Name)
+	}
+	return models.NotDeleted(filter)
+}
+
+func foodCourtFilter(query FoodCourtQuery) bson.M {
+	filter := bson.M{}
+	if query.IDs != nil {
+		filter["_id"] = in(query.IDs)
+	}
+	if !query.AdminID.IsZero() {
+		filter["admin_id"] = query.AdminID
+	}
+	if query.Name != "" {
+		filter["name"] = regexFilter(query.Name)
+	}
+	if query.OpenOnly {
+		filter["isOpen"] = true
+	}
+	return models.NotDeleted(filter)
+}
+
+type mongoUsers struct{ collection *mongo.Collection }
+
+func (r mongoUsers) FindByID(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	return findOne[models.User](ctx, r.collection, models.NotDeleted(bson.M{"_id": id}))
+}
+
+func (r mongoUsers) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	return findOne[models.User](ctx, r.collection, models.NotDeleted(bson.M{"email": email}))
+}
+
+func (r mongoUsers) List(ctx context.Context, query UserQuery) ([]models.User, error) {
+	return findAll[models.User](ctx, r.collection, userFilter(query))
+}
+
+func (r mongoUsers) ListPage(ctx context.Context, query UserQuery, skip, limit int64) ([]models.User, error) {
+	return findAll[models.User](ctx, r.collection, userFilter(query), newestFirst(skip, limit))
+}
+
+func (r mongoUsers) Count(ctx context.Context, query UserQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, userFilter(query))
+}
+
+func (r mongoUsers) EmailInUse(ctx context.Context, email string, except primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email, "_id": bson.M{"$ne": except}})
+	return count > 0, err
+}
+
+func (r mongoUsers) Create(ctx context.Context, user *models.User) error {
+	return insertOne(ctx, r.collection, &user.ID, user)
+}
+
+func (r mongoUsers) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id}), updateDocument(fields))
+}
+
+func (r mongoUsers) UpdateWithEmail(ctx context.Context, id primitive.ObjectID, email string, fields Fields) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id, "email": email}), updateDocument(fields))
+}
+
+type mongoVendors struct{ collection *mongo.Collection }
+
+func (r mongoVendors) FindByID(ctx context.Context, id primitive.ObjectID) (models.Vendor, error) {
+	return findOne[models.Vendor](ctx, r.collection, models.NotDeleted(bson.M{"_id": id}))
+}
+
+func (r mongoVendors) FindByUser(ctx context.Context, userID primitive.ObjectID) (models.Vendor, error) {
+	return findOne[models.Vendor](ctx, r.collection, models.NotDeleted(bson.M{"user_id": userID}))
+}
+
+func (r mongoVendors) List(ctx context.Context) ([]models.Vendor, error) {
+	return findAll[models.Vendor](ctx, r.collection, models.NotDeleted(bson.M{}))
+}
+
+func (r mongoVendors) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Vendor, error) {
+	return findAll[models.Vendor](ctx, r.collection, models.NotDeleted(bson.M{"_id": in(ids)}))
+}
+
+func (r mongoVendors) ListByUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]models.Vendor, error) {
+	return findAll[models.Vendor](ctx, r.collection, models.NotDeleted(bson.M{"user_id": in(userIDs)}))
+}
+
+func (r mongoVendors) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, models.NotDeleted(bson.M{}))
+}
+
+func (r mongoVendors) Create(ctx context.Context, vendor *models.Vendor) error {
+	return insertOne(ctx, r.collection, &vendor.ID, vendor)
+}
+
+func (r mongoVendors) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id}), updateDocument(fields))
+}
+
+type mongoItems struct{ collection *mongo.Collection }
+
+func (r mongoItems) FindByID(ctx context.Context, id primitive.ObjectID) (models.Item, error) {
+	return findOne[models.Item](ctx, r.collection, models.NotDeleted(bson.M{"_id": id}))
+}
+
+func (r mongoItems) FindOwned(ctx context.Context, vendorID, id primitive.ObjectID) (models.Item, error) {
+	return findOne[models.Item](ctx, r.collection, models.NotDeleted(bson.M{"_id": id, "vendor_id": vendorID}))
+}
+
+func (r mongoItems) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Item, error) {
+	return findAll[models.Item](ctx, r.collection, models.NotDeleted(bson.M{"_id": in(ids)}))
+}
+
+func (r mongoItems) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Item, error) {
+	return findAll[models.Item](ctx, r.collection, models.NotDeleted(bson.M{"vendor_id": vendorID}))
+}
+
+func (r mongoItems) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, models.NotDeleted(bson.M{}))
+}
+
+func (r mongoItems) CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, models.NotDeleted(bson.M{"vendor_id": vendorID}))
+}
+
+func (r mongoItems) Create(ctx context.Context, item *models.Item) error {
+	return insertOne(ctx, r.collection, &item.ID, item)
+}
+
+func (r mongoItems) UpdateOwned(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id, "vendor_id": vendorID}), updateDocument(fields))
+}
+
+type mongoFoodCourts struct{ collection *mongo.Collection }
+
+func (r mongoFoodCourts) FindByID(ctx context.Context, id primitive.ObjectID) (models.FoodCourt, error) {
+	return findOne[models.FoodCourt](ctx, r.collection, models.NotDeleted(bson.M{"_id": id}))
+}
+
+func (r mongoFoodCourts) List(ctx context.Context, query FoodCourtQuery) ([]models.FoodCourt, error) {
+	return findAll[models.FoodCourt](ctx, r.collection, foodCourtFilter(query))
+}
+
+func (r mongoFoodCourts) ListPage(ctx context.Context, query FoodCourtQuery, skip, limit int64) ([]models.FoodCourt, error) {
+	return findAll[models.FoodCourt](ctx, r.collection, foodCourtFilter(query), newestFirst(skip, limit))
+}
+
+func (r mongoFoodCourts) Count(ctx context.Context, query FoodCourtQuery) (int64, error) {
+	return r.collection.CountDocuments(ctx, foodCourtFilter(query))
+}
+
+func (r mongoFoodCourts) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.FoodCourt, error) {
+	return findAll[models.FoodCourt](ctx, r.collection, models.NotDeleted(bson.M{"vendor_ids": vendorID}))
+}
+
+func (r mongoFoodCourts) ListScheduled(ctx context.Context) ([]models.FoodCourt, error) {
+	return findAll[models.FoodCourt](ctx, r.collection, models.NotDeleted(bson.M{"schedule": bson.M{"$exists": true}}))
+}
+
+func (r mongoFoodCourts) NameTaken(ctx context.Context, adminID primitive.ObjectID, name string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"name": name, "admin_id": adminID})
+	return count > 0, err
+}
+
+func (r mongoFoodCourts) Create(ctx context.Context, foodCourt *models.FoodCourt) error {
+	return insertOne(ctx, r.collection, &foodCourt.ID, foodCourt)
+}
+
+func (r mongoFoodCourts) Update(ctx context.Context, id primitive.ObjectID, fields Fields, unset ...string) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id}), updateDocument(fields, unset...))
+}
+
+func (r mongoFoodCourts) AddVendor(ctx context.Context, id, vendorID primitive.ObjectID) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id}), bson.M{
+		"$push": bson.M{"vendor_ids": vendorID},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+}
+
+func (r mongoFoodCourts) SetOpen(ctx context.Context, id primitive.ObjectID, open bool) error {
+	return updateOne(ctx, r.collection, models.NotDeleted(bson.M{"_id": id, "isOpen": !open}), bson.M{
+		"$set": bson.M{"isOpen": open, "updatedAt": time.Now()},
+	})
+}
+
+type mongoItemFoodCourts struct{ collection *mongo.Collection }
+
+func (r mongoItemFoodCourts) FindByID(ctx context.Context, id primitive.ObjectID) (models.ItemFoodCourt, error) {
+	return findOne[models.ItemFoodCourt](ctx, r.collection, bson.M{"_id": id})
+}
+
+func (r mongoItemFoodCourts) Find(ctx context.Context, itemID, foodCourtID primitive.ObjectID) (models.ItemFoodCourt, error) {
+	return findOne[models.ItemFoodCourt](ctx, r.collection, bson.M{"item_id": itemID, "foodcourt_id": foodCourtID})
+}
+
+func (r mongoItemFoodCourts) ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return findAll[models.ItemFoodCourt](ctx, r.collection, bson.M{"_id": in(ids)})
+}
+
+func (r mongoItemFoodCourts) ListByItem(ctx context.Context, itemID primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return findAll[models.ItemFoodCourt](ctx, r.collection, bson.M{"item_id": itemID})
+}
+
+func (r mongoItemFoodCourts) ListByItems(ctx context.Context, itemIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return findAll[models.ItemFoodCourt](ctx, r.collection, bson.M{"item_id": in(itemIDs)})
+}
+
+func (r mongoItemFoodCourts) ListByFoodCourts(ctx context.Context, foodCourtIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error) {
+	return findAll[models.ItemFoodCourt](ctx, r.collection, bson.M{"foodcourt_id": in(foodCourtIDs)})
+}
+
+func (r mongoItemFoodCourts) ListRecent(ctx context.Context, limit int64) ([]models.ItemFoodCourt, error) {
+	return findAll[models.ItemFoodCourt](ctx, r.collection, bson.M{}, newestFirst(0, limit))
+}
+
+func (r mongoItemFoodCourts) Create(ctx context.Context, listing *models.ItemFoodCourt) error {
+	return insertOne(ctx, r.collection, &listing.ID, listing)
+}
+
+func (r mongoItemFoodCourts) Update(ctx context.Context, id primitive.ObjectID, fields Fields) error {
+	return updateOne(ctx, r.collection, bson.M{"_id": id}, updateDocument(fields))
+}
+
+func (r mongoItemFoodCourts) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type mongoManagers struct{ collection *mongo.Collection }
+
+func (r mongoManagers) List(ctx context.Context) ([]models.Manager, error) {
+	return findAll[models.Manager](ctx, r.collection, bson.M{})
+}
+
+func (r mongoManagers) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
+
+func (r mongoManagers) FindForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error) {
+	return findOne[models.Manager](ctx, r.collection, bson.M{"_id": id, "vendor_id": vendorID})
+}
+
+func (r mongoManagers) ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Manager, error) {
+	return findAll[models.Manager](ctx, r.collection, bson.M{"vendor_id": vendorID})
+}
+
+func (r mongoManagers) CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"vendor_id": vendorID})
+}
+
+func (r mongoManagers) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Manager, error) {
+	return findAll[models.Manager](ctx, r.collection, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+func (r mongoManagers) CountByUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+}
+
+func (r mongoManagers) Create(ctx context.Context, manager *models.Manager) error {
+	return insertOne(ctx, r.collection, &manager.ID, manager)
+}
+
+func (r mongoManagers) UpdateByUser(ctx context.Context, userID primitive.ObjectID, fields Fields) error {
+	result, err := r.collection.UpdateMany(ctx, bson.M{"user_id": userID}, updateDocument(fields))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r mongoManagers) UpdateForVendor(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields, unset ...string) (models.Manager, error) {
+	var manager models.Manager
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "vendor_id": vendorID},
+		updateDocument(fields, unset...),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&manager)
+	if err == mongo.ErrNoDocuments {
+		err = ErrNotFound
+	}
+	return manager, err
+}
+
+func (r mongoManagers) DeleteForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error) {
+	var manager models.Manager
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"_id": id, "vendor_id": vendorID}).Decode(&manager)
+	if err == mongo.ErrNoDocuments {
+		err = ErrNotFound
+	}
+	return manager, err
+}
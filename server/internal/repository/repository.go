@@ -0,0 +1,161 @@
+// Package repository is the data access layer for the core aggregates. Each
+// aggregate has an interface with a MongoDB implementation, used by the
+// server, and an in-memory one, so handlers can be exercised without a
+// database. Reads never return soft-deleted users, vendors, items or food
+// courts.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+)
+
+// Collection names.
+const (
+	UsersCollection          = "users"
+	VendorsCollection        = "vendors"
+	ItemsCollection          = "items"
+	FoodCourtsCollection     = "foodcourts"
+	ItemFoodCourtsCollection = "itemfoodcourts"
+	ManagersCollection       = "managers"
+)
+
+var ErrNotFound = errors.New("document not found")
+
+// Fields are the top-level fields an update sets, keyed by their bson
+// names.
+type Fields map[string]interface{}
+
+// UserQuery narrows a listing of users. Zero fields match everyone; a
+// non-nil IDs matches only those users. Email and Name are
+// case-insensitive regular expressions, and Role matches the primary role
+// or any other the user holds.
+type UserQuery struct {
+	IDs   []primitive.ObjectID
+	Role  string
+	Email string
+	Name  string
+}
+
+type Users interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	List(ctx context.Context, query UserQuery) ([]models.User, error)
+	// ListPage returns one page of the matching users, newest first.
+	ListPage(ctx context.Context, query UserQuery, skip, limit int64) ([]models.User, error)
+	Count(ctx context.Context, query UserQuery) (int64, error)
+	// EmailInUse reports whether anyone but except has the email. Trashed
+	// users count, since they keep their address until they are purged.
+	EmailInUse(ctx context.Context, email string, except primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, id primitive.ObjectID, fields Fields) error
+	// UpdateWithEmail updates the user only while they still have email,
+	// so a link sent to an old address cannot act on a new one.
+	UpdateWithEmail(ctx context.Context, id primitive.ObjectID, email string, fields Fields) error
+}
+
+type Vendors interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.Vendor, error)
+	FindByUser(ctx context.Context, userID primitive.ObjectID) (models.Vendor, error)
+	List(ctx context.Context) ([]models.Vendor, error)
+	ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Vendor, error)
+	ListByUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]models.Vendor, error)
+	Count(ctx context.Context) (int64, error)
+	Create(ctx context.Context, vendor *models.Vendor) error
+	Update(ctx context.Context, id primitive.ObjectID, fields Fields) error
+}
+
+// Items are always reached through the vendor that owns them, so a vendor
+// can never read or change another vendor's item.
+type Items interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.Item, error)
+	FindOwned(ctx context.Context, vendorID, id primitive.ObjectID) (models.Item, error)
+	ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Item, error)
+	ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Item, error)
+	Count(ctx context.Context) (int64, error)
+	CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error)
+	Create(ctx context.Context, item *models.Item) error
+	UpdateOwned(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields) error
+}
+
+// FoodCourtQuery narrows a listing of food courts the way UserQuery does
+// for users. A zero AdminID matches every admin's courts.
+type FoodCourtQuery struct {
+	IDs      []primitive.ObjectID
+	AdminID  primitive.ObjectID
+	Name     string
+	OpenOnly bool
+}
+
+type FoodCourts interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.FoodCourt, error)
+	List(ctx context.Context, query FoodCourtQuery) ([]models.FoodCourt, error)
+	// ListPage returns one page of the matching food courts, newest first.
+	ListPage(ctx context.Context, query FoodCourtQuery, skip, limit int64) ([]models.FoodCourt, error)
+	Count(ctx context.Context, query FoodCourtQuery) (int64, error)
+	// ListByVendor returns the food courts the vendor has a stall in.
+	ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.FoodCourt, error)
+	// ListScheduled returns the courts that open and close on a schedule.
+	ListScheduled(ctx context.Context) ([]models.FoodCourt, error)
+	// NameTaken reports whether the admin already has a court called name.
+	// Trashed courts keep their name until they are purged.
+	NameTaken(ctx context.Context, adminID primitive.ObjectID, name string) (bool, error)
+	Create(ctx context.Context, foodCourt *models.FoodCourt) error
+	// Update sets fields and removes the unset ones.
+	Update(ctx context.Context, id primitive.ObjectID, fields Fields, unset ...string) error
+	// AddVendor gives the vendor a stall in the court.
+	AddVendor(ctx context.Context, id, vendorID primitive.ObjectID) error
+	// SetOpen opens or closes the court. It returns ErrNotFound when the
+	// court is already that way, so schedulers racing on it flip it once.
+	SetOpen(ctx context.Context, id primitive.ObjectID, open bool) error
+}
+
+// ItemFoodCourts are the listings of items in food courts.
+type ItemFoodCourts interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.ItemFoodCourt, error)
+	Find(ctx context.Context, itemID, foodCourtID primitive.ObjectID) (models.ItemFoodCourt, error)
+	ListByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.ItemFoodCourt, error)
+	ListByItem(ctx context.Context, itemID primitive.ObjectID) ([]models.ItemFoodCourt, error)
+	ListByItems(ctx context.Context, itemIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error)
+	ListByFoodCourts(ctx context.Context, foodCourtIDs []primitive.ObjectID) ([]models.ItemFoodCourt, error)
+	// ListRecent returns the newest listings, at most limit of them.
+	ListRecent(ctx context.Context, limit int64) ([]models.ItemFoodCourt, error)
+	Create(ctx context.Context, listing *models.ItemFoodCourt) error
+	Update(ctx context.Context, id primitive.ObjectID, fields Fields) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// Managers are changed through the vendor they work for, except for the
+// contact details and availability the person shares across vendors.
+type Managers interface {
+	// List returns every record, for admins.
+	List(ctx context.Context) ([]models.Manager, error)
+	Count(ctx context.Context) (int64, error)
+	FindForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error)
+	ListByVendor(ctx context.Context, vendorID primitive.ObjectID) ([]models.Manager, error)
+	CountByVendor(ctx context.Context, vendorID primitive.ObjectID) (int64, error)
+	// ListByUser returns the user's records, one per vendor, oldest first.
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Manager, error)
+	CountByUser(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	Create(ctx context.Context, manager *models.Manager) error
+	// UpdateByUser sets fields on every record of the user.
+	UpdateByUser(ctx context.Context, userID primitive.ObjectID, fields Fields) error
+	// UpdateForVendor sets fields, removes the unset ones and returns the
+	// updated record.
+	UpdateForVendor(ctx context.Context, vendorID, id primitive.ObjectID, fields Fields, unset ...string) (models.Manager, error)
+	DeleteForVendor(ctx context.Context, vendorID, id primitive.ObjectID) (models.Manager, error)
+}
+
+// Repositories bundles one repository per aggregate.
+type Repositories struct {
+	Users          Users
+	Vendors        Vendors
+	Items          Items
+	FoodCourts     FoodCourts
+	ItemFoodCourts ItemFoodCourts
+	Managers       Managers
+}
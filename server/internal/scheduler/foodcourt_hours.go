@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/models"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+	"github.com/MohdMusaiyab/infybyte/server/internal/utils"
+)
+
+const foodCourtHoursInterval = 30 * time.Second
+
+// StartFoodCourtHours opens and closes food courts that have a schedule,
+// checking every 30 seconds until ctx is cancelled. Each flip is a
+// conditional update on the old isOpen value, so several replicas running
+// the scheduler broadcast each change only once.
+func StartFoodCourtHours(ctx context.Context, repos *repository.Repositories) {
+	go func() {
+		ticker := time.NewTicker(foodCourtHoursInterval)
+		defer ticker.Stop()
+
+		syncFoodCourtHours(ctx, repos)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				syncFoodCourtHours(ctx, repos)
+			}
+		}
+	}()
+}
+
+func syncFoodCourtHours(ctx context.Context, repos *repository.Repositories) {
+	foodCourts, err := repos.FoodCourts.ListScheduled(ctx)
+	if err != nil {
+		log.Printf("Scheduler: failed to load food courts: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, foodCourt := range foodCourts {
+		if foodCourt.Schedule == nil {
+			continue
+		}
+
+		shouldBeOpen := foodCourt.Schedule.IsOpenAt(now.In(models.FoodCourtLocation(foodCourt.Timezone)))
+		if shouldBeOpen == foodCourt.IsOpen {
+			continue
+		}
+
+		err := repos.FoodCourts.SetOpen(ctx, foodCourt.ID, shouldBeOpen)
+		if err == repository.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			log.Printf("Scheduler: failed to update food court %s: %v", foodCourt.ID.Hex(), err)
+			continue
+		}
+
+		foodCourt.IsOpen = shouldBeOpen
+		utils.BroadcastFoodCourtUpdate(utils.NewFoodCourtEvent(foodCourt), "update")
+		log.Printf("Scheduler: food court %s is now open=%t", foodCourt.ID.Hex(), shouldBeOpen)
+	}
+}
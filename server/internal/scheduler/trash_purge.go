@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/cascade"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
+)
+
+const trashPurgeInterval = time.Hour
+
+// PurgeFunc hard-deletes everything trashed before the cutoff.
+type PurgeFunc func(ctx context.Context, db *mongo.Database, repos *repository.Repositories, before time.Time) (*cascade.Report, error)
+
+// StartTrashPurge deletes records that have been in the trash for longer
+// than retention, checking every hour until ctx is cancelled. Every record
+// is deleted in its own transaction, so replicas racing on the same record
+// simply find it gone.
+func StartTrashPurge(ctx context.Context, db *mongo.Database, repos *repository.Repositories, retention time.Duration, purge PurgeFunc) {
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+
+		purgeTrash(ctx, db, repos, retention, purge)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purgeTrash(ctx, db, repos, retention, purge)
+			}
+		}
+	}()
+}
+
+func purgeTrash(ctx context.Context, db *mongo.Database, repos *repository.Repositories, retention time.Duration, purge PurgeFunc) {
+	report, err := purge(ctx, db, repos, time.Now().Add(-retention))
+	if err != nil {
+		log.Printf("Scheduler: failed to purge trash: %v", err)
+	}
+	if report != nil && len(report.Deleted) > 0 {
+		log.Printf("Scheduler: purged trash %v", report.Deleted)
+	}
+}
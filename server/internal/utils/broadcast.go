@@ -1,43 +1,162 @@
 package utils
 
 import (
-	"encoding/json"
 	"log"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"github.com/MohdMusaiyab/infybyte/server/internal/models"
 	"github.com/MohdMusaiyab/infybyte/server/internal/websocket"
 )
 
-var websocketHub *websocket.Hub
+var backplane websocket.Backplane
+
+type BroadcastMessage = websocket.BroadcastMessage
+
+// SetBackplane sets where broadcasts are published. The hub of every
+// instance subscribes to the same backplane.
+func SetBackplane(bp websocket.Backplane) {
+	backplane = bp
+}
+
+func publish(message BroadcastMessage, topics ...string) bool {
+	if backplane == nil {
+		log.Println("WebSocket backplane not initialized")
+		return false
+	}
+
+	for _, topic := range topics {
+		if err := backplane.Publish(topic, message); err != nil {
+			log.Printf("Error publishing to %s: %v", topic, err)
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	EventItemFoodCourtUpdate   = "item_foodcourt_update"
+	EventItemUpdate            = "item_update"
+	EventFoodCourtUpdate       = "foodcourt_update"
+	EventFoodCourtVendorUpdate = "foodcourt_vendor_update"
+	EventOrderUpdate           = "order_update"
+)
 
-type BroadcastMessage struct {
-	Type    string      `json:"type"`
-	Payload interface{} `json:"payload"`
-	Action  string      `json:"action"`
+// ItemFoodCourtEvent is an ItemFoodCourt plus what a menu needs to render
+// it, so clients can apply the change without refetching.
+type ItemFoodCourtEvent struct {
+	models.ItemFoodCourt
+	ItemName       string             `json:"itemName"`
+	Category       string             `json:"category"`
+	IsVeg          bool               `json:"isVeg"`
+	BasePrice      float64            `json:"basePrice"`
+	EffectivePrice float64            `json:"effectivePrice"`
+	VendorID       primitive.ObjectID `json:"vendorId"`
+	ShopName       string             `json:"shopName"`
 }
 
-func SetWebSocketHub(hub *websocket.Hub) {
-	websocketHub = hub
+type ItemEvent struct {
+	models.Item
+	ShopName     string               `json:"shopName"`
+	FoodCourtIDs []primitive.ObjectID `json:"foodCourtIds"`
 }
 
-func BroadcastItemFoodCourtUpdate(itemFoodCourt models.ItemFoodCourt, action string) {
-	if websocketHub == nil {
-		log.Println("WebSocket hub not initialized")
-		return
+type FoodCourtEvent struct {
+	ID       primitive.ObjectID      `json:"id"`
+	Name     string                  `json:"name"`
+	Location string                  `json:"location"`
+	Timings  string                  `json:"timings,omitempty"`
+	Timezone string                  `json:"timezone,omitempty"`
+	Schedule *models.OpeningSchedule `json:"schedule,omitempty"`
+	IsOpen   bool                    `json:"isOpen"`
+}
+
+// NewFoodCourtEvent copies the fields clients render from foodCourt.
+func NewFoodCourtEvent(foodCourt models.FoodCourt) FoodCourtEvent {
+	return FoodCourtEvent{
+		ID:       foodCourt.ID,
+		Name:     foodCourt.Name,
+		Location: foodCourt.Location,
+		Timings:  foodCourt.Timings,
+		Timezone: foodCourt.Timezone,
+		Schedule: foodCourt.Schedule,
+		IsOpen:   foodCourt.IsOpen,
 	}
+}
+
+type FoodCourtVendorEvent struct {
+	FoodCourtID   primitive.ObjectID `json:"foodCourtId"`
+	FoodCourtName string             `json:"foodCourtName"`
+	VendorID      primitive.ObjectID `json:"vendorId"`
+	ShopName      string             `json:"shopName"`
+}
+
+func BroadcastItemFoodCourtUpdate(event ItemFoodCourtEvent, action string) {
+	message := BroadcastMessage{
+		Type:    EventItemFoodCourtUpdate,
+		Payload: event,
+		Action:  action,
+	}
+
+	if publish(message, websocket.FoodCourtTopic(event.FoodCourtID.Hex())) {
+		log.Printf("Broadcasted ItemFoodCourt update: %s (ID: %s)", action, event.ID.Hex())
+	}
+}
 
+// BroadcastItemUpdate reaches every food court listing the item and the
+// vendor's own staff.
+func BroadcastItemUpdate(event ItemEvent, action string) {
 	message := BroadcastMessage{
-		Type:    "item_foodcourt_update",
-		Payload: itemFoodCourt,
+		Type:    EventItemUpdate,
+		Payload: event,
 		Action:  action,
 	}
 
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshaling broadcast message: %v", err)
-		return
+	topics := []string{websocket.VendorTopic(event.VendorID.Hex())}
+	for _, foodCourtID := range event.FoodCourtIDs {
+		topics = append(topics, websocket.FoodCourtTopic(foodCourtID.Hex()))
 	}
 
-	websocketHub.Broadcast <- messageBytes
-	log.Printf("Broadcasted ItemFoodCourt update: %s (ID: %s)", action, itemFoodCourt.ID.Hex())
+	if publish(message, topics...) {
+		log.Printf("Broadcasted Item update: %s (ID: %s)", action, event.ID.Hex())
+	}
+}
+
+// BroadcastFoodCourtUpdate also goes to every user so court lists can show
+// openings and closings live.
+func BroadcastFoodCourtUpdate(event FoodCourtEvent, action string) {
+	message := BroadcastMessage{
+		Type:    EventFoodCourtUpdate,
+		Payload: event,
+		Action:  action,
+	}
+
+	if publish(message, websocket.FoodCourtTopic(event.ID.Hex()), websocket.RoleTopic("user")) {
+		log.Printf("Broadcasted FoodCourt update: %s (ID: %s)", action, event.ID.Hex())
+	}
+}
+
+func BroadcastFoodCourtVendorUpdate(event FoodCourtVendorEvent, action string) {
+	message := BroadcastMessage{
+		Type:    EventFoodCourtVendorUpdate,
+		Payload: event,
+		Action:  action,
+	}
+
+	if publish(message, websocket.FoodCourtTopic(event.FoodCourtID.Hex()), websocket.VendorTopic(event.VendorID.Hex())) {
+		log.Printf("Broadcasted FoodCourt vendor update: %s (FoodCourt: %s, Vendor: %s)", action, event.FoodCourtID.Hex(), event.VendorID.Hex())
+	}
+}
+
+// BroadcastOrderUpdate tells the kitchen and the customer about an order.
+func BroadcastOrderUpdate(order models.Order, action string) {
+	message := BroadcastMessage{
+		Type:    EventOrderUpdate,
+		Payload: order,
+		Action:  action,
+	}
+
+	if publish(message, websocket.VendorTopic(order.VendorID.Hex()), websocket.UserTopic(order.UserID.Hex())) {
+		log.Printf("Broadcasted Order update: %s (ID: %s)", action, order.ID.Hex())
+	}
 }
@@ -1,86 +1,187 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+const (
+	tokenUseAccess  = "access"
+	tokenUseRefresh = "refresh"
 )
 
 var (
-	accessSecret  = []byte(os.Getenv("ACCESS_SECRET"))
-	refreshSecret = []byte(os.Getenv("REFRESH_SECRET"))
+	accessSecret  []byte
+	refreshSecret []byte
+	tokenKeys     *keySet
 )
 
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    string   `json:"user_id"`
+	Role      string   `json:"role"`
+	Roles     []string `json:"roles,omitempty"` // Access tokens only
+	TokenUse  string   `json:"token_use,omitempty"`
+	SessionID string   `json:"sid,omitempty"` // Refresh tokens only; the ID is the RegisteredClaims jti
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID, role string) (string, error) {
+// InitJWT loads the token keys. It must run after the environment is
+// loaded.
+//
+// With JWT_SIGNING_KEY_FILE (an RSA or Ed25519 private key in PEM) access
+// tokens are signed RS256 or EdDSA with the key's thumbprint as kid. Keys
+// listed in JWT_VERIFICATION_KEY_FILES are still accepted and published in
+// the JWKS, so a retired key keeps working until its tokens expire.
+// ACCESS_SECRET signs HS256 access tokens when there is no signing key, and
+// while set it also keeps verifying HS256 tokens issued before a switch.
+//
+// Refresh tokens are always HS256 on REFRESH_SECRET. Only this server reads
+// them, and signing them with a published key would let any service that
+// trusts the JWKS accept one as an access token.
+func InitJWT() error {
+	accessSecret = []byte(os.Getenv("ACCESS_SECRET"))
+	refreshSecret = []byte(os.Getenv("REFRESH_SECRET"))
+
+	var verificationFiles []string
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			verificationFiles = append(verificationFiles, path)
+		}
+	}
+
+	keys, err := loadKeySet(os.Getenv("JWT_SIGNING_KEY_FILE"), verificationFiles)
+	if err != nil {
+		return err
+	}
+	tokenKeys = keys
+
+	if len(refreshSecret) == 0 {
+		return errors.New("REFRESH_SECRET is required")
+	}
+	if tokenKeys.signer == nil && len(accessSecret) == 0 {
+		return errors.New("set JWT_SIGNING_KEY_FILE or ACCESS_SECRET")
+	}
+	if string(accessSecret) == string(refreshSecret) {
+		return errors.New("ACCESS_SECRET and REFRESH_SECRET must differ")
+	}
+	return nil
+}
+
+func signToken(claims JWTClaims) (string, error) {
+	if claims.TokenUse == tokenUseRefresh {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(refreshSecret)
+	}
+
+	if tokenKeys != nil && tokenKeys.signer != nil {
+		signer := tokenKeys.signer
+		token := jwt.NewWithClaims(signer.method, claims)
+		token.Header["kid"] = signer.id
+		return token.SignedString(signer.private)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(accessSecret)
+}
+
+func GenerateAccessToken(userID, role string, roles []string) (string, error) {
 	claims := JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:   userID,
+		Role:     role,
+		Roles:    roles,
+		TokenUse: tokenUseAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(accessSecret)
+	return signToken(claims)
 }
 
-func GenerateRefreshToken(userID, role string) (string, error) {
+func GenerateRefreshToken(userID, role, sessionID, jti string) (string, error) {
 	claims := JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		TokenUse:  tokenUseRefresh,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(refreshSecret)
+	return signToken(claims)
 }
 
 func ValidateToken(tokenStr string, isRefresh bool) (*JWTClaims, error) {
-	var secret []byte
+	use, secret := tokenUseAccess, accessSecret
 	if isRefresh {
-		secret = refreshSecret
-	} else {
-		secret = accessSecret
+		use, secret = tokenUseRefresh, refreshSecret
 	}
 
 	token, err := jwt.ParseWithClaims(tokenStr, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+			if tokenKeys == nil {
+				return nil, errors.New("no verification keys loaded")
+			}
+			key, ok := tokenKeys.verifiers[kid]
+			if !ok {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+			if token.Method.Alg() != key.method.Alg() {
+				return nil, fmt.Errorf("key %q does not sign %s", kid, token.Method.Alg())
+			}
+			return key.public, nil
+		}
+
+		// Tokens without a kid are HS256 tokens from the shared secrets.
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || len(secret) == 0 {
+			return nil, errors.New("token has no key id")
+		}
 		return secret, nil
-	})
+	}, jwt.WithValidMethods([]string{"RS256", "EdDSA", "HS256"}))
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*JWTClaims)
 	if !ok || !token.Valid {
-		return nil, err
+		return nil, errors.New("invalid token")
+	}
+
+	// Refresh tokens signed with the asymmetric key before they moved to
+	// REFRESH_SECRET share it with access tokens, so the claim is what keeps
+	// one from being used as the other.
+	if claims.TokenUse != use && (claims.TokenUse != "" || token.Header["kid"] != nil) {
+		return nil, errors.New("wrong token type")
 	}
 
 	return claims, nil
 }
 
-func ValidateRefreshToken(tokenStr string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return refreshSecret, nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		return nil, err
+// EffectiveRoles returns the roles an access token grants. Tokens issued
+// before roles were added only carry Role.
+func (c *JWTClaims) EffectiveRoles() []string {
+	if len(c.Roles) == 0 {
+		return permissions.Normalize([]string{c.Role})
 	}
+	return permissions.Normalize(c.Roles)
+}
 
-	return claims, nil
+func ValidateRefreshToken(tokenStr string) (*JWTClaims, error) {
+	return ValidateToken(tokenStr, true)
 }
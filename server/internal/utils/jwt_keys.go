@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+type verificationKey struct {
+	id     string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+type keySet struct {
+	signer    *signingKey
+	verifiers map[string]verificationKey
+	order     []string // JWKS order: signer first, then retired keys
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS returns the public keys that verify tokens issued by this server.
+func JWKS() []JWK {
+	keys := []JWK{}
+	if tokenKeys == nil {
+		return keys
+	}
+	for _, kid := range tokenKeys.order {
+		keys = append(keys, publicJWK(tokenKeys.verifiers[kid]))
+	}
+	return keys
+}
+
+func loadKeySet(signingFile string, verificationFiles []string) (*keySet, error) {
+	keys := &keySet{verifiers: make(map[string]verificationKey)}
+
+	add := func(public crypto.PublicKey) (verificationKey, error) {
+		key, err := newVerificationKey(public)
+		if err != nil {
+			return key, err
+		}
+		if _, exists := keys.verifiers[key.id]; !exists {
+			keys.verifiers[key.id] = key
+			keys.order = append(keys.order, key.id)
+		}
+		return key, nil
+	}
+
+	if signingFile != "" {
+		private, err := readPrivateKey(signingFile)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE: %w", err)
+		}
+		key, err := add(private.Public())
+		if err != nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE: %w", err)
+		}
+		keys.signer = &signingKey{id: key.id, method: key.method, private: private}
+	}
+
+	for _, path := range verificationFiles {
+		public, err := readPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEY_FILES %s: %w", path, err)
+		}
+		if _, err := add(public); err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEY_FILES %s: %w", path, err)
+		}
+	}
+
+	return keys, nil
+}
+
+func newVerificationKey(public crypto.PublicKey) (verificationKey, error) {
+	key := verificationKey{public: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return key, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+	key.id = thumbprint(publicJWK(key))
+	return key, nil
+}
+
+func publicJWK(key verificationKey) JWK {
+	jwk := JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// thumbprint is the RFC 7638 key ID, so every service derives the same kid
+// from the same key.
+func thumbprint(jwk JWK) string {
+	var canonical string
+	switch jwk.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(block)
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// readPublicKey accepts a public key or, for convenience, a private key.
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		private, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, err
+		}
+		return private.Public(), nil
+	}
+}
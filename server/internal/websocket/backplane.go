@@ -0,0 +1,45 @@
+package websocket
+
+import "sync"
+
+// Backplane carries events between server instances. Every instance
+// publishes through it and feeds what it receives into its own Hub, so a
+// client sees the same events whichever replica it is connected to.
+type Backplane interface {
+	Publish(topic string, event BroadcastMessage) error
+	// Subscribe registers the handler called for every event, including
+	// ones published by this instance.
+	Subscribe(handler func(topic string, event BroadcastMessage)) error
+	Close() error
+}
+
+// MemoryBackplane delivers events within a single process. It is the
+// default and is all a single replica needs.
+type MemoryBackplane struct {
+	mutex    sync.RWMutex
+	handlers []func(topic string, event BroadcastMessage)
+}
+
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{}
+}
+
+func (b *MemoryBackplane) Publish(topic string, event BroadcastMessage) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, handler := range b.handlers {
+		handler(topic, event)
+	}
+	return nil
+}
+
+func (b *MemoryBackplane) Subscribe(handler func(topic string, event BroadcastMessage)) error {
+	b.mutex.Lock()
+	b.handlers = append(b.handlers, handler)
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *MemoryBackplane) Close() error {
+	return nil
+}
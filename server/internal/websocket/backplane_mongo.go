@@ -0,0 +1,174 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	mongoBackplaneCollection = "ws_events"
+	mongoBackplaneSize       = 16 * 1024 * 1024
+	mongoBackplaneRetry      = time.Second
+)
+
+type backplaneEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Instance  string             `bson:"instance"`
+	Topic     string             `bson:"topic"`
+	Data      []byte             `bson:"data"` // JSON-encoded BroadcastMessage
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// MongoBackplane shares events through a capped collection that every
+// instance tails. Unlike change streams it works on a standalone mongod,
+// though deletes and other transactional writes still need a replica set.
+// Events from this instance are delivered locally right away and skipped
+// when they come back round. Each hub still numbers what it receives, so
+// frames carry the hub's epoch for resuming clients to hand back.
+type MongoBackplane struct {
+	collection *mongo.Collection
+	instance   string
+	local      *MemoryBackplane
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func NewMongoBackplane(db *mongo.Database) (*MongoBackplane, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := db.CreateCollection(ctx, mongoBackplaneCollection,
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(mongoBackplaneSize))
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists") {
+		return nil, err
+	}
+
+	runCtx, runCancel := context.WithCancel(context.Background())
+	return &MongoBackplane{
+		collection: db.Collection(mongoBackplaneCollection),
+		instance:   generateClientID(),
+		local:      NewMemoryBackplane(),
+		ctx:        runCtx,
+		cancel:     runCancel,
+	}, nil
+}
+
+func (b *MongoBackplane) Publish(topic string, event BroadcastMessage) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	b.local.Publish(topic, event)
+
+	ctx, cancel := context.WithTimeout(b.ctx, 5*time.Second)
+	defer cancel()
+	_, err = b.collection.InsertOne(ctx, backplaneEvent{
+		Instance:  b.instance,
+		Topic:     topic,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+func (b *MongoBackplane) Subscribe(handler func(topic string, event BroadcastMessage)) error {
+	b.local.Subscribe(handler)
+
+	// Only events written from now on are of interest.
+	var latest backplaneEvent
+	err := b.collection.FindOne(b.ctx, bson.M{},
+		options.FindOne().SetSort(bson.M{"$natural": -1})).Decode(&latest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	go b.tail(latest.ID, handler)
+	return nil
+}
+
+// tail delivers events written after last. A restarted cursor resumes
+// after last by _id; when nothing matches yet the cursor dies at once, so
+// the next attempt tails the whole collection and skips what it has seen.
+func (b *MongoBackplane) tail(last primitive.ObjectID, handler func(topic string, event BroadcastMessage)) {
+	resume := true
+	for b.ctx.Err() == nil {
+		filter := bson.M{}
+		if resume && !last.IsZero() {
+			filter = bson.M{"_id": bson.M{"$gt": last}}
+			b.checkBehind(last)
+		}
+
+		cursor, err := b.collection.Find(b.ctx, filter,
+			options.Find().
+				SetSort(bson.M{"$natural": 1}).
+				SetCursorType(options.TailableAwait).
+				SetMaxAwaitTime(5*time.Second))
+		if err != nil {
+			if b.ctx.Err() == nil {
+				log.Printf("Backplane tail error: %v", err)
+			}
+			time.Sleep(mongoBackplaneRetry)
+			continue
+		}
+
+		matched := false
+		for cursor.Next(b.ctx) {
+			matched = true
+			var doc backplaneEvent
+			if err := cursor.Decode(&doc); err != nil {
+				log.Printf("Backplane decode error: %v", err)
+				continue
+			}
+			if bytes.Compare(doc.ID[:], last[:]) <= 0 {
+				continue
+			}
+			last = doc.ID
+			if doc.Instance == b.instance {
+				continue
+			}
+
+			var event BroadcastMessage
+			if err := json.Unmarshal(doc.Data, &event); err != nil {
+				log.Printf("Backplane event decode error: %v", err)
+				continue
+			}
+			handler(doc.Topic, event)
+		}
+		resume = matched
+
+		// A tailable cursor dies when nothing matches or it falls off the
+		// end of the capped collection; wait and start over.
+		if err := cursor.Err(); err != nil && b.ctx.Err() == nil {
+			log.Printf("Backplane cursor error: %v", err)
+		}
+		cursor.Close(context.Background())
+		time.Sleep(mongoBackplaneRetry)
+	}
+}
+
+// checkBehind logs when the oldest event kept is newer than last, meaning
+// events in between rolled out of the capped collection unseen.
+func (b *MongoBackplane) checkBehind(last primitive.ObjectID) {
+	var oldest backplaneEvent
+	err := b.collection.FindOne(b.ctx, bson.M{},
+		options.FindOne().SetSort(bson.M{"$natural": 1})).Decode(&oldest)
+	if err == nil && bytes.Compare(oldest.ID[:], last[:]) > 0 {
+		log.Printf("Backplane fell behind the capped collection; some events were lost")
+	}
+}
+
+func (b *MongoBackplane) Close() error {
+	b.cancel()
+	return nil
+}
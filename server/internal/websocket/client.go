@@ -7,12 +7,17 @@ import (
 )
 
 type Client struct {
-	ID     string
-	UserID string
-	Role   string
-	Send   chan []byte
+	ID        string
+	UserID    string
+	Role      string   // Primary role
+	Roles     []string // Every role the user holds, including Role
+	VendorIDs []string // The vendors a vendor or manager works for
+	Send      chan []byte
 
-	closeOnce sync.Once
+	subscriptions map[string]bool
+	subMutex      sync.RWMutex
+	sendMutex     sync.Mutex
+	closed        bool
 }
 
 func generateClientID() string {
@@ -21,17 +26,78 @@ func generateClientID() string {
 	return hex.EncodeToString(bytes)
 }
 
-func NewClient(userID, role string) *Client {
-	return &Client{
-		ID:     generateClientID(),
-		UserID: userID,
-		Role:   role,
-		Send:   make(chan []byte, 256),
+// NewClient creates a client already subscribed to its own user topic, the
+// topic of each of its roles, and the topic of each vendor in vendorIDs.
+func NewClient(userID, role string, roles []string, vendorIDs []string) *Client {
+	client := &Client{
+		ID:            generateClientID(),
+		UserID:        userID,
+		Role:          role,
+		Roles:         roles,
+		VendorIDs:     vendorIDs,
+		Send:          make(chan []byte, 256),
+		subscriptions: make(map[string]bool),
+	}
+	client.Subscribe(UserTopic(userID))
+	for _, r := range roles {
+		client.Subscribe(RoleTopic(r))
+	}
+	for _, vendorID := range vendorIDs {
+		client.Subscribe(VendorTopic(vendorID))
+	}
+	return client
+}
+
+func (c *Client) Subscribe(topic string) {
+	c.subMutex.Lock()
+	c.subscriptions[topic] = true
+	c.subMutex.Unlock()
+}
+
+func (c *Client) Unsubscribe(topic string) {
+	c.subMutex.Lock()
+	delete(c.subscriptions, topic)
+	c.subMutex.Unlock()
+}
+
+func (c *Client) IsSubscribed(topic string) bool {
+	c.subMutex.RLock()
+	defer c.subMutex.RUnlock()
+	return c.subscriptions[topic]
+}
+
+func (c *Client) Subscriptions() []string {
+	c.subMutex.RLock()
+	defer c.subMutex.RUnlock()
+	topics := make([]string, 0, len(c.subscriptions))
+	for topic := range c.subscriptions {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// Deliver queues data without blocking. It reports false when the buffer
+// is full or the client has already been closed, so the hub and the read
+// loop can both write replies without racing SafeClose.
+func (c *Client) Deliver(data []byte) bool {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		return false
 	}
 }
 
 func (c *Client) SafeClose() {
-	c.closeOnce.Do(func() {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	if !c.closed {
+		c.closed = true
 		close(c.Send)
-	})
+	}
 }
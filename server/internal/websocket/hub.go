@@ -1,31 +1,67 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
 	"sync"
+	"time"
 )
 
+// Message is an event addressed to every client subscribed to Topic.
+type Message struct {
+	Topic string
+	Event BroadcastMessage
+}
+
+// ResumeRequest subscribes Client to Topic and replays what it missed after
+// Since. It goes through Run so no live event can slip in between. Epoch is
+// the one the client's frames carried; Since means nothing under another
+// hub's numbering, so a mismatch asks for a resync instead.
+type ResumeRequest struct {
+	Client *Client
+	Topic  string
+	Since  uint64
+	Epoch  string
+}
+
 type Hub struct {
 	Clients    map[*Client]bool
-	Broadcast  chan []byte
+	Broadcast  chan Message
 	Register   chan *Client
 	Unregister chan *Client
+	Resume     chan ResumeRequest
 	mutex      sync.RWMutex
+	epoch      string
+
+	// Owned by Run. seqs outlives eviction of a topic's replay buffer, so
+	// numbering carries on where the topic stopped.
+	seqs    map[string]uint64
+	replays map[string]*replayBuffer
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		Broadcast:  make(chan []byte),
+		Broadcast:  make(chan Message),
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
+		Resume:     make(chan ResumeRequest),
+		epoch:      generateClientID(),
 		Clients:    make(map[*Client]bool),
+		seqs:       make(map[string]uint64),
+		replays:    make(map[string]*replayBuffer),
 	}
 }
 
 func (h *Hub) Run() {
+	sweep := time.NewTicker(replayWindow)
+	defer sweep.Stop()
+
 	for {
 		select {
 
+		case now := <-sweep.C:
+			h.evictIdle(now)
+
 		case client := <-h.Register:
 			h.mutex.Lock()
 			h.Clients[client] = true
@@ -33,34 +69,141 @@ func (h *Hub) Run() {
 			log.Printf("WebSocket client registered: %s (User: %s, Role: %s)", client.ID, client.UserID, client.Role)
 
 		case client := <-h.Unregister:
-			h.mutex.Lock()
-			if _, exists := h.Clients[client]; exists {
-				delete(h.Clients, client)
-				client.SafeClose()
-			}
-			h.mutex.Unlock()
+			h.removeClient(client)
 			log.Printf("WebSocket client unregistered: %s", client.ID)
 
+		case request := <-h.Resume:
+			h.resume(request)
+
 		case message := <-h.Broadcast:
+			data, ok := h.stamp(message)
+			if !ok {
+				continue
+			}
+
+			var slow []*Client
 
 			h.mutex.RLock()
 			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-
-				default:
-
-					log.Printf("Removing slow client: %s", client.ID)
-					h.mutex.RUnlock()
-					h.Unregister <- client
-					h.mutex.RLock()
+				if !client.IsSubscribed(message.Topic) {
+					continue
+				}
+				if !client.Deliver(data) {
+					slow = append(slow, client)
 				}
 			}
 			h.mutex.RUnlock()
+
+			// Removed here rather than via h.Unregister: Run is the only
+			// reader of that channel, so sending to it from here deadlocks.
+			for _, client := range slow {
+				log.Printf("Removing slow client: %s", client.ID)
+				h.removeClient(client)
+			}
 		}
 	}
 }
 
+// Epoch identifies this hub's sequence numbering. It changes on restart.
+func (h *Hub) Epoch() string {
+	return h.epoch
+}
+
+// Publish queues event for every subscriber of topic.
+func (h *Hub) Publish(topic string, event BroadcastMessage) {
+	h.Broadcast <- Message{Topic: topic, Event: event}
+}
+
+// stamp assigns the next sequence number for the topic, encodes the event
+// and keeps a copy for replay.
+func (h *Hub) stamp(message Message) ([]byte, bool) {
+	seq := h.seqs[message.Topic] + 1
+
+	event := message.Event
+	event.Topic = message.Topic
+	event.Seq = seq
+	event.Epoch = h.epoch
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling broadcast message: %v", err)
+		return nil, false
+	}
+
+	h.seqs[message.Topic] = seq
+	buffer, exists := h.replays[message.Topic]
+	if !exists {
+		buffer = newReplayBuffer()
+		h.replays[message.Topic] = buffer
+	}
+	buffer.add(seq, data)
+
+	return data, true
+}
+
+func (h *Hub) resume(request ResumeRequest) {
+	request.Client.Subscribe(request.Topic)
+
+	last := h.seqs[request.Topic]
+	if request.Epoch != h.epoch {
+		// Numbered by another instance, or by this one before a restart.
+		h.sendResync(request.Client, request.Topic, last)
+		return
+	}
+	if request.Since >= last {
+		if request.Since > last {
+			// The client saw numbers this hub never issued.
+			h.sendResync(request.Client, request.Topic, last)
+		}
+		return
+	}
+
+	buffer, exists := h.replays[request.Topic]
+	if !exists {
+		h.sendResync(request.Client, request.Topic, last)
+		return
+	}
+	frames, ok := buffer.since(request.Since)
+	if !ok {
+		h.sendResync(request.Client, request.Topic, last)
+		return
+	}
+	for _, frame := range frames {
+		if !request.Client.Deliver(frame) {
+			h.sendResync(request.Client, request.Topic, last)
+			return
+		}
+	}
+}
+
+// evictIdle drops the replay buffers of topics that have had no events for
+// replayWindow, so the hub does not keep frames for every topic it has ever
+// seen. Only the last sequence number of each topic stays.
+func (h *Hub) evictIdle(now time.Time) {
+	for topic, buffer := range h.replays {
+		if now.Sub(buffer.updatedAt) >= replayWindow {
+			delete(h.replays, topic)
+		}
+	}
+}
+
+func (h *Hub) sendResync(client *Client, topic string, seq uint64) {
+	data, err := json.Marshal(BroadcastMessage{Type: TypeResyncRequired, Topic: topic, Seq: seq, Epoch: h.epoch})
+	if err != nil {
+		return
+	}
+	client.Deliver(data)
+}
+
+func (h *Hub) removeClient(client *Client) {
+	h.mutex.Lock()
+	if _, exists := h.Clients[client]; exists {
+		delete(h.Clients, client)
+		client.SafeClose()
+	}
+	h.mutex.Unlock()
+}
+
 func (h *Hub) GetConnectedClientsCount() int {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
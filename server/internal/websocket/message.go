@@ -0,0 +1,75 @@
+package websocket
+
+import "time"
+
+// BroadcastMessage is the frame pushed to subscribers. Topic, Seq and
+// Epoch are stamped by the hub; Seq increases by one per topic, so a client
+// that notices a gap knows it missed something. Every instance numbers its
+// events on its own, and Epoch names the hub that issued Seq, so a client
+// resuming with it after landing on another instance is told to resync.
+type BroadcastMessage struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+	Action  string      `json:"action,omitempty"`
+	Topic   string      `json:"topic,omitempty"`
+	Seq     uint64      `json:"seq,omitempty"`
+	Epoch   string      `json:"epoch,omitempty"`
+}
+
+// TypeResyncRequired tells a resuming client that the events it asked for
+// have already left the replay buffer and it should reload from the API.
+const TypeResyncRequired = "resync_required"
+
+const replayBufferSize = 256
+
+// replayWindow is how long a topic's frames are kept after its last event.
+// Clients away for longer get a resync instead of a replay.
+const replayWindow = 10 * time.Minute
+
+type replayEntry struct {
+	seq  uint64
+	data []byte
+}
+
+// replayBuffer is a fixed-size ring of the most recent frames on a topic.
+type replayBuffer struct {
+	entries   []replayEntry
+	next      int
+	full      bool
+	updatedAt time.Time
+}
+
+func newReplayBuffer() *replayBuffer {
+	return &replayBuffer{entries: make([]replayEntry, replayBufferSize)}
+}
+
+func (b *replayBuffer) add(seq uint64, data []byte) {
+	b.entries[b.next] = replayEntry{seq: seq, data: data}
+	b.updatedAt = time.Now()
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// since returns the frames after seq in order. ok is false when frames
+// between seq and the oldest one still held have been overwritten.
+func (b *replayBuffer) since(seq uint64) (frames [][]byte, ok bool) {
+	start, count := 0, b.next
+	if b.full {
+		start, count = b.next, len(b.entries)
+	}
+	if count == 0 {
+		return nil, true
+	}
+	if oldest := b.entries[start].seq; oldest > seq+1 {
+		return nil, false
+	}
+	for i := 0; i < count; i++ {
+		entry := b.entries[(start+i)%len(b.entries)]
+		if entry.seq > seq {
+			frames = append(frames, entry.data)
+		}
+	}
+	return frames, true
+}
@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestReplayBufferSince(t *testing.T) {
+	// fill adds frames first..last, each carrying its own sequence number.
+	fill := func(first, last uint64) *replayBuffer {
+		b := newReplayBuffer()
+		for seq := first; seq <= last; seq++ {
+			b.add(seq, []byte(strconv.FormatUint(seq, 10)))
+		}
+		return b
+	}
+	seqs := func(from, to uint64) []uint64 {
+		var want []uint64
+		for seq := from; seq <= to; seq++ {
+			want = append(want, seq)
+		}
+		return want
+	}
+
+	tests := []struct {
+		name   string
+		buffer *replayBuffer
+		since  uint64
+		want   []uint64
+		wantOK bool
+	}{
+		{"empty buffer", newReplayBuffer(), 7, nil, true},
+		{"from the start", fill(1, 5), 0, seqs(1, 5), true},
+		{"partway", fill(1, 5), 3, seqs(4, 5), true},
+		{"up to date", fill(1, 5), 5, nil, true},
+		{"exactly full", fill(1, replayBufferSize), 0, seqs(1, replayBufferSize), true},
+		{"wrapped, gap just covered", fill(1, replayBufferSize+10), 10, seqs(11, replayBufferSize+10), true},
+		{"wrapped, across the seam", fill(1, replayBufferSize+10), replayBufferSize - 1, seqs(replayBufferSize, replayBufferSize+10), true},
+		{"wrapped, gap overwritten", fill(1, replayBufferSize+10), 9, nil, false},
+		{"wrapped twice", fill(1, 2*replayBufferSize+3), 2 * replayBufferSize, seqs(2*replayBufferSize+1, 2*replayBufferSize+3), true},
+		{"buffer started after the cursor", fill(500, 510), 300, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, ok := tt.buffer.since(tt.since)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %t, want %t", ok, tt.wantOK)
+			}
+			var got []uint64
+			for _, frame := range frames {
+				seq, err := strconv.ParseUint(string(frame), 10, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, seq)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("since(%d) = %v, want %v", tt.since, got, tt.want)
+			}
+		})
+	}
+}
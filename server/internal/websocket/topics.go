@@ -0,0 +1,29 @@
+package websocket
+
+import "strings"
+
+const (
+	TopicFoodCourt = "foodcourt"
+	TopicVendor    = "vendor"
+	TopicUser      = "user"
+	TopicRole      = "role"
+)
+
+func FoodCourtTopic(foodCourtID string) string { return TopicFoodCourt + ":" + foodCourtID }
+func VendorTopic(vendorID string) string       { return TopicVendor + ":" + vendorID }
+func UserTopic(userID string) string           { return TopicUser + ":" + userID }
+func RoleTopic(role string) string             { return TopicRole + ":" + role }
+
+// ParseTopic splits "kind:id" into its parts. ok is false for anything that
+// is not one of the known kinds with a non-empty id.
+func ParseTopic(topic string) (kind, id string, ok bool) {
+	kind, id, found := strings.Cut(topic, ":")
+	if !found || id == "" {
+		return "", "", false
+	}
+	switch kind {
+	case TopicFoodCourt, TopicVendor, TopicUser, TopicRole:
+		return kind, id, true
+	}
+	return "", "", false
+}
@@ -3,37 +3,52 @@ package routes
 import (
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func AdminRoutes(router *gin.RouterGroup, db *mongo.Database) {
+func AdminRoutes(router *gin.RouterGroup, db *mongo.Database, repos *repository.Repositories, limiter *middlewares.RateLimiter) {
 	admin := router.Group("/admin")
-	admin.Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware()) // ✅ Protect all admin routes
+	admin.Use(middlewares.AuthMiddleware(), limiter.Limit("admin"))
+
+	manageUsers := middlewares.RequirePermission(permissions.UsersManage)
+	manageFoodCourts := middlewares.RequirePermission(permissions.FoodCourtManage)
+	readAudit := middlewares.RequirePermission(permissions.AuditRead)
+	manageTrash := middlewares.RequirePermission(permissions.TrashManage)
+	transactional := middlewares.RequireTransactions(db)
 	{
 
-		admin.GET("/users", func(c *gin.Context) { controllers.GetAllUsers(c, db) })
-		admin.PUT("/users/:id/make-vendor", func(c *gin.Context) { controllers.MakeVendor(c, db) })
-		admin.PUT("/users/:id/make-user", func(c *gin.Context) { controllers.MakeUser(c, db) })
-		admin.DELETE("/users/:id", func(c *gin.Context) { controllers.DeleteUser(c, db) })
-
-		admin.GET("/vendors", func(c *gin.Context) { controllers.GetAllVendors(c, db) })
-		admin.GET("/vendors/:id", func(c *gin.Context) { controllers.GetVendorDetails(c, db) })
-		admin.GET("/managers", func(c *gin.Context) { controllers.GetAllManagers(c, db) })
-		admin.PATCH("/vendors/:id/status", func(c *gin.Context) { controllers.UpdateVendorStatus(c, db) })
-
-		admin.GET("/profile", func(c *gin.Context) { controllers.GetAdminProfile(c, db) })
-		admin.PUT("/profile", func(c *gin.Context) { controllers.UpdateAdminProfile(c, db) })
-		admin.GET("/dashboard-stats", func(c *gin.Context) { controllers.GetAdminDashboardStats(c, db) })
-
-		admin.GET("/my-food-courts", func(c *gin.Context) { controllers.GetAllFoodCourtsAdmin(c, db) })
-		admin.GET("/get-food-court-details/:foodCourtId", func(c *gin.Context) { controllers.GetFoodCourtDetailsAdmin(c, db) })
-		admin.POST("/food-courts", func(c *gin.Context) { controllers.CreateFoodCourt(c, db) })
-		admin.POST("/food-courts/:foodCourtId/add-vendor/:vendorId", func(c *gin.Context) { controllers.AddVendorToFoodCourt(c, db) })
-		admin.DELETE("/food-courts/:foodCourtId/remove-vendor/:vendorId", func(c *gin.Context) { controllers.RemoveVendorFromFoodCourt(c, db) })
-		admin.PUT("/food-courts/:foodCourtId", func(c *gin.Context) { controllers.UpdateFoodCourt(c, db) })
-		admin.DELETE("/food-courts/:foodCourtId", func(c *gin.Context) { controllers.DeleteFoodCourt(c, db) })
-
-		admin.GET("/vendor-dropdown", func(c *gin.Context) { controllers.GetVendorDropdown(c, db) })
+		admin.GET("/users", manageUsers, func(c *gin.Context) { controllers.GetAllUsers(c, repos) })
+		admin.PUT("/users/:id/make-vendor", manageUsers, func(c *gin.Context) { controllers.MakeVendor(c, db, repos) })
+		admin.PUT("/users/:id/make-user", manageUsers, transactional, func(c *gin.Context) { controllers.MakeUser(c, db, repos) })
+		admin.DELETE("/users/:id", manageUsers, transactional, func(c *gin.Context) { controllers.DeleteUser(c, db, repos) })
+		admin.GET("/users/:id/sessions", manageUsers, func(c *gin.Context) { controllers.GetUserSessionsAdmin(c, db, repos) })
+		admin.DELETE("/users/:id/sessions", manageUsers, func(c *gin.Context) { controllers.RevokeAllUserSessionsAdmin(c, db) })
+		admin.DELETE("/users/:id/sessions/:sessionId", manageUsers, func(c *gin.Context) { controllers.RevokeUserSessionAdmin(c, db) })
+		admin.POST("/users/:id/unlock", manageUsers, func(c *gin.Context) { controllers.UnlockUserLogin(c, db, repos) })
+		admin.GET("/audit-logs", readAudit, func(c *gin.Context) { controllers.GetAuditLogs(c, db) })
+		admin.GET("/trash", manageTrash, func(c *gin.Context) { controllers.GetTrash(c, db) })
+		admin.POST("/trash/:type/:id/restore", manageTrash, transactional, func(c *gin.Context) { controllers.RestoreTrash(c, db, repos) })
+
+		admin.GET("/vendors", manageUsers, func(c *gin.Context) { controllers.GetAllVendors(c, repos) })
+		admin.GET("/vendors/:id", manageUsers, func(c *gin.Context) { controllers.GetVendorDetails(c, repos) })
+		admin.GET("/managers", manageUsers, func(c *gin.Context) { controllers.GetAllManagers(c, repos) })
+		admin.PATCH("/vendors/:id/status", manageUsers, transactional, func(c *gin.Context) { controllers.UpdateVendorStatus(c, db, repos) })
+
+		admin.GET("/profile", manageUsers, func(c *gin.Context) { controllers.GetAdminProfile(c, repos) })
+		admin.PUT("/profile", manageUsers, func(c *gin.Context) { controllers.UpdateAdminProfile(c, repos) })
+		admin.GET("/dashboard-stats", manageUsers, func(c *gin.Context) { controllers.GetAdminDashboardStats(c, repos) })
+
+		admin.GET("/my-food-courts", manageFoodCourts, func(c *gin.Context) { controllers.GetAllFoodCourtsAdmin(c, repos) })
+		admin.GET("/get-food-court-details/:foodCourtId", manageFoodCourts, func(c *gin.Context) { controllers.GetFoodCourtDetailsAdmin(c, repos) })
+		admin.POST("/food-courts", manageFoodCourts, func(c *gin.Context) { controllers.CreateFoodCourt(c, repos) })
+		admin.POST("/food-courts/:foodCourtId/add-vendor/:vendorId", manageFoodCourts, func(c *gin.Context) { controllers.AddVendorToFoodCourt(c, repos) })
+		admin.DELETE("/food-courts/:foodCourtId/remove-vendor/:vendorId", manageFoodCourts, transactional, func(c *gin.Context) { controllers.RemoveVendorFromFoodCourt(c, db, repos) })
+		admin.PUT("/food-courts/:foodCourtId", manageFoodCourts, func(c *gin.Context) { controllers.UpdateFoodCourt(c, repos) })
+		admin.DELETE("/food-courts/:foodCourtId", manageFoodCourts, transactional, func(c *gin.Context) { controllers.DeleteFoodCourt(c, db, repos) })
+
+		admin.GET("/vendor-dropdown", manageFoodCourts, func(c *gin.Context) { controllers.GetVendorDropdown(c, repos) })
 	}
 }
@@ -2,17 +2,26 @@ package routes
 
 import (
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
+	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func AuthRoutes(router *gin.RouterGroup, db *mongo.Database) {
+func AuthRoutes(router *gin.RouterGroup, db *mongo.Database, repos *repository.Repositories, limiter *middlewares.RateLimiter) {
 	auth := router.Group("/auth")
+	auth.Use(limiter.Limit("auth"))
 	{
-		auth.POST("/register", func(c *gin.Context) { controllers.Register(c, db) })
-		auth.POST("/login", func(c *gin.Context) { controllers.Login(c, db) })
-		auth.POST("/refresh", func(c *gin.Context) { controllers.Refresh(c, db) })
+		auth.POST("/register", func(c *gin.Context) { controllers.Register(c, db, repos) })
+		auth.POST("/login", func(c *gin.Context) { controllers.Login(c, db, repos) })
+		auth.POST("/refresh", func(c *gin.Context) { controllers.Refresh(c, db, repos) })
 		auth.POST("/logout", func(c *gin.Context) { controllers.Logout(c, db) })
+		auth.POST("/logout-all", middlewares.AuthMiddleware(), func(c *gin.Context) { controllers.LogoutAll(c, db) })
+
+		auth.POST("/forgot-password", func(c *gin.Context) { controllers.ForgotPassword(c, db, repos) })
+		auth.POST("/reset-password", func(c *gin.Context) { controllers.ResetPassword(c, db, repos) })
+		auth.POST("/verify-email", func(c *gin.Context) { controllers.VerifyEmail(c, db, repos) })
+		auth.POST("/resend-verification", middlewares.AuthMiddleware(), func(c *gin.Context) { controllers.ResendVerificationEmail(c, db, repos) })
 
 	}
 }
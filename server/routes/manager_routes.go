@@ -6,26 +6,36 @@ import (
 
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 )
 
-func ManagerRoutes(router *gin.RouterGroup, db *mongo.Database) {
+func ManagerRoutes(router *gin.RouterGroup, db *mongo.Database, repos *repository.Repositories, limiter *middlewares.RateLimiter) {
 	manager := router.Group("/manager")
-	manager.Use(middlewares.AuthMiddleware(), middlewares.ManagerMiddleware())
+	manager.Use(middlewares.AuthMiddleware(), limiter.Limit("manager"))
+
+	operateMenu := middlewares.RequirePermission(permissions.MenuOperate)
+	fulfilOrders := middlewares.RequirePermission(permissions.OrdersFulfil)
+	asManager := middlewares.ManagerActor(repos)
+	asActiveManager := middlewares.ActiveManagerActor(repos)
+	transactional := middlewares.RequireTransactions(db)
 	{
 
-		manager.GET("/dashboard", func(c *gin.Context) { controllers.GetManagerDashboard(c, db) })
-		manager.GET("/foodcourts", func(c *gin.Context) { controllers.GetManagerFoodCourts(c, db) })
-		manager.GET("/foodcourts/:id", func(c *gin.Context) { controllers.GetManagerFoodCourtWithItems(c, db) })
-		manager.GET("/foodcourts/:id/items/:itemId", func(c *gin.Context) { controllers.GetManagerFoodCourtItem(c, db) })
-		manager.GET("/items/:itemId", func(c *gin.Context) { controllers.GetManagerItemWithFCAssignments(c, db) })
-		manager.GET("/vendor-items", func(c *gin.Context) { controllers.GetVendorItemsForManager(c, db) })
-		manager.GET("/profile", func(c *gin.Context) { controllers.GetManagerProfile(c, db) })
+		manager.GET("/dashboard", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerDashboard(c, repos) })
+		manager.GET("/foodcourts", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerFoodCourts(c, repos) })
+		manager.GET("/foodcourts/:id", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerFoodCourtWithItems(c, repos) })
+		manager.GET("/foodcourts/:id/items/:itemId", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerFoodCourtItem(c, repos) })
+		manager.GET("/items/:itemId", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerItemWithFCAssignments(c, repos) })
+		manager.GET("/vendor-items", operateMenu, asManager, func(c *gin.Context) { controllers.GetVendorItemsForManager(c, repos) })
+		manager.GET("/profile", operateMenu, asManager, func(c *gin.Context) { controllers.GetManagerProfile(c, repos) })
+		manager.GET("/orders", fulfilOrders, asManager, func(c *gin.Context) { controllers.GetManagerOrders(c, db) })
 
-		manager.PUT("/foodcourt/item/:itemId/status", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.UpdateFoodCourtItemStatus(c, db) })
-		manager.PUT("/foodcourt/item/:itemId", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.UpdateFoodCourtItemByManager(c, db) })
-		manager.POST("/items/:itemId/foodcourt", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.AddItemToManagerFoodCourt(c, db) })
-		manager.PUT("/items/:itemId/foodcourt", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.UpdateItemInManagerFoodCourt(c, db) })
-		manager.DELETE("/items/:itemId/foodcourt", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.RemoveItemFromManagerFoodCourt(c, db) })
-		manager.PUT("/profile", middlewares.ActiveManagerMiddleware(db), func(c *gin.Context) { controllers.UpdateManagerProfile(c, db) })
+		manager.PUT("/foodcourt/item/:itemId/status", operateMenu, asActiveManager, func(c *gin.Context) { controllers.UpdateFoodCourtItemStatus(c, repos) })
+		manager.PUT("/foodcourt/item/:itemId", operateMenu, asActiveManager, func(c *gin.Context) { controllers.UpdateFoodCourtItemByManager(c, repos) })
+		manager.POST("/items/:itemId/foodcourt", operateMenu, asActiveManager, func(c *gin.Context) { controllers.AddItemToManagerFoodCourt(c, repos) })
+		manager.PUT("/items/:itemId/foodcourt", operateMenu, asActiveManager, func(c *gin.Context) { controllers.UpdateItemInManagerFoodCourt(c, repos) })
+		manager.DELETE("/items/:itemId/foodcourt", operateMenu, asActiveManager, func(c *gin.Context) { controllers.RemoveItemFromManagerFoodCourt(c, repos) })
+		manager.PUT("/profile", operateMenu, transactional, asActiveManager, func(c *gin.Context) { controllers.UpdateManagerProfile(c, db, repos) })
+		manager.PATCH("/orders/:id/status", fulfilOrders, asActiveManager, func(c *gin.Context) { controllers.UpdateManagerOrderStatus(c, db, repos) })
 	}
 }
@@ -3,28 +3,35 @@ package routes
 import (
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/handlers"
+	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func InitRoutes(router *gin.Engine, db *mongo.Database, wsHandler *handlers.WebSocketHandler) {
+func InitRoutes(router *gin.Engine, db *mongo.Database, repos *repository.Repositories, wsHandler *handlers.WebSocketHandler, limiter *middlewares.RateLimiter) {
+	// Token verification keys for other services
+	router.GET("/.well-known/jwks.json", controllers.GetJWKS)
+
 	v1 := router.Group("/api/v1")
 	{
 		// Health route
 		v1.GET("/health", func(c *gin.Context) { controllers.HealthCheck(c, db) })
 
 		// Auth routes
-		AuthRoutes(v1, db)
+		AuthRoutes(v1, db, repos, limiter)
 		// Admin Routes
-		AdminRoutes(v1, db)
+		AdminRoutes(v1, db, repos, limiter)
 		// Vendor Routes
-		VendorRoutes(v1, db)
+		VendorRoutes(v1, db, repos, limiter)
 		// User Routes
-		UserRoutes(v1, db)
+		UserRoutes(v1, db, repos, limiter)
 		// Manager Routes
-		ManagerRoutes(v1, db)
+		ManagerRoutes(v1, db, repos, limiter)
 		
 		// WebSocket route under API v1
 		v1.GET("/ws", wsHandler.HandleWebSocket)
+		// Server-Sent Events fallback for networks that block WebSockets
+		v1.GET("/events", wsHandler.HandleEvents)
 	}
 }
\ No newline at end of file
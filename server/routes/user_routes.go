@@ -6,22 +6,44 @@ import (
 
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 )
 
-func UserRoutes(router *gin.RouterGroup, db *mongo.Database) {
+func UserRoutes(router *gin.RouterGroup, db *mongo.Database, repos *repository.Repositories, limiter *middlewares.RateLimiter) {
 	user := router.Group("/user")
-	user.Use(middlewares.AuthMiddleware())
+	user.Use(middlewares.AuthMiddleware(), limiter.Limit("user"))
+
+	manageProfile := middlewares.RequirePermission(permissions.ProfileManage)
+	readMenu := middlewares.RequirePermission(permissions.MenuRead)
+	placeOrders := middlewares.RequirePermission(permissions.OrdersPlace)
+	transactional := middlewares.RequireTransactions(db)
 	{
-		user.GET("/profile", func(c *gin.Context) { controllers.GetUserProfile(c, db) })
-		user.PUT("/profile", func(c *gin.Context) { controllers.UpdateUserProfile(c, db) })
+		user.GET("/profile", manageProfile, func(c *gin.Context) { controllers.GetUserProfile(c, repos) })
+		user.PUT("/profile", manageProfile, func(c *gin.Context) { controllers.UpdateUserProfile(c, db, repos) })
+		user.PUT("/password", manageProfile, func(c *gin.Context) { controllers.ChangePassword(c, db, repos) })
+
+		user.GET("/sessions", manageProfile, func(c *gin.Context) { controllers.GetUserSessions(c, db) })
+		user.DELETE("/sessions", manageProfile, func(c *gin.Context) { controllers.RevokeOtherUserSessions(c, db) })
+		user.DELETE("/sessions/:id", manageProfile, func(c *gin.Context) { controllers.RevokeUserSession(c, db) })
+
+		user.GET("/manager-invites", manageProfile, func(c *gin.Context) { controllers.GetUserManagerInvites(c, db, repos) })
+		user.POST("/manager-invites/:id/accept", manageProfile, transactional, func(c *gin.Context) { controllers.AcceptManagerInvite(c, db, repos) })
+		user.POST("/manager-invites/:id/decline", manageProfile, transactional, func(c *gin.Context) { controllers.DeclineManagerInvite(c, db, repos) })
+
+		user.GET("/foodcourts", readMenu, func(c *gin.Context) { controllers.GetAllFoodCourts(c, repos) })
+		user.GET("/foodcourts/:id", readMenu, func(c *gin.Context) { controllers.GetFoodCourtByID(c, repos) })
+		user.GET("/foodcourts/:id/items", readMenu, func(c *gin.Context) { controllers.GetFoodCourtItems(c, repos) })
+		user.GET("/foodcourts/:id/queue", readMenu, func(c *gin.Context) { controllers.GetFoodCourtQueue(c, db, repos) })
 
-		user.GET("/foodcourts", func(c *gin.Context) { controllers.GetAllFoodCourts(c, db) })
-		user.GET("/foodcourts/:id", func(c *gin.Context) { controllers.GetFoodCourtByID(c, db) })
-		user.GET("/foodcourts/:id/items", func(c *gin.Context) { controllers.GetFoodCourtItems(c, db) })
+		user.GET("/vendors/:id", readMenu, func(c *gin.Context) { controllers.GetVendorProfileByID(c, repos) })
+		user.GET("/vendors/:id/items", readMenu, func(c *gin.Context) { controllers.GetVendorItemsWithFoodCourts(c, repos) })
 
-		user.GET("/vendors/:id", func(c *gin.Context) { controllers.GetVendorProfileByID(c, db) })
-		user.GET("/vendors/:id/items", func(c *gin.Context) { controllers.GetVendorItemsWithFoodCourts(c, db) })
+		user.GET("/items/:id", readMenu, func(c *gin.Context) { controllers.GetItemDetails(c, repos) })
 
-		user.GET("/items/:id", func(c *gin.Context) { controllers.GetItemDetails(c, db) })
+		user.POST("/orders", placeOrders, func(c *gin.Context) { controllers.PlaceOrder(c, db, repos) })
+		user.GET("/orders", placeOrders, func(c *gin.Context) { controllers.GetUserOrders(c, db) })
+		user.GET("/orders/:id", placeOrders, func(c *gin.Context) { controllers.GetUserOrder(c, db) })
+		user.PATCH("/orders/:id/cancel", placeOrders, func(c *gin.Context) { controllers.CancelUserOrder(c, db, repos) })
 	}
 }
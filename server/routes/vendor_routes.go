@@ -6,41 +6,55 @@ import (
 
 	"github.com/MohdMusaiyab/infybyte/server/internal/controllers"
 	"github.com/MohdMusaiyab/infybyte/server/internal/middlewares"
+	"github.com/MohdMusaiyab/infybyte/server/internal/permissions"
+	"github.com/MohdMusaiyab/infybyte/server/internal/repository"
 )
 
-func VendorRoutes(router *gin.RouterGroup, db *mongo.Database) {
+func VendorRoutes(router *gin.RouterGroup, db *mongo.Database, repos *repository.Repositories, limiter *middlewares.RateLimiter) {
 	vendor := router.Group("/vendor")
-	vendor.Use(middlewares.AuthMiddleware(), middlewares.VendorMiddleware())
+	vendor.Use(middlewares.AuthMiddleware(), limiter.Limit("vendor"))
+
+	manageShop := middlewares.RequirePermission(permissions.ShopManage)
+	writeMenu := middlewares.RequirePermission(permissions.MenuWrite)
+	manageManagers := middlewares.RequirePermission(permissions.ManagersManage)
+	fulfilOrders := middlewares.RequirePermission(permissions.OrdersFulfil)
+	asVendor := middlewares.VendorActor(repos)
+	transactional := middlewares.RequireTransactions(db)
 	{
 
-		vendor.GET("/profile", func(c *gin.Context) { controllers.GetVendorProfile(c, db) })
-		vendor.PUT("/profile", func(c *gin.Context) { controllers.UpdateVendorProfile(c, db) })
-		vendor.GET("/profile/:id", func(c *gin.Context) { controllers.GetVendorProfileByID(c, db) })
+		vendor.GET("/profile", manageShop, asVendor, func(c *gin.Context) { controllers.GetVendorProfile(c, repos) })
+		vendor.PUT("/profile", manageShop, asVendor, func(c *gin.Context) { controllers.UpdateVendorProfile(c, repos) })
+		vendor.GET("/profile/:id", manageShop, func(c *gin.Context) { controllers.GetVendorProfileByID(c, repos) })
 
-		vendor.GET("/dashboard", func(c *gin.Context) { controllers.GetVendorDashboardStats(c, db) })
+		vendor.GET("/dashboard", manageShop, asVendor, func(c *gin.Context) { controllers.GetVendorDashboardStats(c, repos) })
 
-		vendor.GET("/foodcourts/:id/items", func(c *gin.Context) { controllers.SingleFoodCourtItems(c, db) })
+		vendor.GET("/foodcourts/:id/items", writeMenu, asVendor, func(c *gin.Context) { controllers.SingleFoodCourtItems(c, repos) })
 
-		vendor.GET("/items", func(c *gin.Context) { controllers.GetVendorItems(c, db) })
-		vendor.POST("/items", func(c *gin.Context) { controllers.CreateItem(c, db) })
-		vendor.GET("/items/:id", func(c *gin.Context) { controllers.GetVendorItem(c, db) })
-		vendor.PUT("/items/:id", func(c *gin.Context) { controllers.UpdateItem(c, db) })
-		vendor.DELETE("/items/:id", func(c *gin.Context) { controllers.DeleteItem(c, db) })
+		vendor.GET("/items", writeMenu, asVendor, func(c *gin.Context) { controllers.GetVendorItems(c, repos) })
+		vendor.POST("/items", writeMenu, asVendor, func(c *gin.Context) { controllers.CreateItem(c, repos) })
+		vendor.GET("/items/:id", writeMenu, asVendor, func(c *gin.Context) { controllers.GetVendorItem(c, repos) })
+		vendor.PUT("/items/:id", writeMenu, asVendor, func(c *gin.Context) { controllers.UpdateItem(c, repos) })
+		vendor.DELETE("/items/:id", writeMenu, transactional, asVendor, func(c *gin.Context) { controllers.DeleteItem(c, db, repos) })
 
-		vendor.GET("/foodcourts", func(c *gin.Context) { controllers.GetVendorFoodCourts(c, db) })
-		vendor.GET("/foodcourt-items", func(c *gin.Context) { controllers.GetVendorFoodCourtItems(c, db) })
-		vendor.POST("/foodcourt-items", func(c *gin.Context) { controllers.CreateFoodCourtItem(c, db) })
-		vendor.PUT("/foodcourt-items/:id", func(c *gin.Context) { controllers.UpdateFoodCourtItem(c, db) })
-		vendor.DELETE("/foodcourt-items", func(c *gin.Context) { controllers.DeleteFoodCourtItem(c, db) })
-		vendor.GET("/items/:id/foodcourts", func(c *gin.Context) { controllers.GetItemFoodCourts(c, db) })
-		vendor.GET("/my-foodcourts", func(c *gin.Context) { controllers.GetVendorFoodCourtsForDisplay(c, db) })
+		vendor.GET("/foodcourts", writeMenu, asVendor, func(c *gin.Context) { controllers.GetVendorFoodCourts(c, repos) })
+		vendor.GET("/foodcourt-items", writeMenu, asVendor, func(c *gin.Context) { controllers.GetVendorFoodCourtItems(c, repos) })
+		vendor.POST("/foodcourt-items", writeMenu, asVendor, func(c *gin.Context) { controllers.CreateFoodCourtItem(c, repos) })
+		vendor.PUT("/foodcourt-items/:id", writeMenu, asVendor, func(c *gin.Context) { controllers.UpdateFoodCourtItem(c, repos) })
+		vendor.DELETE("/foodcourt-items", writeMenu, asVendor, func(c *gin.Context) { controllers.DeleteFoodCourtItem(c, repos) })
+		vendor.GET("/items/:id/foodcourts", writeMenu, asVendor, func(c *gin.Context) { controllers.GetItemFoodCourts(c, repos) })
+		vendor.GET("/my-foodcourts", writeMenu, asVendor, func(c *gin.Context) { controllers.GetVendorFoodCourtsForDisplay(c, repos) })
 
-		vendor.GET("/managers", func(c *gin.Context) { controllers.GetVendorManagers(c, db) })
-		vendor.POST("/managers", func(c *gin.Context) { controllers.AddManager(c, db) })
-		vendor.PUT("/managers/:id", func(c *gin.Context) { controllers.UpdateManager(c, db) })
-		vendor.DELETE("/managers/:id", func(c *gin.Context) { controllers.RemoveManager(c, db) })
-		vendor.GET("/managers/:id", func(c *gin.Context) { controllers.GetVendorManager(c, db) })
+		vendor.GET("/managers", manageManagers, asVendor, func(c *gin.Context) { controllers.GetVendorManagers(c, repos) })
+		vendor.PUT("/managers/:id", manageManagers, asVendor, func(c *gin.Context) { controllers.UpdateManager(c, repos) })
+		vendor.DELETE("/managers/:id", manageManagers, asVendor, func(c *gin.Context) { controllers.RemoveManager(c, db, repos) })
+		vendor.GET("/managers/:id", manageManagers, asVendor, func(c *gin.Context) { controllers.GetVendorManager(c, repos) })
 
-		vendor.GET("/users", func(c *gin.Context) { controllers.GetAllUsersForManager(c, db) })
+		vendor.GET("/manager-invites", manageManagers, asVendor, func(c *gin.Context) { controllers.GetVendorManagerInvites(c, db, repos) })
+		vendor.POST("/manager-invites", manageManagers, asVendor, func(c *gin.Context) { controllers.CreateManagerInvite(c, db, repos) })
+		vendor.DELETE("/manager-invites/:id", manageManagers, asVendor, func(c *gin.Context) { controllers.RevokeManagerInvite(c, db) })
+
+		vendor.GET("/orders", fulfilOrders, asVendor, func(c *gin.Context) { controllers.GetVendorOrders(c, db) })
+		vendor.GET("/orders/:id", fulfilOrders, asVendor, func(c *gin.Context) { controllers.GetVendorOrder(c, db) })
+		vendor.PATCH("/orders/:id/status", fulfilOrders, asVendor, func(c *gin.Context) { controllers.UpdateVendorOrderStatus(c, db, repos) })
 	}
 }